@@ -2,6 +2,7 @@ package baseapp
 
 import (
 	"fmt"
+	"io"
 	"runtime/debug"
 	"strings"
 
@@ -40,12 +41,13 @@ const (
 type BaseApp struct {
 	// initialized on creation
 	Logger     log.Logger
-	name       string               // application name from wrsp.Info
-	cdc        *wire.Codec          // Amino codec
-	db         dbm.DB               // common DB backend
-	cms        sdk.CommitMultiStore // Main (uncached) state
-	router     Router               // handle any kind of message
-	codespacer *sdk.Codespacer      // handle module codespacing
+	name       string                  // application name from wrsp.Info
+	cdc        *wire.Codec             // Amino codec
+	db         dbm.DB                  // common DB backend
+	cms        sdk.CommitMultiStore    // Main (uncached) state
+	router     Router                  // handle any kind of message
+	codespacer *sdk.Codespacer         // handle module codespacing
+	storeKeys  map[string]sdk.StoreKey // mounted stores by name
 
 	// must be set
 	txDecoder   sdk.TxDecoder   // unmarshal []byte into sdk.Tx
@@ -57,6 +59,9 @@ type BaseApp struct {
 	endBlocker       sdk.EndBlocker   // logic to run after all txs, and to determine valset changes
 	addrPeerFilter   sdk.PeerFilter   // filter peers by address and port
 	pubkeyPeerFilter sdk.PeerFilter   // filter peers by public key
+	streaming        StreamingService // notified of state changes while delivering blocks
+	invariants       []invariant      // checked by AssertInvariants
+	invCheckPeriod   int64            // blocks between invariant checks in EndBlock, 0 to disable
 
 	//--------------------
 	// Volatile
@@ -69,6 +74,7 @@ type BaseApp struct {
 	deliverState     *state                  // for DeliverTx
 	valUpdates       []wrsp.Validator        // cached validator changes from DeliverTx
 	signedValidators []wrsp.SigningValidator // absent validators from begin block
+	txIndex          int                     // index of the next DeliverTx in the block, for store tracing
 }
 
 var _ wrsp.Application = (*BaseApp)(nil)
@@ -84,6 +90,7 @@ func NewBaseApp(name string, cdc *wire.Codec, logger log.Logger, db dbm.DB) *Bas
 		cms:        store.NewCommitMultiStore(db),
 		router:     NewRouter(),
 		codespacer: sdk.NewCodespacer(),
+		storeKeys:  make(map[string]sdk.StoreKey),
 		txDecoder:  defaultTxDecoder(cdc),
 	}
 	// Register the undefined & root codespaces, which should not be used by any modules
@@ -108,14 +115,28 @@ func (app *BaseApp) MountStoresIAVL(keys ...*sdk.KVStoreKey) {
 	}
 }
 
+// Mount transient stores, reset on every commit, to the provided keys in the BaseApp multistore
+func (app *BaseApp) MountStoresTransient(keys ...*sdk.TransientStoreKey) {
+	for _, key := range keys {
+		app.MountStore(key, sdk.StoreTypeTransient)
+	}
+}
+
 // Mount a store to the provided key in the BaseApp multistore, using a specified DB
 func (app *BaseApp) MountStoreWithDB(key sdk.StoreKey, typ sdk.StoreType, db dbm.DB) {
 	app.cms.MountStoreWithDB(key, typ, db)
+	app.storeKeys[key.Name()] = key
 }
 
 // Mount a store to the provided key in the BaseApp multistore, using the default DB
 func (app *BaseApp) MountStore(key sdk.StoreKey, typ sdk.StoreType) {
-	app.cms.MountStoreWithDB(key, typ, nil)
+	app.MountStoreWithDB(key, typ, nil)
+}
+
+// SetCommitMultiStoreTracer traces all store operations of delivered blocks
+// to w, see CommitMultiStore.SetTracer
+func (app *BaseApp) SetCommitMultiStoreTracer(w io.Writer) {
+	app.cms.SetTracer(w)
 }
 
 // Set the txDecoder function
@@ -165,13 +186,30 @@ func (app *BaseApp) Router() Router { return app.router }
 
 // load latest application version
 func (app *BaseApp) LoadLatestVersion(mainKey sdk.StoreKey) error {
-	app.cms.LoadLatestVersion()
-	return app.initFromStore(mainKey)
+	return app.LoadLatestVersionAndUpgrade(mainKey, nil)
 }
 
 // load application version
 func (app *BaseApp) LoadVersion(version int64, mainKey sdk.StoreKey) error {
-	app.cms.LoadVersion(version)
+	return app.LoadVersionAndUpgrade(version, mainKey, nil)
+}
+
+// load latest application version, applying upgrades to the mounted stores,
+// e.g. when a module is added to a running chain
+func (app *BaseApp) LoadLatestVersionAndUpgrade(mainKey sdk.StoreKey, upgrades *sdk.StoreUpgrades) error {
+	err := app.cms.LoadLatestVersionAndUpgrade(upgrades)
+	if err != nil {
+		return err
+	}
+	return app.initFromStore(mainKey)
+}
+
+// load application version, applying upgrades to the mounted stores
+func (app *BaseApp) LoadVersionAndUpgrade(version int64, mainKey sdk.StoreKey, upgrades *sdk.StoreUpgrades) error {
+	err := app.cms.LoadVersionAndUpgrade(version, upgrades)
+	if err != nil {
+		return err
+	}
 	return app.initFromStore(mainKey)
 }
 
@@ -257,7 +295,7 @@ func (app *BaseApp) setCheckState(header wrsp.Header) {
 }
 
 func (app *BaseApp) setDeliverState(header wrsp.Header) {
-	ms := app.cms.CacheMultiStore()
+	ms := app.cms.CacheMultiStoreWithListeners()
 	app.deliverState = &state{
 		ms:  ms,
 		ctx: sdk.NewContext(ms, header, false, nil, app.Logger),
@@ -293,6 +331,7 @@ func (app *BaseApp) InitChain(req wrsp.RequestInitChain) (res wrsp.ResponseInitC
 	}
 
 	// Initialize the deliver state and run initChain
+	app.cms.SetTracingContext(sdk.TraceContext{"blockHeight": int64(0)})
 	app.setDeliverState(wrsp.Header{})
 	app.initChainer(app.deliverState.ctx, req) // no error
 
@@ -380,10 +419,15 @@ func (app *BaseApp) BeginBlock(req wrsp.RequestBeginBlock) (res wrsp.ResponseBeg
 	if app.deliverState == nil {
 		app.setDeliverState(req.Header)
 	}
+	app.cms.SetTracingContext(sdk.TraceContext{"blockHeight": req.Header.Height})
+	app.txIndex = 0
 	app.valUpdates = nil
 	if app.beginBlocker != nil {
 		res = app.beginBlocker(app.deliverState.ctx, req)
 	}
+	if app.streaming != nil {
+		app.streaming.ListenBeginBlock(req)
+	}
 	// set the signed validators for addition to context in deliverTx
 	app.signedValidators = req.Validators
 	return
@@ -416,6 +460,14 @@ func (app *BaseApp) CheckTx(txBytes []byte) (res wrsp.ResponseCheckTx) {
 
 // Implements WRSP
 func (app *BaseApp) DeliverTx(txBytes []byte) (res wrsp.ResponseDeliverTx) {
+	if app.cms.TracingEnabled() {
+		app.cms.SetTracingContext(sdk.TraceContext{
+			"blockHeight": app.deliverState.ctx.BlockHeight(),
+			"txIndex":     app.txIndex,
+		})
+	}
+	app.txIndex++
+
 	// Decode the Tx.
 	var result sdk.Result
 	var tx, err = app.txDecoder(txBytes)
@@ -434,7 +486,7 @@ func (app *BaseApp) DeliverTx(txBytes []byte) (res wrsp.ResponseDeliverTx) {
 	}
 
 	// Tell the blockchain engine (i.e. Tendermint).
-	return wrsp.ResponseDeliverTx{
+	res = wrsp.ResponseDeliverTx{
 		Code:      uint32(result.Code),
 		Data:      result.Data,
 		Log:       result.Log,
@@ -442,6 +494,10 @@ func (app *BaseApp) DeliverTx(txBytes []byte) (res wrsp.ResponseDeliverTx) {
 		GasUsed:   result.GasUsed,
 		Tags:      result.Tags,
 	}
+	if app.streaming != nil {
+		app.streaming.ListenDeliverTx(txBytes, res)
+	}
+	return res
 }
 
 // nolint - Mostly for testing
@@ -547,11 +603,13 @@ func (app *BaseApp) runTx(mode runTxMode, txBytes []byte, tx sdk.Tx) (result sdk
 
 // Implements WRSP
 func (app *BaseApp) EndBlock(req wrsp.RequestEndBlock) (res wrsp.ResponseEndBlock) {
+	app.cms.SetTracingContext(sdk.TraceContext{"blockHeight": req.Height})
 	if app.endBlocker != nil {
 		res = app.endBlocker(app.deliverState.ctx, req)
 	} else {
 		res.ValidatorUpdates = app.valUpdates
 	}
+	app.assertInvariantsAtEndBlock(app.deliverState.ctx)
 	return
 }
 
@@ -573,6 +631,12 @@ func (app *BaseApp) Commit() (res wrsp.ResponseCommit) {
 	app.Logger.Debug("Commit synced",
 		"commit", commitID,
 	)
+	if app.streaming != nil {
+		// a failing sink must not halt the chain
+		if err := app.streaming.ListenCommit(commitID); err != nil {
+			app.Logger.Error("Failed to stream state changes", "err", err)
+		}
+	}
 
 	// Reset the Check state to the latest committed
 	// NOTE: safe because Tendermint holds a lock on the mempool for Commit.
@@ -342,6 +342,64 @@ func TestDeliverTx(t *testing.T) {
 	}
 }
 
+// streamRecorder records what a StreamingService is notified of
+type streamRecorder struct {
+	writes  []string
+	commits int
+}
+
+func (sr *streamRecorder) OnWrite(storeKey sdk.StoreKey, key []byte, value []byte, delete bool) {
+	sr.writes = append(sr.writes, fmt.Sprintf("%s/%s=%s", storeKey.Name(), key, value))
+}
+func (sr *streamRecorder) ListenBeginBlock(req wrsp.RequestBeginBlock) {
+	sr.writes = append(sr.writes, "begin")
+}
+func (sr *streamRecorder) ListenDeliverTx(txBytes []byte, res wrsp.ResponseDeliverTx) {
+	sr.writes = append(sr.writes, "tx")
+}
+func (sr *streamRecorder) ListenCommit(commitID sdk.CommitID) error {
+	sr.commits++
+	return nil
+}
+
+// Test that only the writes of delivered blocks are streamed.
+func TestStreaming(t *testing.T) {
+	app := newBaseApp(t.Name())
+
+	capKey := sdk.NewKVStoreKey("main")
+	otherKey := sdk.NewKVStoreKey("other")
+	app.MountStoresIAVL(capKey, otherKey)
+	err := app.LoadLatestVersion(capKey)
+	assert.Nil(t, err)
+
+	recorder := &streamRecorder{}
+	assert.NotNil(t, app.SetStreamingService(recorder, "unknown"))
+	err = app.SetStreamingService(recorder, "main")
+	assert.Nil(t, err)
+
+	app.SetBeginBlocker(func(ctx sdk.Context, req wrsp.RequestBeginBlock) wrsp.ResponseBeginBlock {
+		ctx.KVStore(capKey).Set([]byte("begin"), []byte("1"))
+		ctx.KVStore(otherKey).Set([]byte("begin"), []byte("1"))
+		return wrsp.ResponseBeginBlock{}
+	})
+	app.SetAnteHandler(func(ctx sdk.Context, tx sdk.Tx) (newCtx sdk.Context, res sdk.Result, abort bool) { return })
+	app.Router().AddRoute(msgType, func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		ctx.KVStore(capKey).Set([]byte("tx"), []byte("1"))
+		return sdk.Result{}
+	})
+
+	app.BeginBlock(wrsp.RequestBeginBlock{Header: wrsp.Header{Height: 1}})
+	app.Check(testUpdatePowerTx{})
+	app.Deliver(testUpdatePowerTx{})
+	app.EndBlock(wrsp.RequestEndBlock{})
+	app.Commit()
+
+	// writes of CheckTx and of other stores are not streamed, and writes
+	// are not reported again on commit
+	assert.Equal(t, []string{"main/begin=1", "begin", "main/tx=1"}, recorder.writes)
+	assert.Equal(t, 1, recorder.commits)
+}
+
 func TestSimulateTx(t *testing.T) {
 	app := newBaseApp(t.Name())
 
@@ -684,3 +742,50 @@ func fromJSON(bz []byte, ptr interface{}) {
 		panic(err)
 	}
 }
+
+func TestInvariants(t *testing.T) {
+	app := newBaseApp(t.Name())
+	capKey := sdk.NewKVStoreKey("main")
+	app.MountStoresIAVL(capKey)
+	err := app.LoadLatestVersion(capKey)
+	require.Nil(t, err)
+
+	// the value stored under key must never be "broken"
+	key := []byte("key")
+	app.RegisterInvariant("test/not-broken", func(ctx sdk.Context) error {
+		if string(ctx.KVStore(capKey).Get(key)) == "broken" {
+			return fmt.Errorf("value is broken")
+		}
+		return nil
+	})
+	require.Panics(t, func() {
+		app.RegisterInvariant("test/not-broken", func(ctx sdk.Context) error { return nil })
+	})
+	app.SetInvariantCheckPeriod(2)
+
+	// an invariant violation at a height that isn't checked goes unnoticed
+	header := wrsp.Header{Height: 1}
+	app.BeginBlock(wrsp.RequestBeginBlock{Header: header})
+	app.deliverState.ctx.KVStore(capKey).Set(key, []byte("broken"))
+	require.NotPanics(t, func() { app.EndBlock(wrsp.RequestEndBlock{Height: 1}) })
+	app.Commit()
+	require.NotNil(t, app.CheckInvariants())
+
+	// fix it
+	header = wrsp.Header{Height: 2}
+	app.BeginBlock(wrsp.RequestBeginBlock{Header: header})
+	app.deliverState.ctx.KVStore(capKey).Set(key, []byte("fixed"))
+	require.NotPanics(t, func() { app.EndBlock(wrsp.RequestEndBlock{Height: 2}) })
+	app.Commit()
+	require.Nil(t, app.CheckInvariants())
+
+	// uncommitted writes of the check state are ignored
+	app.checkState.ctx.KVStore(capKey).Set(key, []byte("broken"))
+	require.Nil(t, app.CheckInvariants())
+
+	// the node halts on violations at checked heights
+	header = wrsp.Header{Height: 4}
+	app.BeginBlock(wrsp.RequestBeginBlock{Header: header})
+	app.deliverState.ctx.KVStore(capKey).Set(key, []byte("broken"))
+	require.Panics(t, func() { app.EndBlock(wrsp.RequestEndBlock{Height: 4}) })
+}
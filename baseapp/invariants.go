@@ -0,0 +1,68 @@
+package baseapp
+
+import (
+	"fmt"
+
+	wrsp "github.com/tepleton/wrsp/types"
+
+	sdk "github.com/tepleton/tepleton-sdk/types"
+)
+
+// named invariant of a module
+type invariant struct {
+	name  string
+	check sdk.Invariant
+}
+
+// RegisterInvariant adds an invariant checked by AssertInvariants, named
+// after the module and the property it checks, e.g. "stake/delegator-shares"
+func (app *BaseApp) RegisterInvariant(name string, check sdk.Invariant) {
+	for _, inv := range app.invariants {
+		if inv.name == name {
+			panic(fmt.Sprintf("invariant %s already registered", name))
+		}
+	}
+	app.invariants = append(app.invariants, invariant{name, check})
+}
+
+// SetInvariantCheckPeriod checks all invariants in EndBlock every period
+// blocks, halting the node on a violation. Zero disables the checks.
+func (app *BaseApp) SetInvariantCheckPeriod(period int64) {
+	if period < 0 {
+		panic("invariant check period can't be negative")
+	}
+	app.invCheckPeriod = period
+}
+
+// AssertInvariants checks all registered invariants against the state of
+// ctx, in the order of registration, and returns the first violation
+func (app *BaseApp) AssertInvariants(ctx sdk.Context) error {
+	for _, inv := range app.invariants {
+		err := inv.check(ctx)
+		if err != nil {
+			return fmt.Errorf("invariant %s broken: %v", inv.name, err)
+		}
+	}
+	return nil
+}
+
+// CheckInvariants checks all registered invariants against a cache of the
+// last committed state, which the pending txs of the mempool don't alter
+func (app *BaseApp) CheckInvariants() error {
+	header := wrsp.Header{Height: app.LastBlockHeight()}
+	ctx := sdk.NewContext(app.cms.CacheMultiStore(), header, true, nil, app.Logger)
+	return app.AssertInvariants(ctx)
+}
+
+// assert the invariants at the end of the block if due, halting the node
+// on violations so that a broken state is never committed
+func (app *BaseApp) assertInvariantsAtEndBlock(ctx sdk.Context) {
+	if app.invCheckPeriod == 0 || ctx.BlockHeight()%app.invCheckPeriod != 0 {
+		return
+	}
+	err := app.AssertInvariants(ctx)
+	if err != nil {
+		app.Logger.Error("Halting on broken invariant", "height", ctx.BlockHeight(), "err", err)
+		panic(err)
+	}
+}
@@ -0,0 +1,47 @@
+package baseapp
+
+import (
+	"fmt"
+	"sort"
+
+	wrsp "github.com/tepleton/wrsp/types"
+
+	sdk "github.com/tepleton/tepleton-sdk/types"
+)
+
+// StreamingService is notified of every write to the stores it listens to
+// while blocks are delivered, and of the boundaries of the block phases.
+// Writes are reported before the boundary they belong to, writes of
+// InitChain are reported with the BeginBlock of the first block.
+type StreamingService interface {
+	sdk.WriteListener
+
+	// ListenBeginBlock ends the writes of BeginBlock
+	ListenBeginBlock(req wrsp.RequestBeginBlock)
+
+	// ListenDeliverTx ends the writes of a transaction
+	ListenDeliverTx(txBytes []byte, res wrsp.ResponseDeliverTx)
+
+	// ListenCommit ends the writes of EndBlock and the block
+	ListenCommit(commitID sdk.CommitID) error
+}
+
+// SetStreamingService makes s listen to the stores named storeNames, or to
+// all mounted stores if none are named. Call it after mounting the stores.
+func (app *BaseApp) SetStreamingService(s StreamingService, storeNames ...string) error {
+	if len(storeNames) == 0 {
+		for name := range app.storeKeys {
+			storeNames = append(storeNames, name)
+		}
+		sort.Strings(storeNames)
+	}
+	for _, name := range storeNames {
+		key, ok := app.storeKeys[name]
+		if !ok {
+			return fmt.Errorf("no store named %v is mounted", name)
+		}
+		app.cms.AddListeners(key, []sdk.WriteListener{s})
+	}
+	app.streaming = s
+	return nil
+}
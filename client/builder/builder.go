@@ -84,7 +84,7 @@ func GetFromAddress() (from sdk.Address, err error) {
 		return nil, errors.Errorf("No key for: %s", name)
 	}
 
-	return info.PubKey.Address(), nil
+	return sdk.Address(info.GetPubKey().Address()), nil
 }
 
 // sign and build the transaction from the msg
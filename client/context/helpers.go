@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/pkg/errors"
+	"github.com/spf13/viper"
 
 	"github.com/tepleton/tepleton-sdk/wire"
 	"github.com/tepleton/tepleton-sdk/x/auth"
@@ -47,13 +48,34 @@ func (ctx CoreContext) Query(key cmn.HexBytes, storeName string) (res []byte, er
 	return ctx.query(key, storeName, "key")
 }
 
-// Query from Tendermint with the provided storename and subspace
+// Query from Tendermint with the provided storename and subspace.
+// The subspace is queried page by page, see QuerySubspacePage.
 func (ctx CoreContext) QuerySubspace(cdc *wire.Codec, subspace []byte, storeName string) (res []sdk.KVPair, err error) {
-	resRaw, err := ctx.query(subspace, storeName, "subspace")
+	query := sdk.SubspaceQuery{Subspace: subspace}
+	for {
+		page, err := ctx.QuerySubspacePage(cdc, query, storeName)
+		if err != nil {
+			return res, err
+		}
+		res = append(res, page.KVs...)
+		if page.NextKey == nil {
+			return res, nil
+		}
+		query.Start = page.NextKey
+	}
+}
+
+// Query a single page of a subspace from Tendermint with the provided storename
+func (ctx CoreContext) QuerySubspacePage(cdc *wire.Codec, query sdk.SubspaceQuery, storeName string) (page sdk.SubspaceResult, err error) {
+	bz, err := cdc.MarshalBinary(query)
 	if err != nil {
-		return res, err
+		return page, err
 	}
-	cdc.MustUnmarshalBinary(resRaw, &res)
+	resRaw, err := ctx.query(bz, storeName, "subspace-page")
+	if err != nil {
+		return page, err
+	}
+	err = cdc.UnmarshalBinary(resRaw, &page)
 	return
 }
 
@@ -98,7 +120,7 @@ func (ctx CoreContext) GetFromAddress() (from sdk.Address, err error) {
 		return nil, errors.Errorf("No key for: %s", name)
 	}
 
-	return info.PubKey.Address(), nil
+	return sdk.Address(info.GetPubKey().Address()), nil
 }
 
 // sign and build the transaction from the msg
@@ -111,6 +133,17 @@ func (ctx CoreContext) SignAndBuild(name, passphrase string, msg sdk.Msg, cdc *w
 	}
 	accnum := ctx.AccountNumber
 	sequence := ctx.Sequence
+	signMode, err := auth.ParseSignMode(ctx.SignMode)
+	if err != nil {
+		return nil, err
+	}
+	var feeGranter sdk.Address
+	if ctx.FeeGranter != "" {
+		feeGranter, err = sdk.GetAccAddressBech32(ctx.FeeGranter)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	signMsg := auth.StdSignMsg{
 		ChainID:        chainID,
@@ -118,6 +151,8 @@ func (ctx CoreContext) SignAndBuild(name, passphrase string, msg sdk.Msg, cdc *w
 		Sequences:      []int64{sequence},
 		Msg:            msg,
 		Fee:            auth.NewStdFee(ctx.Gas, sdk.Coin{}), // TODO run simulate to estimate gas?
+		TimeoutHeight:  ctx.TimeoutHeight,
+		FeeGranter:     feeGranter,
 	}
 
 	keybase, err := keys.GetKeyBase()
@@ -126,7 +161,10 @@ func (ctx CoreContext) SignAndBuild(name, passphrase string, msg sdk.Msg, cdc *w
 	}
 
 	// sign and build
-	bz := signMsg.Bytes()
+	bz, err := signMsg.BytesForMode(signMode)
+	if err != nil {
+		return nil, err
+	}
 
 	sig, pubkey, err := keybase.Sign(name, passphrase, bz)
 	if err != nil {
@@ -137,10 +175,11 @@ func (ctx CoreContext) SignAndBuild(name, passphrase string, msg sdk.Msg, cdc *w
 		Signature:     sig,
 		AccountNumber: accnum,
 		Sequence:      sequence,
+		SignMode:      signMode,
 	}}
 
 	// marshal bytes
-	tx := auth.NewStdTx(signMsg.Msg, signMsg.Fee, sigs)
+	tx := auth.NewStdTx(signMsg.Msg, signMsg.Fee, sigs).WithTimeoutHeight(signMsg.TimeoutHeight).WithFeeGranter(signMsg.FeeGranter)
 
 	return cdc.MarshalBinary(tx)
 }
@@ -221,6 +260,10 @@ func (ctx CoreContext) NextSequence(address []byte) (int64, error) {
 
 // get passphrase from std input
 func (ctx CoreContext) GetPassphraseFromStdin(name string) (pass string, err error) {
+	if viper.GetString(client.FlagKeyringBackend) == keys.BackendTest {
+		// the test keyring never asks for a passphrase
+		return "", nil
+	}
 	buf := client.BufferStdin()
 	prompt := fmt.Sprintf("Password to sign with '%s':", name)
 	return client.GetPassword(prompt, buf)
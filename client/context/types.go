@@ -19,6 +19,9 @@ type CoreContext struct {
 	Client          rpcclient.Client
 	Decoder         auth.AccountDecoder
 	AccountStore    string
+	TimeoutHeight   int64
+	SignMode        string
+	FeeGranter      string
 }
 
 // WithChainID - return a copy of the context with an updated chainID
@@ -87,3 +90,21 @@ func (c CoreContext) WithAccountStore(accountStore string) CoreContext {
 	c.AccountStore = accountStore
 	return c
 }
+
+// WithTimeoutHeight - return a copy of the context with an updated timeout height
+func (c CoreContext) WithTimeoutHeight(height int64) CoreContext {
+	c.TimeoutHeight = height
+	return c
+}
+
+// WithSignMode - return a copy of the context with an updated sign mode, json or textual
+func (c CoreContext) WithSignMode(signMode string) CoreContext {
+	c.SignMode = signMode
+	return c
+}
+
+// WithFeeGranter - return a copy of the context with an updated bech32 address of the fee granter
+func (c CoreContext) WithFeeGranter(feeGranter string) CoreContext {
+	c.FeeGranter = feeGranter
+	return c
+}
@@ -23,7 +23,7 @@ func NewCoreContextFromViper() CoreContext {
 	// if chain ID is not specified manually, read default chain ID
 	if chainID == "" {
 		def, err := defaultChainID()
-		if err != nil {
+		if err == nil {
 			chainID = def
 		}
 	}
@@ -39,6 +39,9 @@ func NewCoreContextFromViper() CoreContext {
 		Client:          rpc,
 		Decoder:         nil,
 		AccountStore:    "acc",
+		TimeoutHeight:   viper.GetInt64(client.FlagTimeoutHeight),
+		SignMode:        viper.GetString(client.FlagSignMode),
+		FeeGranter:      viper.GetString(client.FlagFeeGranter),
 	}
 }
 
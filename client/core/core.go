@@ -83,7 +83,7 @@ func (ctx CoreContext) GetFromAddress() (from sdk.Address, err error) {
 		return nil, errors.Errorf("No key for: %s", name)
 	}
 
-	return info.PubKey.Address(), nil
+	return sdk.Address(info.GetPubKey().Address()), nil
 }
 
 // sign and build the transaction from the msg
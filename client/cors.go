@@ -0,0 +1,42 @@
+package client
+
+import "strings"
+
+// AllowedOrigins are the origins allowed to make cross-origin requests
+// to the rest-server, set with --cors
+type AllowedOrigins struct {
+	any     bool
+	origins map[string]bool
+}
+
+// ParseAllowedOrigins parses a comma separated list of origins, "*"
+// allows all origins
+func ParseAllowedOrigins(origins string) AllowedOrigins {
+	allowed := AllowedOrigins{origins: make(map[string]bool)}
+	for _, origin := range strings.Split(origins, ",") {
+		origin = strings.TrimSpace(origin)
+		switch origin {
+		case "":
+		case "*":
+			allowed.any = true
+		default:
+			allowed.origins[origin] = true
+		}
+	}
+	return allowed
+}
+
+// Empty is true if no origin is allowed
+func (a AllowedOrigins) Empty() bool {
+	return !a.any && len(a.origins) == 0
+}
+
+// Any is true if all origins are allowed
+func (a AllowedOrigins) Any() bool {
+	return a.any
+}
+
+// Allows is true if origin is allowed
+func (a AllowedOrigins) Allows(origin string) bool {
+	return origin != "" && (a.any || a.origins[origin])
+}
@@ -4,15 +4,20 @@ import "github.com/spf13/cobra"
 
 // nolint
 const (
-	FlagChainID       = "chain-id"
-	FlagNode          = "node"
-	FlagHeight        = "height"
-	FlagGas           = "gas"
-	FlagTrustNode     = "trust-node"
-	FlagName          = "name"
-	FlagAccountNumber = "account-number"
-	FlagSequence      = "sequence"
-	FlagFee           = "fee"
+	FlagChainID        = "chain-id"
+	FlagNode           = "node"
+	FlagHeight         = "height"
+	FlagGas            = "gas"
+	FlagTrustNode      = "trust-node"
+	FlagName           = "name"
+	FlagAccountNumber  = "account-number"
+	FlagSequence       = "sequence"
+	FlagFee            = "fee"
+	FlagKeyringBackend = "keyring-backend"
+	FlagTimeoutHeight  = "timeout-height"
+	FlagSignMode       = "sign-mode"
+	FlagFeeGranter     = "fee-granter"
+	FlagCORS           = "cors"
 )
 
 // LineBreak can be included in a command list to provide a blank line
@@ -41,6 +46,10 @@ func PostCommands(cmds ...*cobra.Command) []*cobra.Command {
 		c.Flags().String(FlagChainID, "", "Chain ID of tepleton node")
 		c.Flags().String(FlagNode, "tcp://localhost:46657", "<host>:<port> to tepleton rpc interface for this chain")
 		c.Flags().Int64(FlagGas, 200000, "gas limit to set per-transaction")
+		c.Flags().String(FlagKeyringBackend, "db", "Keyring the signing key is stored in (db|file|memory|test)")
+		c.Flags().Int64(FlagTimeoutHeight, 0, "Block height after which the tx is refused, 0 if it doesn't time out")
+		c.Flags().String(FlagSignMode, "json", "Encoding of the signed message (json|textual)")
+		c.Flags().String(FlagFeeGranter, "", "Bech32 address of the account paying the fee from its fee grant")
 	}
 	return cmds
 }
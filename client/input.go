@@ -48,6 +48,15 @@ func GetSeed(prompt string, buf *bufio.Reader) (seed string, err error) {
 	return
 }
 
+// GetBIP39Passphrase will prompt for the optional BIP 39 passphrase of a
+// mnemonic, without echo on a tty. Unlike passwords it may be empty.
+func GetBIP39Passphrase(prompt string, buf *bufio.Reader) (passphrase string, err error) {
+	if inputIsTty() {
+		return speakeasy.Ask(prompt)
+	}
+	return readLineFromBuf(buf)
+}
+
 // GetCheckPassword will prompt for a password twice to verify they
 // match (for creating a new password).
 // It enforces the password length. Only parses password once if
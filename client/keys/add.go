@@ -1,26 +1,35 @@
 package keys
 
 import (
+	"bufio"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 
-	"github.com/tepleton/tepleton-sdk/client"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
-	"github.com/tepleton/go-crypto/keys"
+	crypto "github.com/tepleton/tepleton/crypto"
 	"github.com/tepleton/tmlibs/cli"
+
+	"github.com/tepleton/tepleton-sdk/client"
+	"github.com/tepleton/tepleton-sdk/crypto/keys"
+	"github.com/tepleton/tepleton-sdk/crypto/keys/hd"
 )
 
 const (
-	flagType     = "type"
-	flagRecover  = "recover"
-	flagNoBackup = "no-backup"
-	flagDryRun   = "dry-run"
+	flagType            = "type"
+	flagRecover         = "recover"
+	flagNoBackup        = "no-backup"
+	flagDryRun          = "dry-run"
+	flagBIP39Passphrase = "bip39-passphrase"
+	flagRemoteSigner    = "remote-signer"
+	flagRemoteSignerKey = "remote-signer-pubkey"
+	flagRemoteKey       = "remote-key"
 )
 
 func addKeyCommand() *cobra.Command {
@@ -29,13 +38,23 @@ func addKeyCommand() *cobra.Command {
 		Short: "Create a new key, or import from seed",
 		Long: `Add a public/private key pair to the key store.
 If you select --seed/-s you can recover a key from the seed
-phrase, otherwise, a new key will be generated.`,
+phrase, otherwise, a new key will be generated.
+With --bip39-passphrase the key is derived from the seed phrase
+together with a BIP 39 passphrase, which is needed to recover it.
+Recovered keys are secp256k1 keys unless --type is given, so keys
+of other types need their --type to be recovered.
+With --remote-signer the key is a reference to a key held by a
+remote signer, which signs with it.`,
 		RunE: runAddCmd,
 	}
-	cmd.Flags().StringP(flagType, "t", "ed25519", "Type of private key (ed25519|secp256k1|ledger)")
+	cmd.Flags().StringP(flagType, "t", "ed25519", "Type of private key (ed25519|secp256k1|secp256r1), secp256k1 by default with --recover")
 	cmd.Flags().Bool(flagRecover, false, "Provide seed phrase to recover existing key instead of creating")
 	cmd.Flags().Bool(flagNoBackup, false, "Don't print out seed phrase (if others are watching the terminal)")
 	cmd.Flags().Bool(flagDryRun, false, "Perform action, but don't add key to local keystore")
+	cmd.Flags().Bool(flagBIP39Passphrase, false, "Prompt for the BIP 39 passphrase of the seed phrase")
+	cmd.Flags().String(flagRemoteSigner, "", "Address of the remote signer holding the key, tcp://host:port or unix:///path")
+	cmd.Flags().String(flagRemoteSignerKey, "", "Hex identity the remote signer must authenticate with, required with --remote-signer")
+	cmd.Flags().String(flagRemoteKey, "", "Name of the key in the remote signer, the name of the key if empty")
 	return cmd
 }
 
@@ -70,21 +89,35 @@ func runAddCmd(cmd *cobra.Command, args []string) error {
 			}
 		}
 
-		pass, err = client.GetCheckPassword(
-			"Enter a passphrase for your key:",
-			"Repeat the passphrase:", buf)
-		if err != nil {
-			return err
+		if !usesTestBackend() {
+			pass, err = client.GetCheckPassword(
+				"Enter a passphrase for your key:",
+				"Repeat the passphrase:", buf)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
-	if viper.GetBool(flagRecover) {
+	algo := signingAlgo(cmd)
+	if viper.GetString(flagRemoteSigner) != "" {
+		info, err := addRemoteKey(kb, name, pass, buf)
+		if err != nil {
+			return err
+		}
+		viper.Set(flagNoBackup, true)
+		printCreate(info, "")
+	} else if viper.GetBool(flagRecover) {
 		seed, err := client.GetSeed(
 			"Enter your recovery seed phrase:", buf)
 		if err != nil {
 			return err
 		}
-		info, err := kb.Recover(name, pass, seed)
+		bip39Passphrase, err := getBIP39Passphrase(buf)
+		if err != nil {
+			return err
+		}
+		info, err := kb.Derive(name, seed, pass, bip39Passphrase, *hd.NewFundraiserParams(0, 0), algo)
 		if err != nil {
 			return err
 		}
@@ -92,8 +125,11 @@ func runAddCmd(cmd *cobra.Command, args []string) error {
 		viper.Set(flagNoBackup, true)
 		printCreate(info, "")
 	} else {
-		algo := keys.CryptoAlgo(viper.GetString(flagType))
-		info, seed, err := kb.Create(name, pass, algo)
+		bip39Passphrase, err := getBIP39Passphrase(buf)
+		if err != nil {
+			return err
+		}
+		info, seed, err := kb.CreateMnemonic(name, keys.English, pass, bip39Passphrase, algo)
 		if err != nil {
 			return err
 		}
@@ -102,6 +138,58 @@ func runAddCmd(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// signingAlgo returns the type of the key to add. Recovered keys default to
+// secp256k1, the type of the keys recovered before --type applied to them,
+// so that their seed phrase still recovers the same address.
+func signingAlgo(cmd *cobra.Command) keys.SigningAlgo {
+	if viper.GetBool(flagRecover) && !cmd.Flags().Changed(flagType) {
+		return keys.Secp256k1
+	}
+	return keys.SigningAlgo(viper.GetString(flagType))
+}
+
+// addRemoteKey stores a reference to a key of the remote signer. The
+// keybase authenticates to the signer with a new identity, which the
+// signer must allow before the key is fetched.
+func addRemoteKey(kb keys.Keybase, name, pass string, buf *bufio.Reader) (keys.Info, error) {
+	h := viper.GetString(flagRemoteSignerKey)
+	if h == "" {
+		return nil, errors.Errorf("--%s is required with --%s", flagRemoteSignerKey, flagRemoteSigner)
+	}
+	bz, err := hex.DecodeString(h)
+	if err != nil {
+		return nil, errors.Errorf("invalid remote signer identity: %v", err)
+	}
+	signerPubKey, err := crypto.PubKeyFromBytes(bz)
+	if err != nil {
+		return nil, errors.Errorf("invalid remote signer identity: %v", err)
+	}
+	keyName := viper.GetString(flagRemoteKey)
+	if keyName == "" {
+		keyName = name
+	}
+
+	identity := crypto.GenPrivKeyEd25519()
+	fmt.Printf("Identity of the key for the remote signer: %X\n", identity.PubKey().Bytes())
+	response, err := client.GetConfirmation("allow it in the remote signer, then connect", buf)
+	if err != nil {
+		return nil, err
+	}
+	if !response {
+		return nil, errors.New("aborted")
+	}
+	return kb.CreateRemote(name, viper.GetString(flagRemoteSigner), keyName, signerPubKey, identity, pass)
+}
+
+// getBIP39Passphrase prompts for the BIP 39 passphrase if it was requested,
+// it's empty otherwise
+func getBIP39Passphrase(buf *bufio.Reader) (string, error) {
+	if !viper.GetBool(flagBIP39Passphrase) {
+		return "", nil
+	}
+	return client.GetBIP39Passphrase("Enter your BIP 39 passphrase:", buf)
+}
+
 func printCreate(info keys.Info, seed string) {
 	output := viper.Get(cli.OutputFlag)
 	switch output {
@@ -181,7 +269,7 @@ func AddNewKeyRequestHandler(w http.ResponseWriter, r *http.Request) {
 	// check if already exists
 	infos, err := kb.List()
 	for _, i := range infos {
-		if i.Name == m.Name {
+		if i.GetName() == m.Name {
 			w.WriteHeader(http.StatusConflict)
 			w.Write([]byte(fmt.Sprintf("Account with name %s already exists.", m.Name)))
 			return
@@ -189,22 +277,22 @@ func AddNewKeyRequestHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// create account
-	info, err := kb.Recover(m.Name, m.Password, m.Seed)
+	info, err := kb.CreateKey(m.Name, m.Seed, m.Password)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		w.Write([]byte(err.Error()))
 		return
 	}
-	w.Write([]byte(info.PubKey.Address().String()))
+	w.Write([]byte(info.GetPubKey().Address().String()))
 }
 
 // function to just a new seed to display in the UI before actually persisting it in the keybase
-func getSeed(algo keys.CryptoAlgo) string {
+func getSeed(algo keys.SigningAlgo) string {
 	kb := client.MockKeyBase()
 	pass := "throwing-this-key-away"
 	name := "inmemorykey"
 
-	_, seed, _ := kb.Create(name, pass, algo)
+	_, seed, _ := kb.CreateMnemonic(name, keys.English, pass, "", algo)
 	return seed
 }
 
@@ -216,7 +304,7 @@ func SeedRequestHandler(w http.ResponseWriter, r *http.Request) {
 	if algoType == "" {
 		algoType = "ed25519"
 	}
-	algo := keys.CryptoAlgo(algoType)
+	algo := keys.SigningAlgo(algoType)
 
 	seed := getSeed(algo)
 	w.Write([]byte(seed))
@@ -0,0 +1,27 @@
+package keys
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tepleton/tepleton-sdk/crypto/keys"
+)
+
+func TestAddSigningAlgo(t *testing.T) {
+	defer viper.Reset()
+
+	// new keys take the default type
+	cmd := addKeyCommand()
+	viper.Set(flagType, "ed25519")
+	assert.Equal(t, keys.SigningAlgo("ed25519"), signingAlgo(cmd))
+
+	// recovered keys are secp256k1 keys unless their type is given
+	viper.Set(flagRecover, true)
+	assert.Equal(t, keys.Secp256k1, signingAlgo(cmd))
+	require.Nil(t, cmd.Flags().Set(flagType, "secp256r1"))
+	viper.Set(flagType, "secp256r1")
+	assert.Equal(t, keys.SigningAlgo("secp256r1"), signingAlgo(cmd))
+}
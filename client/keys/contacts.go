@@ -0,0 +1,460 @@
+package keys
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tepleton/tmlibs/cli"
+	dbm "github.com/tepleton/tmlibs/db"
+
+	sdk "github.com/tepleton/tepleton-sdk/types"
+)
+
+const flagNotes = "notes"
+
+// Contact is a named counterparty of the address book, like the deposit
+// address of an exchange
+type Contact struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Notes   string `json:"notes,omitempty"`
+}
+
+// ValidateBasic checks the name and the bech32 address of the contact
+func (c Contact) ValidateBasic() error {
+	if c.Name == "" || strings.HasPrefix(c.Name, "@") {
+		return fmt.Errorf("invalid contact name %q", c.Name)
+	}
+	if _, err := sdk.GetAccAddressBech32(c.Address); err != nil {
+		return fmt.Errorf("invalid address %q: %v", c.Address, err)
+	}
+	return nil
+}
+
+// Contact returns the contact name, nil if there is none
+func (s *MetadataStore) Contact(name string) (*Contact, error) {
+	bz := s.db.Get(contactKey(name))
+	if bz == nil {
+		return nil, nil
+	}
+	var contact Contact
+	err := json.Unmarshal(bz, &contact)
+	if err != nil {
+		return nil, err
+	}
+	return &contact, nil
+}
+
+// Contacts returns the whole address book, sorted by name
+func (s *MetadataStore) Contacts() ([]Contact, error) {
+	var contacts []Contact
+	iter := dbm.IteratePrefix(s.db, []byte(contactPrefix))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		var contact Contact
+		err := json.Unmarshal(iter.Value(), &contact)
+		if err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, contact)
+	}
+	return contacts, nil
+}
+
+// SetContact adds contact to the address book, or replaces the contact of
+// the same name
+func (s *MetadataStore) SetContact(contact Contact) error {
+	err := contact.ValidateBasic()
+	if err != nil {
+		return err
+	}
+	bz, err := json.Marshal(contact)
+	if err != nil {
+		return err
+	}
+	s.db.SetSync(contactKey(contact.Name), bz)
+	return nil
+}
+
+// DeleteContact removes the contact name from the address book
+func (s *MetadataStore) DeleteContact(name string) {
+	s.db.DeleteSync(contactKey(name))
+}
+
+var errContactNotFound = errors.New("contact not found")
+
+// keyExists tells whether there is a local key name. Contacts can't be named
+// after local keys, as @name would name both.
+func keyExists(name string) (bool, error) {
+	kb, err := GetKeyBase()
+	if err != nil {
+		return false, err
+	}
+	_, err = kb.Get(name)
+	return err == nil, nil
+}
+
+// getContact returns the contact name of the address book
+func getContact(name string) (Contact, error) {
+	store, err := GetMetadataStore()
+	if err != nil {
+		return Contact{}, err
+	}
+	contact, err := store.Contact(name)
+	if err != nil {
+		return Contact{}, err
+	}
+	if contact == nil {
+		return Contact{}, errContactNotFound
+	}
+	return *contact, nil
+}
+
+func contactsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "contacts",
+		Short: "Manage the address book of named addresses",
+		Long: `The address book names addresses, like the deposit addresses of
+exchanges. Commands taking an address take @name for the address of the
+contact name or of the local key name. Contacts can't take the name of a
+local key.`,
+	}
+	addCmd := &cobra.Command{
+		Use:   "add <name> <address>",
+		Short: "Add a contact to the address book",
+		Args:  cobra.ExactArgs(2),
+		RunE:  runAddContactCmd,
+	}
+	addCmd.Flags().String(flagNotes, "", "Notes about the contact")
+	updateCmd := &cobra.Command{
+		Use:   "update <name> [<address>]",
+		Short: "Change the address or the notes of a contact",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE:  runUpdateContactCmd,
+	}
+	updateCmd.Flags().String(flagNotes, "", "Notes about the contact")
+	cmd.AddCommand(
+		addCmd,
+		&cobra.Command{
+			Use:   "list",
+			Short: "List the address book",
+			Args:  cobra.NoArgs,
+			RunE:  runListContactsCmd,
+		},
+		&cobra.Command{
+			Use:   "show <name>",
+			Short: "Show a contact of the address book",
+			Args:  cobra.ExactArgs(1),
+			RunE:  runShowContactCmd,
+		},
+		updateCmd,
+		&cobra.Command{
+			Use:   "delete <name>",
+			Short: "Remove a contact from the address book",
+			Args:  cobra.ExactArgs(1),
+			RunE:  runDeleteContactCmd,
+		},
+	)
+	return cmd
+}
+
+func runAddContactCmd(cmd *cobra.Command, args []string) error {
+	store, err := GetMetadataStore()
+	if err != nil {
+		return err
+	}
+	existing, err := store.Contact(args[0])
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("contact %s exists already", args[0])
+	}
+	exists, err := keyExists(args[0])
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("a key named %s exists already", args[0])
+	}
+	contact := Contact{
+		Name:    args[0],
+		Address: args[1],
+		Notes:   viper.GetString(flagNotes),
+	}
+	err = store.SetContact(contact)
+	if err != nil {
+		return err
+	}
+	printContacts([]Contact{contact})
+	return nil
+}
+
+func runUpdateContactCmd(cmd *cobra.Command, args []string) error {
+	contact, err := getContact(args[0])
+	if err != nil {
+		return err
+	}
+	if len(args) == 2 {
+		contact.Address = args[1]
+	}
+	if cmd.Flags().Changed(flagNotes) {
+		contact.Notes = viper.GetString(flagNotes)
+	}
+	store, err := GetMetadataStore()
+	if err != nil {
+		return err
+	}
+	err = store.SetContact(contact)
+	if err != nil {
+		return err
+	}
+	printContacts([]Contact{contact})
+	return nil
+}
+
+func runListContactsCmd(cmd *cobra.Command, args []string) error {
+	store, err := GetMetadataStore()
+	if err != nil {
+		return err
+	}
+	contacts, err := store.Contacts()
+	if err != nil {
+		return err
+	}
+	printContacts(contacts)
+	return nil
+}
+
+func runShowContactCmd(cmd *cobra.Command, args []string) error {
+	contact, err := getContact(args[0])
+	if err != nil {
+		return err
+	}
+	printContacts([]Contact{contact})
+	return nil
+}
+
+func runDeleteContactCmd(cmd *cobra.Command, args []string) error {
+	if _, err := getContact(args[0]); err != nil {
+		return err
+	}
+	store, err := GetMetadataStore()
+	if err != nil {
+		return err
+	}
+	store.DeleteContact(args[0])
+	fmt.Printf("Contact %s deleted\n", args[0])
+	return nil
+}
+
+func printContacts(contacts []Contact) {
+	switch viper.Get(cli.OutputFlag) {
+	case "text":
+		fmt.Printf("NAME:\tADDRESS:\t\t\t\t\t\tNOTES:\n")
+		for _, contact := range contacts {
+			fmt.Printf("%s\t%s\t%s\n", contact.Name, contact.Address, contact.Notes)
+		}
+	case "json":
+		out, err := json.MarshalIndent(contacts, "", "  ")
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(string(out))
+	}
+}
+
+///////////////////////
+// REST
+
+// update contact REST body
+type UpdateContactBody struct {
+	Address string `json:"address"`
+	Notes   string `json:"notes"`
+}
+
+// query address book REST handler
+func QueryContactsRequestHandler(w http.ResponseWriter, r *http.Request) {
+	store, err := GetMetadataStore()
+	if err != nil {
+		w.WriteHeader(500)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	contacts, err := store.Contacts()
+	if err != nil {
+		w.WriteHeader(500)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	// an empty list will be JSONized as null, but we want to keep the empty list
+	if len(contacts) == 0 {
+		w.Write([]byte("[]"))
+		return
+	}
+	output, err := json.MarshalIndent(contacts, "", "  ")
+	if err != nil {
+		w.WriteHeader(500)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	w.Write(output)
+}
+
+// add contact REST handler
+func AddContactRequestHandler(w http.ResponseWriter, r *http.Request) {
+	var contact Contact
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&contact)
+	if err != nil {
+		w.WriteHeader(400)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	err = contact.ValidateBasic()
+	if err != nil {
+		w.WriteHeader(400)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	store, err := GetMetadataStore()
+	if err != nil {
+		w.WriteHeader(500)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	existing, err := store.Contact(contact.Name)
+	if err != nil {
+		w.WriteHeader(500)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	if existing != nil {
+		w.WriteHeader(409)
+		w.Write([]byte(fmt.Sprintf("contact %s exists already", contact.Name)))
+		return
+	}
+	exists, err := keyExists(contact.Name)
+	if err != nil {
+		w.WriteHeader(500)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	if exists {
+		w.WriteHeader(409)
+		w.Write([]byte(fmt.Sprintf("a key named %s exists already", contact.Name)))
+		return
+	}
+	err = store.SetContact(contact)
+	if err != nil {
+		w.WriteHeader(500)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.WriteHeader(200)
+}
+
+// get contact REST handler
+func GetContactRequestHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	contact, err := getContact(vars["contact"])
+	if err == errContactNotFound {
+		w.WriteHeader(404)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(500)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	output, err := json.MarshalIndent(contact, "", "  ")
+	if err != nil {
+		w.WriteHeader(500)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	w.Write(output)
+}
+
+// update contact REST handler, replacing the address and the notes
+func UpdateContactRequestHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	var m UpdateContactBody
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&m)
+	if err != nil {
+		w.WriteHeader(400)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	contact, err := getContact(vars["contact"])
+	if err == errContactNotFound {
+		w.WriteHeader(404)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(500)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	contact.Address = m.Address
+	contact.Notes = m.Notes
+	err = contact.ValidateBasic()
+	if err != nil {
+		w.WriteHeader(400)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	store, err := GetMetadataStore()
+	if err != nil {
+		w.WriteHeader(500)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	err = store.SetContact(contact)
+	if err != nil {
+		w.WriteHeader(500)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.WriteHeader(200)
+}
+
+// delete contact REST handler
+func DeleteContactRequestHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name := vars["contact"]
+	_, err := getContact(name)
+	if err == errContactNotFound {
+		w.WriteHeader(404)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(500)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	store, err := GetMetadataStore()
+	if err != nil {
+		w.WriteHeader(500)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	store.DeleteContact(name)
+
+	w.WriteHeader(200)
+}
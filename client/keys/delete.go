@@ -5,11 +5,11 @@ import (
 	"fmt"
 	"net/http"
 
-	"github.com/tepleton/tepleton-sdk/client"
 	"github.com/gorilla/mux"
-	keys "github.com/tepleton/go-crypto/keys"
-
 	"github.com/spf13/cobra"
+
+	"github.com/tepleton/tepleton-sdk/client"
+	"github.com/tepleton/tepleton-sdk/crypto/keys"
 )
 
 func deleteKeyCommand() *cobra.Command {
@@ -25,11 +25,15 @@ func deleteKeyCommand() *cobra.Command {
 func runDeleteCmd(cmd *cobra.Command, args []string) error {
 	name := args[0]
 
-	buf := client.BufferStdin()
-	oldpass, err := client.GetPassword(
-		"DANGER - enter password to permanently delete key:", buf)
-	if err != nil {
-		return err
+	var oldpass string
+	if !usesTestBackend() {
+		var err error
+		buf := client.BufferStdin()
+		oldpass, err = client.GetPassword(
+			"DANGER - enter password to permanently delete key:", buf)
+		if err != nil {
+			return err
+		}
 	}
 
 	kb, err := GetKeyBase()
@@ -0,0 +1,54 @@
+package keys
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tepleton/tepleton-sdk/client"
+)
+
+func exportKeyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export <name>",
+		Short: "Export a private key, encrypted with an export passphrase, in ASCII armor",
+		Long: `Export a private key, encrypted with an export passphrase, in ASCII armor.
+The armor carries the type and the derivation path of the key, and is
+imported back with the import command.`,
+		RunE: runExportCmd,
+		Args: cobra.ExactArgs(1),
+	}
+	return cmd
+}
+
+func runExportCmd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	var decryptPass, encryptPass string
+	if !usesTestBackend() {
+		var err error
+		buf := client.BufferStdin()
+		decryptPass, err = client.GetPassword(
+			"Enter the passphrase of the key:", buf)
+		if err != nil {
+			return err
+		}
+		encryptPass, err = client.GetCheckPassword(
+			"Enter a passphrase to encrypt the exported key:",
+			"Repeat the passphrase:", buf)
+		if err != nil {
+			return err
+		}
+	}
+
+	kb, err := GetKeyBase()
+	if err != nil {
+		return err
+	}
+	armor, err := kb.ExportPrivKeyArmor(name, decryptPass, encryptPass)
+	if err != nil {
+		return err
+	}
+	fmt.Println(armor)
+	return nil
+}
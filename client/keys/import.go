@@ -0,0 +1,52 @@
+package keys
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tepleton/tepleton-sdk/client"
+)
+
+func importKeyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <name> <keyfile>",
+		Short: "Import a private key exported in ASCII armor",
+		Long: `Import a private key exported in ASCII armor by the export command.
+Keys in the legacy format are accepted too. The key is stored encrypted
+with the passphrase it was exported with.`,
+		RunE: runImportCmd,
+		Args: cobra.ExactArgs(2),
+	}
+	return cmd
+}
+
+func runImportCmd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	armor, err := ioutil.ReadFile(args[1])
+	if err != nil {
+		return err
+	}
+
+	var pass string
+	if !usesTestBackend() {
+		buf := client.BufferStdin()
+		pass, err = client.GetPassword(
+			"Enter the passphrase the key was exported with:", buf)
+		if err != nil {
+			return err
+		}
+	}
+
+	kb, err := GetKeyBase()
+	if err != nil {
+		return err
+	}
+	err = kb.ImportPrivKeyArmor(name, string(armor), pass)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Key %s imported\n", name)
+	return nil
+}
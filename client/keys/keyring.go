@@ -0,0 +1,111 @@
+package keys
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tepleton/tmlibs/cli"
+	dbm "github.com/tepleton/tmlibs/db"
+
+	"github.com/tepleton/tepleton-sdk/client"
+	"github.com/tepleton/tepleton-sdk/crypto/keys"
+)
+
+// Keyring backends selectable with --keyring-backend
+const (
+	// BackendDB keeps all keys in a LevelDB under <home>/keys
+	BackendDB = "db"
+	// BackendFile keeps every key in its own file under <home>/keyring-file
+	BackendFile = "file"
+	// BackendMemory keeps keys in memory only, they are lost on exit
+	BackendMemory = "memory"
+	// BackendTest keeps keys in files under <home>/keyring-test, all
+	// encrypted with TestPassphrase, and never asks for a passphrase
+	BackendTest = "test"
+)
+
+// TestPassphrase encrypts every key of the test backend
+const TestPassphrase = keys.TestPassphrase
+
+// keyringStorage opens the storage the keys of backend are kept in
+func keyringStorage(backend, rootDir string) (keys.Storage, error) {
+	switch backend {
+	case BackendDB, "":
+		db, err := dbm.NewGoLevelDB(KeyDBName, filepath.Join(rootDir, "keys"))
+		if err != nil {
+			return nil, err
+		}
+		return keys.NewDBStorage(db), nil
+	case BackendFile:
+		return keys.NewFileStorage(filepath.Join(rootDir, "keyring-file")), nil
+	case BackendMemory:
+		return keys.NewMemStorage(), nil
+	case BackendTest:
+		return keys.NewFileStorage(filepath.Join(rootDir, "keyring-test")), nil
+	default:
+		return nil, fmt.Errorf("unknown keyring backend %q", backend)
+	}
+}
+
+// newKeybase creates the keybase of backend on storage
+func newKeybase(backend string, storage keys.Storage) keys.Keybase {
+	if backend == BackendTest {
+		return keys.NewTestKeybase(storage)
+	}
+	return keys.NewWithStorage(storage)
+}
+
+// usesTestBackend is true if no passphrases should be asked for
+func usesTestBackend() bool {
+	return viper.GetString(client.FlagKeyringBackend) == BackendTest
+}
+
+func migrateKeysCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Copy the keys of the LevelDB keystore into the selected keyring backend",
+		Long: `Migrate copies every key of <home>/keys into the keyring selected with
+--keyring-backend. Keys already present in the keyring are left untouched,
+and keys stay encrypted with their current passphrase. Keys stored by older
+versions are converted to the current format.`,
+		Args: cobra.NoArgs,
+		RunE: runMigrateCmd,
+	}
+	return cmd
+}
+
+func runMigrateCmd(cmd *cobra.Command, args []string) error {
+	backend := viper.GetString(client.FlagKeyringBackend)
+	switch backend {
+	case BackendDB, "":
+		return fmt.Errorf("select the keyring to migrate to with --%s", client.FlagKeyringBackend)
+	case BackendMemory:
+		return fmt.Errorf("can't migrate to the %s backend, keys would be lost", BackendMemory)
+	case BackendTest:
+		return fmt.Errorf("can't migrate to the %s backend, its keys are encrypted with %q", BackendTest, TestPassphrase)
+	}
+
+	rootDir := viper.GetString(cli.HomeFlag)
+	db, err := dbm.NewGoLevelDB(KeyDBName, filepath.Join(rootDir, "keys"))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	to, err := keyringStorage(backend, rootDir)
+	if err != nil {
+		return err
+	}
+
+	migrated, err := keys.Migrate(keys.NewDBStorage(db), to)
+	if err != nil {
+		return err
+	}
+	for _, name := range migrated {
+		fmt.Printf("Migrated %s\n", name)
+	}
+	fmt.Printf("Migrated %d keys to the %s keyring\n", len(migrated), backend)
+	return nil
+}
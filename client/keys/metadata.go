@@ -0,0 +1,218 @@
+package keys
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tepleton/tmlibs/cli"
+	dbm "github.com/tepleton/tmlibs/db"
+
+	"github.com/tepleton/tepleton-sdk/client"
+	sdk "github.com/tepleton/tepleton-sdk/types"
+)
+
+// MetadataDBName is the database under <home>/keys holding the address book
+const MetadataDBName = "metadata"
+
+// prefix of the contact records of the metadata database
+const contactPrefix = "contact/"
+
+func contactKey(name string) []byte {
+	return []byte(contactPrefix + name)
+}
+
+// metadata is used to make GetMetadataStore a singleton
+var metadata *MetadataStore
+
+// MetadataStore holds what is known about addresses besides the keys: the
+// address book of named counterparties. The metadata of the keys, like notes
+// or tags, is part of the keys in the keybase, so that it goes with them.
+type MetadataStore struct {
+	db dbm.DB
+}
+
+// NewMetadataStore returns a MetadataStore keeping its records in db
+func NewMetadataStore(db dbm.DB) *MetadataStore {
+	return &MetadataStore{db: db}
+}
+
+// GetMetadataStore opens the metadata store of the selected keyring
+// backend, in memory for the memory backend
+func GetMetadataStore() (*MetadataStore, error) {
+	if metadata == nil {
+		var db dbm.DB
+		if viper.GetString(client.FlagKeyringBackend) == BackendMemory {
+			db = dbm.NewMemDB()
+		} else {
+			rootDir := viper.GetString(cli.HomeFlag)
+			var err error
+			db, err = dbm.NewGoLevelDB(MetadataDBName, filepath.Join(rootDir, "keys"))
+			if err != nil {
+				return nil, err
+			}
+		}
+		metadata = NewMetadataStore(db)
+	}
+	return metadata, nil
+}
+
+// used to set the metadata store manually in test
+func SetMetadataStore(store *MetadataStore) {
+	metadata = store
+}
+
+// GetAccAddress parses a bech32 account address. @name is resolved to the
+// address of the contact name of the address book or of the local key name,
+// so that commands taking an address can take a name. A name of both a
+// contact and a key is ambiguous and refused.
+func GetAccAddress(address string) (sdk.Address, error) {
+	if !strings.HasPrefix(address, "@") {
+		return sdk.GetAccAddressBech32(address)
+	}
+	name := strings.TrimPrefix(address, "@")
+
+	store, err := GetMetadataStore()
+	if err != nil {
+		return nil, err
+	}
+	contact, err := store.Contact(name)
+	if err != nil {
+		return nil, err
+	}
+	kb, err := GetKeyBase()
+	if err != nil {
+		return nil, err
+	}
+	info, keyErr := kb.Get(name)
+
+	switch {
+	case contact != nil && keyErr == nil:
+		return nil, fmt.Errorf("%s names both a contact and a key, delete one of them", name)
+	case contact != nil:
+		return sdk.GetAccAddressBech32(contact.Address)
+	case keyErr == nil:
+		return sdk.Address(info.GetPubKey().Address()), nil
+	default:
+		return nil, fmt.Errorf("no contact or key named %s", name)
+	}
+}
+
+func metadataCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "meta <name> [<field>=<value>...]",
+		Short: "Show or set the metadata of a key",
+		Long: `Show the metadata of a key, like notes or tags, or set fields of it.
+A field set to an empty value is removed.
+
+    keys meta validator note="cold storage" tags=ops,mainnet`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: runMetadataCmd,
+	}
+	return cmd
+}
+
+func runMetadataCmd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	info, err := getKey(name)
+	if err != nil {
+		return err
+	}
+	md := info.GetMetadata()
+
+	if len(args) > 1 {
+		for _, arg := range args[1:] {
+			kv := strings.SplitN(arg, "=", 2)
+			if len(kv) != 2 || kv[0] == "" {
+				return fmt.Errorf("invalid field %q, expected <field>=<value>", arg)
+			}
+			if kv[1] == "" {
+				delete(md, kv[0])
+			} else {
+				md[kv[0]] = kv[1]
+			}
+		}
+		kb, err := GetKeyBase()
+		if err != nil {
+			return err
+		}
+		info, err = kb.SetMetadata(name, md)
+		if err != nil {
+			return err
+		}
+		md = info.GetMetadata()
+	}
+	printMetadata(md)
+	return nil
+}
+
+func printMetadata(md map[string]string) {
+	switch viper.Get(cli.OutputFlag) {
+	case "text":
+		fields := make([]string, 0, len(md))
+		for field := range md {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			fmt.Printf("%s\t%s\n", field, md[field])
+		}
+	case "json":
+		out, err := json.MarshalIndent(md, "", "  ")
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(string(out))
+	}
+}
+
+///////////////////////
+// REST
+
+// set key metadata REST body
+type SetKeyMetadataBody struct {
+	Metadata map[string]string `json:"metadata"`
+}
+
+// set key metadata REST handler, replacing the metadata of the key
+func SetKeyMetadataRequestHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name := vars["name"]
+	var m SetKeyMetadataBody
+
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&m)
+	if err != nil {
+		w.WriteHeader(400)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	_, err = getKey(name)
+	if err != nil {
+		w.WriteHeader(404)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	kb, err := GetKeyBase()
+	if err != nil {
+		w.WriteHeader(500)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	_, err = kb.SetMetadata(name, m.Metadata)
+	if err != nil {
+		w.WriteHeader(500)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.WriteHeader(200)
+}
@@ -0,0 +1,92 @@
+package keys
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tcrypto "github.com/tepleton/tepleton/crypto"
+	dbm "github.com/tepleton/tmlibs/db"
+
+	"github.com/tepleton/tepleton-sdk/crypto/keys"
+	sdk "github.com/tepleton/tepleton-sdk/types"
+)
+
+func newTestAddress() sdk.Address {
+	return sdk.Address(tcrypto.GenPrivKeyEd25519().PubKey().Address())
+}
+
+func TestMetadataStoreContacts(t *testing.T) {
+	store := NewMetadataStore(dbm.NewMemDB())
+	contact, err := store.Contact("alice")
+	require.Nil(t, err)
+	assert.Nil(t, contact)
+
+	alice := Contact{Name: "alice", Address: sdk.MustBech32ifyAcc(newTestAddress()), Notes: "exchange deposit"}
+	bob := Contact{Name: "bob", Address: sdk.MustBech32ifyAcc(newTestAddress())}
+	assert.NotNil(t, store.SetContact(Contact{Name: "@carl", Address: bob.Address}))
+	assert.NotNil(t, store.SetContact(Contact{Name: "carl", Address: "invalid"}))
+	require.Nil(t, store.SetContact(bob))
+	require.Nil(t, store.SetContact(alice))
+
+	contacts, err := store.Contacts()
+	require.Nil(t, err)
+	assert.Equal(t, []Contact{alice, bob}, contacts)
+
+	alice.Notes = "closed"
+	require.Nil(t, store.SetContact(alice))
+	contact, err = store.Contact("alice")
+	require.Nil(t, err)
+	assert.Equal(t, &alice, contact)
+
+	store.DeleteContact("alice")
+	contact, err = store.Contact("alice")
+	require.Nil(t, err)
+	assert.Nil(t, contact)
+	contacts, err = store.Contacts()
+	require.Nil(t, err)
+	assert.Equal(t, []Contact{bob}, contacts)
+}
+
+func TestGetAccAddress(t *testing.T) {
+	kb := keys.NewInMemory()
+	SetKeyBase(kb)
+	store := NewMetadataStore(dbm.NewMemDB())
+	SetMetadataStore(store)
+
+	keyPub := tcrypto.GenPrivKeyEd25519().PubKey()
+	_, err := kb.CreateOffline("mykey", keyPub)
+	require.Nil(t, err)
+	contactAddr := newTestAddress()
+	require.Nil(t, store.SetContact(Contact{Name: "alice", Address: sdk.MustBech32ifyAcc(contactAddr)}))
+
+	addr, err := GetAccAddress(sdk.MustBech32ifyAcc(contactAddr))
+	require.Nil(t, err)
+	assert.Equal(t, contactAddr, addr)
+	_, err = GetAccAddress("invalid")
+	assert.NotNil(t, err)
+
+	addr, err = GetAccAddress("@alice")
+	require.Nil(t, err)
+	assert.Equal(t, contactAddr, addr)
+	addr, err = GetAccAddress("@mykey")
+	require.Nil(t, err)
+	assert.Equal(t, sdk.Address(keyPub.Address()), addr)
+	_, err = GetAccAddress("@unknown")
+	assert.NotNil(t, err)
+
+	// contacts can't be named after keys
+	exists, err := keyExists("mykey")
+	require.Nil(t, err)
+	assert.True(t, exists)
+	exists, err = keyExists("alice")
+	require.Nil(t, err)
+	assert.False(t, exists)
+
+	// a key named after a contact makes the name ambiguous
+	_, err = kb.CreateOffline("alice", tcrypto.GenPrivKeyEd25519().PubKey())
+	require.Nil(t, err)
+	_, err = GetAccAddress("@alice")
+	assert.NotNil(t, err)
+}
@@ -1,9 +1,10 @@
 package keys
 
 import (
-	"github.com/tepleton/tepleton-sdk/client"
-	"github.com/gorilla/mux"
 	"github.com/spf13/cobra"
+
+	"github.com/tepleton/tepleton-sdk/client"
+	"github.com/tepleton/tepleton-sdk/client/openapi"
 )
 
 // Commands registers a sub-tree of commands to interact with
@@ -22,19 +23,119 @@ func Commands() *cobra.Command {
 		addKeyCommand(),
 		listKeysCmd,
 		showKeysCmd,
+		metadataCommand(),
+		client.LineBreak,
+		contactsCommand(),
 		client.LineBreak,
 		deleteKeyCommand(),
 		updateKeyCommand(),
+		exportKeyCommand(),
+		importKeyCommand(),
+		migrateKeysCommand(),
 	)
+	cmd.PersistentFlags().String(client.FlagKeyringBackend, "db", "Keyring to store keys in (db|file|memory|test)")
 	return cmd
 }
 
 // resgister REST routes
-func RegisterRoutes(r *mux.Router) {
-	r.HandleFunc("/keys", QueryKeysRequestHandler).Methods("GET")
-	r.HandleFunc("/keys", AddNewKeyRequestHandler).Methods("POST")
-	r.HandleFunc("/keys/seed", SeedRequestHandler).Methods("GET")
-	r.HandleFunc("/keys/{name}", GetKeyRequestHandler).Methods("GET")
-	r.HandleFunc("/keys/{name}", UpdateKeyRequestHandler).Methods("PUT")
-	r.HandleFunc("/keys/{name}", DeleteKeyRequestHandler).Methods("DELETE")
+func RegisterRoutes(r *openapi.Router) {
+	r.Handle(openapi.Route{
+		Method:   "GET",
+		Path:     "/keys",
+		Summary:  "List the locally stored keys",
+		Response: []KeyOutput{},
+		Handler:  QueryKeysRequestHandler,
+	})
+	r.Handle(openapi.Route{
+		Method:   "POST",
+		Path:     "/keys",
+		Summary:  "Recover a key from a seed and store it locally",
+		Request:  NewKeyBody{},
+		Response: "",
+		Handler:  AddNewKeyRequestHandler,
+	})
+	r.Handle(openapi.Route{
+		Method:   "GET",
+		Path:     "/keys/seed",
+		Summary:  "Generate a new seed without storing it",
+		Response: "",
+		Handler:  SeedRequestHandler,
+	})
+	r.Handle(openapi.Route{
+		Method:   "GET",
+		Path:     "/keys/contacts",
+		Summary:  "List the address book",
+		Response: []Contact{},
+		Handler:  QueryContactsRequestHandler,
+	})
+	r.Handle(openapi.Route{
+		Method:  "POST",
+		Path:    "/keys/contacts",
+		Summary: "Add a contact to the address book",
+		Request: Contact{},
+		Handler: AddContactRequestHandler,
+	})
+	r.Handle(openapi.Route{
+		Method:   "GET",
+		Path:     "/keys/contacts/{contact}",
+		Summary:  "Get a contact of the address book",
+		Response: Contact{},
+		Handler:  GetContactRequestHandler,
+	})
+	r.Handle(openapi.Route{
+		Method:  "PUT",
+		Path:    "/keys/contacts/{contact}",
+		Summary: "Change the address and the notes of a contact",
+		Request: UpdateContactBody{},
+		Handler: UpdateContactRequestHandler,
+	})
+	r.Handle(openapi.Route{
+		Method:  "DELETE",
+		Path:    "/keys/contacts/{contact}",
+		Summary: "Remove a contact from the address book",
+		Handler: DeleteContactRequestHandler,
+	})
+	r.Handle(openapi.Route{
+		Method:   "GET",
+		Path:     "/keys/{name}",
+		Summary:  "Get a locally stored key",
+		Response: KeyOutput{},
+		Handler:  GetKeyRequestHandler,
+	})
+	r.Handle(openapi.Route{
+		Method:  "PUT",
+		Path:    "/keys/{name}",
+		Summary: "Change the password of a locally stored key",
+		Request: UpdateKeyBody{},
+		Handler: UpdateKeyRequestHandler,
+	})
+	r.Handle(openapi.Route{
+		Method:  "DELETE",
+		Path:    "/keys/{name}",
+		Summary: "Delete a locally stored key",
+		Request: DeleteKeyBody{},
+		Handler: DeleteKeyRequestHandler,
+	})
+	r.Handle(openapi.Route{
+		Method:  "PUT",
+		Path:    "/keys/{name}/metadata",
+		Summary: "Replace the metadata of a stored key",
+		Request: SetKeyMetadataBody{},
+		Handler: SetKeyMetadataRequestHandler,
+	})
+	r.Handle(openapi.Route{
+		Method:   "POST",
+		Path:     "/keys/{name}/unlock",
+		Summary:  "Unlock a key for a bounded signing session",
+		Request:  UnlockKeyBody{},
+		Response: UnlockKeyOutput{},
+		Handler:  UnlockKeyRequestHandler,
+	})
+	r.Handle(openapi.Route{
+		Method:  "DELETE",
+		Path:    "/keys/{name}/unlock",
+		Summary: "End a signing session before it expires",
+		Request: LockKeyBody{},
+		Handler: LockKeyRequestHandler,
+	})
 }
@@ -0,0 +1,206 @@
+package keys
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	crypto "github.com/tepleton/tepleton/crypto"
+)
+
+// DefaultSessionTTL is the lifetime of an unlocked key session if the
+// client does not request a shorter one
+const DefaultSessionTTL = 5 * time.Minute
+
+// MaxSessionTTL bounds how long a key may stay unlocked in the LCD
+const MaxSessionTTL = time.Hour
+
+// errors returned when a session can't be used for signing
+var (
+	ErrSessionNotFound = errors.New("session not found or expired")
+	ErrSessionScope    = errors.New("session is not valid for this key")
+)
+
+// session holds an unlocked key for a bounded amount of time. The
+// passphrase never leaves the LCD process and is dropped on expiry.
+type session struct {
+	name       string
+	passphrase string
+	expires    time.Time
+}
+
+// sessionStore keeps the unlocked key sessions of the LCD in memory
+type sessionStore struct {
+	mtx      sync.Mutex
+	sessions map[string]session
+}
+
+var sessions = &sessionStore{sessions: make(map[string]session)}
+
+// open unlocks name for ttl and returns the token scoped to that key
+func (s *sessionStore) open(name, passphrase string, ttl time.Duration) (string, time.Time, error) {
+	bz := make([]byte, 32)
+	if _, err := rand.Read(bz); err != nil {
+		return "", time.Time{}, err
+	}
+	token := hex.EncodeToString(bz)
+	expires := time.Now().Add(ttl)
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.prune()
+	s.sessions[token] = session{name, passphrase, expires}
+	return token, expires, nil
+}
+
+// get returns the live session for token, checking it is scoped to name
+func (s *sessionStore) get(token, name string) (session, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.prune()
+	sess, ok := s.sessions[token]
+	if !ok {
+		return session{}, ErrSessionNotFound
+	}
+	if sess.name != name {
+		return session{}, ErrSessionScope
+	}
+	return sess, nil
+}
+
+// close drops the session behind token, if any
+func (s *sessionStore) close(token string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	delete(s.sessions, token)
+}
+
+// prune drops all expired sessions, the caller must hold the lock
+func (s *sessionStore) prune() {
+	now := time.Now()
+	for token, sess := range s.sessions {
+		if now.After(sess.expires) {
+			delete(s.sessions, token)
+		}
+	}
+}
+
+// SignWithSession signs msg with the key name, unlocked by the session token
+func SignWithSession(token, name string, msg []byte) (crypto.Signature, crypto.PubKey, error) {
+	sess, err := sessions.get(token, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	kb, err := GetKeyBase()
+	if err != nil {
+		return nil, nil, err
+	}
+	return kb.Sign(sess.name, sess.passphrase, msg)
+}
+
+///////////////////////
+// REST
+
+// unlock key request REST body
+type UnlockKeyBody struct {
+	Password string `json:"password"`
+	// TTL in seconds, defaults to DefaultSessionTTL
+	TTL int64 `json:"ttl"`
+}
+
+// unlock key REST response
+type UnlockKeyOutput struct {
+	Name    string    `json:"name"`
+	Token   string    `json:"token"`
+	Expires time.Time `json:"expires"`
+}
+
+// lock key request REST body
+type LockKeyBody struct {
+	Token string `json:"token"`
+}
+
+// unlock key REST handler, opens a signing session scoped to one key
+func UnlockKeyRequestHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name := vars["name"]
+	var m UnlockKeyBody
+
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&m)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	ttl := time.Duration(m.TTL) * time.Second
+	if ttl <= 0 {
+		ttl = DefaultSessionTTL
+	}
+	if ttl > MaxSessionTTL {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Requested session ttl exceeds the maximum of " + MaxSessionTTL.String()))
+		return
+	}
+
+	kb, err := GetKeyBase()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	// prove the passphrase unlocks the key before handing out a token
+	_, _, err = kb.Sign(name, m.Password, []byte(name))
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	token, expires, err := sessions.open(name, m.Password, ttl)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	output, err := json.MarshalIndent(UnlockKeyOutput{name, token, expires}, "", "  ")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	w.Write(output)
+}
+
+// lock key REST handler, ends a signing session before it expires
+func LockKeyRequestHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name := vars["name"]
+	var m LockKeyBody
+
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&m)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	_, err = sessions.get(m.Token, name)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	sessions.close(m.Token)
+
+	w.WriteHeader(http.StatusOK)
+}
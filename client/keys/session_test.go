@@ -0,0 +1,66 @@
+package keys
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSessionStore() *sessionStore {
+	return &sessionStore{sessions: make(map[string]session)}
+}
+
+func TestSessionOpenGet(t *testing.T) {
+	s := newTestSessionStore()
+	token, expires, err := s.open("alice", "passphrase", time.Minute)
+	require.Nil(t, err)
+	assert.Len(t, token, 64)
+	assert.True(t, expires.After(time.Now()))
+
+	sess, err := s.get(token, "alice")
+	require.Nil(t, err)
+	assert.Equal(t, "alice", sess.name)
+	assert.Equal(t, "passphrase", sess.passphrase)
+
+	// tokens are scoped to their key
+	_, err = s.get(token, "bob")
+	assert.Equal(t, ErrSessionScope, err)
+	_, err = s.get("unknown", "alice")
+	assert.Equal(t, ErrSessionNotFound, err)
+
+	// every session gets its own token
+	other, _, err := s.open("alice", "passphrase", time.Minute)
+	require.Nil(t, err)
+	assert.NotEqual(t, token, other)
+}
+
+func TestSessionExpire(t *testing.T) {
+	s := newTestSessionStore()
+	short, _, err := s.open("alice", "passphrase", 10*time.Millisecond)
+	require.Nil(t, err)
+	long, _, err := s.open("bob", "passphrase", time.Minute)
+	require.Nil(t, err)
+
+	time.Sleep(20 * time.Millisecond)
+	_, err = s.get(short, "alice")
+	assert.Equal(t, ErrSessionNotFound, err)
+	_, err = s.get(long, "bob")
+	assert.Nil(t, err)
+
+	// expired sessions are dropped, the passphrase with them
+	assert.Len(t, s.sessions, 1)
+}
+
+func TestSessionClose(t *testing.T) {
+	s := newTestSessionStore()
+	token, _, err := s.open("alice", "passphrase", time.Minute)
+	require.Nil(t, err)
+	s.close(token)
+	_, err = s.get(token, "alice")
+	assert.Equal(t, ErrSessionNotFound, err)
+
+	// closing an unknown token is a no-op
+	s.close("unknown")
+}
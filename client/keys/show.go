@@ -5,9 +5,9 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
-	keys "github.com/tepleton/go-crypto/keys"
-
 	"github.com/spf13/cobra"
+
+	"github.com/tepleton/tepleton-sdk/crypto/keys"
 )
 
 var showKeysCmd = &cobra.Command{
@@ -28,7 +28,7 @@ var showKeysCmd = &cobra.Command{
 func getKey(name string) (keys.Info, error) {
 	kb, err := GetKeyBase()
 	if err != nil {
-		return keys.Info{}, err
+		return nil, err
 	}
 
 	return kb.Get(name)
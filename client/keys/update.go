@@ -5,11 +5,11 @@ import (
 	"fmt"
 	"net/http"
 
-	"github.com/tepleton/tepleton-sdk/client"
 	"github.com/gorilla/mux"
-	keys "github.com/tepleton/go-crypto/keys"
-
 	"github.com/spf13/cobra"
+
+	"github.com/tepleton/tepleton-sdk/client"
+	"github.com/tepleton/tepleton-sdk/crypto/keys"
 )
 
 func updateKeyCommand() *cobra.Command {
@@ -25,6 +25,10 @@ func updateKeyCommand() *cobra.Command {
 func runUpdateCmd(cmd *cobra.Command, args []string) error {
 	name := args[0]
 
+	if usesTestBackend() {
+		return fmt.Errorf("keys of the %s keyring have no passphrase to update", BackendTest)
+	}
+
 	buf := client.BufferStdin()
 	oldpass, err := client.GetPassword(
 		"Enter the current passphrase:", buf)
@@ -2,15 +2,13 @@ package keys
 
 import (
 	"fmt"
-	"path/filepath"
 
 	"github.com/spf13/viper"
 
-	keys "github.com/tepleton/go-crypto/keys"
 	"github.com/tepleton/tmlibs/cli"
-	dbm "github.com/tepleton/tmlibs/db"
 
 	"github.com/tepleton/tepleton-sdk/client"
+	"github.com/tepleton/tepleton-sdk/crypto/keys"
 
 	sdk "github.com/tepleton/tepleton-sdk/types"
 )
@@ -21,22 +19,22 @@ const KeyDBName = "keys"
 // keybase is used to make GetKeyBase a singleton
 var keybase keys.Keybase
 
-// TODO make keybase take a database not load from the directory
-
 // initialize a keybase based on the configuration
 func GetKeyBase() (keys.Keybase, error) {
 	rootDir := viper.GetString(cli.HomeFlag)
 	return GetKeyBaseFromDir(rootDir)
 }
 
-// initialize a keybase based on the configuration
+// initialize a keybase based on the configuration,
+// the keyring backend is selected with --keyring-backend
 func GetKeyBaseFromDir(rootDir string) (keys.Keybase, error) {
 	if keybase == nil {
-		db, err := dbm.NewGoLevelDB(KeyDBName, filepath.Join(rootDir, "keys"))
+		backend := viper.GetString(client.FlagKeyringBackend)
+		storage, err := keyringStorage(backend, rootDir)
 		if err != nil {
 			return nil, err
 		}
-		keybase = client.GetKeyBase(db)
+		keybase = newKeybase(backend, storage)
 	}
 	return keybase, nil
 }
@@ -48,10 +46,11 @@ func SetKeyBase(kb keys.Keybase) {
 
 // used for outputting keys.Info over REST
 type KeyOutput struct {
-	Name    string `json:"name"`
-	Address string `json:"address"`
-	PubKey  string `json:"pub_key"`
-	Seed    string `json:"seed,omitempty"`
+	Name     string            `json:"name"`
+	Address  string            `json:"address"`
+	PubKey   string            `json:"pub_key"`
+	Seed     string            `json:"seed,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 // create a list of KeyOutput in bech32 format
@@ -69,18 +68,23 @@ func Bech32KeysOutput(infos []keys.Info) ([]KeyOutput, error) {
 
 // create a KeyOutput in bech32 format
 func Bech32KeyOutput(info keys.Info) (KeyOutput, error) {
-	bechAccount, err := sdk.Bech32ifyAcc(sdk.Address(info.PubKey.Address().Bytes()))
+	bechAccount, err := sdk.Bech32ifyAcc(sdk.Address(info.GetPubKey().Address().Bytes()))
 	if err != nil {
 		return KeyOutput{}, err
 	}
-	bechPubKey, err := sdk.Bech32ifyAccPub(info.PubKey)
+	bechPubKey, err := sdk.Bech32ifyAccPub(info.GetPubKey())
 	if err != nil {
 		return KeyOutput{}, err
 	}
+	md := info.GetMetadata()
+	if len(md) == 0 {
+		md = nil
+	}
 	return KeyOutput{
-		Name:    info.Name,
-		Address: bechAccount,
-		PubKey:  bechPubKey,
+		Name:     info.GetName(),
+		Address:  bechAccount,
+		PubKey:   bechPubKey,
+		Metadata: md,
 	}, nil
 }
 
@@ -93,6 +97,10 @@ func printInfo(info keys.Info) {
 	case "text":
 		fmt.Printf("NAME:\tADDRESS:\t\t\t\t\t\tPUBKEY:\n")
 		printKeyOutput(ko)
+		if len(ko.Metadata) > 0 {
+			fmt.Println()
+			printMetadata(ko.Metadata)
+		}
 	case "json":
 		out, err := MarshalJSON(ko)
 		if err != nil {
@@ -12,13 +12,14 @@ import (
 	"github.com/stretchr/testify/require"
 
 	wrsp "github.com/tepleton/wrsp/types"
-	cryptoKeys "github.com/tepleton/go-crypto/keys"
 	p2p "github.com/tepleton/tepleton/p2p"
 	ctypes "github.com/tepleton/tepleton/rpc/core/types"
 
 	client "github.com/tepleton/tepleton-sdk/client"
 	keys "github.com/tepleton/tepleton-sdk/client/keys"
+	"github.com/tepleton/tepleton-sdk/client/openapi"
 	rpc "github.com/tepleton/tepleton-sdk/client/rpc"
+	cryptoKeys "github.com/tepleton/tepleton-sdk/crypto/keys"
 	tests "github.com/tepleton/tepleton-sdk/tests"
 	sdk "github.com/tepleton/tepleton-sdk/types"
 	"github.com/tepleton/tepleton-sdk/x/auth"
@@ -104,6 +105,77 @@ func TestKeys(t *testing.T) {
 	require.Equal(t, http.StatusOK, res.StatusCode, body)
 }
 
+func TestKeysMetadataAndContacts(t *testing.T) {
+	name, password := "test", "1234567890"
+	addr, _ := CreateAddr(t, name, password, GetKB(t))
+	cleanup, _, port := InitializeTestLCD(t, 1, []sdk.Address{addr})
+	defer cleanup()
+	addrBech32 := sdk.MustBech32ifyAcc(addr)
+
+	// key metadata
+	keyEndpoint := fmt.Sprintf("/keys/%s", name)
+	jsonStr := []byte(`{"metadata":{"note":"hot wallet","tags":"ops"}}`)
+	res, body := Request(t, port, "PUT", keyEndpoint+"/metadata", jsonStr)
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	res, body = Request(t, port, "PUT", "/keys/unknown/metadata", jsonStr)
+	require.Equal(t, http.StatusNotFound, res.StatusCode, body)
+
+	res, body = Request(t, port, "GET", keyEndpoint, nil)
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	var key keys.KeyOutput
+	err := cdc.UnmarshalJSON([]byte(body), &key)
+	require.Nil(t, err)
+	assert.Equal(t, map[string]string{"note": "hot wallet", "tags": "ops"}, key.Metadata)
+
+	// address book
+	res, body = Request(t, port, "GET", "/keys/contacts", nil)
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	require.Equal(t, "[]", body)
+
+	jsonStr = []byte(fmt.Sprintf(`{"name":"alice","address":"%s","notes":"exchange deposit"}`, addrBech32))
+	res, body = Request(t, port, "POST", "/keys/contacts", jsonStr)
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	res, body = Request(t, port, "POST", "/keys/contacts", jsonStr)
+	require.Equal(t, http.StatusConflict, res.StatusCode, body)
+	res, body = Request(t, port, "POST", "/keys/contacts", []byte(`{"name":"bob","address":"invalid"}`))
+	require.Equal(t, http.StatusBadRequest, res.StatusCode, body)
+	jsonStr = []byte(fmt.Sprintf(`{"name":"%s","address":"%s"}`, name, addrBech32))
+	res, body = Request(t, port, "POST", "/keys/contacts", jsonStr)
+	require.Equal(t, http.StatusConflict, res.StatusCode, body)
+
+	res, body = Request(t, port, "GET", "/keys/contacts/alice", nil)
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	var contact keys.Contact
+	err = json.Unmarshal([]byte(body), &contact)
+	require.Nil(t, err)
+	assert.Equal(t, keys.Contact{Name: "alice", Address: addrBech32, Notes: "exchange deposit"}, contact)
+
+	resolved, err := keys.GetAccAddress("@alice")
+	require.Nil(t, err)
+	assert.Equal(t, addr, resolved)
+	resolved, err = keys.GetAccAddress("@" + name)
+	require.Nil(t, err)
+	assert.Equal(t, addr, resolved)
+	_, err = keys.GetAccAddress("@unknown")
+	require.NotNil(t, err)
+
+	jsonStr = []byte(fmt.Sprintf(`{"address":"%s","notes":"closed"}`, addrBech32))
+	res, body = Request(t, port, "PUT", "/keys/contacts/alice", jsonStr)
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	res, body = Request(t, port, "GET", "/keys/contacts", nil)
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	var contacts []keys.Contact
+	err = json.Unmarshal([]byte(body), &contacts)
+	require.Nil(t, err)
+	require.Len(t, contacts, 1)
+	assert.Equal(t, "closed", contacts[0].Notes)
+
+	res, body = Request(t, port, "DELETE", "/keys/contacts/alice", nil)
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	res, body = Request(t, port, "GET", "/keys/contacts/alice", nil)
+	require.Equal(t, http.StatusNotFound, res.StatusCode, body)
+}
+
 func TestVersion(t *testing.T) {
 	cleanup, _, port := InitializeTestLCD(t, 1, []sdk.Address{})
 	defer cleanup()
@@ -118,6 +190,30 @@ func TestVersion(t *testing.T) {
 	assert.True(t, match, body)
 }
 
+func TestOpenAPI(t *testing.T) {
+	cleanup, _, port := InitializeTestLCD(t, 1, []sdk.Address{})
+	defer cleanup()
+
+	// every registered route must be described
+	missing, err := createRouter(cdc).Undescribed()
+	require.Nil(t, err)
+	require.Empty(t, missing, "Routes without a description")
+
+	res, body := Request(t, port, "GET", "/openapi.json", nil)
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+
+	var doc openapi.Document
+	err = json.Unmarshal([]byte(body), &doc)
+	require.Nil(t, err)
+	_, ok := doc.Paths["/accounts/{address}/send"]["post"]
+	assert.True(t, ok, "Send route missing from the OpenAPI document")
+	_, ok = doc.Paths["/blocks/{height}"]["get"]
+	assert.True(t, ok, "Block route missing from the OpenAPI document")
+	for path := range doc.Paths {
+		assert.NotContains(t, path, ":", "Mux pattern in OpenAPI path")
+	}
+}
+
 func TestNodeStatus(t *testing.T) {
 	cleanup, _, port := InitializeTestLCD(t, 1, []sdk.Address{})
 	defer cleanup()
@@ -418,9 +514,9 @@ func doSend(t *testing.T, port, seed, name, password string, addr sdk.Address) (
 
 	// create receive address
 	kb := client.MockKeyBase()
-	receiveInfo, _, err := kb.Create("receive_address", "1234567890", cryptoKeys.CryptoAlgo("ed25519"))
+	receiveInfo, _, err := kb.CreateMnemonic("receive_address", cryptoKeys.English, "1234567890", "", cryptoKeys.Ed25519)
 	require.Nil(t, err)
-	receiveAddr = receiveInfo.PubKey.Address()
+	receiveAddr = sdk.Address(receiveInfo.GetPubKey().Address())
 	receiveAddrBech := sdk.MustBech32ifyAcc(receiveAddr)
 
 	acc := getAccount(t, port, addr)
@@ -453,9 +549,9 @@ func doSend(t *testing.T, port, seed, name, password string, addr sdk.Address) (
 func doIBCTransfer(t *testing.T, port, seed, name, password string, addr sdk.Address) (resultTx ctypes.ResultBroadcastTxCommit) {
 	// create receive address
 	kb := client.MockKeyBase()
-	receiveInfo, _, err := kb.Create("receive_address", "1234567890", cryptoKeys.CryptoAlgo("ed25519"))
+	receiveInfo, _, err := kb.CreateMnemonic("receive_address", cryptoKeys.English, "1234567890", "", cryptoKeys.Ed25519)
 	require.Nil(t, err)
-	receiveAddr := receiveInfo.PubKey.Address()
+	receiveAddr := sdk.Address(receiveInfo.GetPubKey().Address())
 	receiveAddrBech := sdk.MustBech32ifyAcc(receiveAddr)
 
 	// get the account to get the sequence
@@ -0,0 +1,217 @@
+package lcd
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tepleton/tepleton-sdk/client"
+)
+
+// authConfig describes the credentials a client has to present, and
+// which groups of routes they are required for. An empty token and user
+// means no authentication is configured.
+type authConfig struct {
+	Token    string
+	User     string
+	Password string
+
+	Keys bool // require credentials on /keys routes
+	Sign bool // require credentials on routes that sign with a local key
+}
+
+func (c authConfig) enabled() bool {
+	return c.Token != "" || c.User != ""
+}
+
+// validate refuses incomplete credentials, and credentials that guard no
+// routes, which would leave the rest-server open while looking protected
+func (c authConfig) validate() error {
+	if (c.User == "") != (c.Password == "") {
+		return errors.New("--auth-user and --auth-password must be set together")
+	}
+	if (c.Keys || c.Sign) && !c.enabled() {
+		return errors.New("--auth-keys and --auth-sign require --auth-token or --auth-user")
+	}
+	if c.enabled() && !c.Keys && !c.Sign {
+		return errors.New("--auth-token and --auth-user require --auth-keys or --auth-sign to protect any route")
+	}
+	return nil
+}
+
+// isKeyRoute - the key-management routes registered by keys.RegisterRoutes
+func isKeyRoute(r *http.Request) bool {
+	return r.URL.Path == "/keys" || strings.HasPrefix(r.URL.Path, "/keys/")
+}
+
+// isSignRoute - every POST outside of key management signs with a local key
+// (/txs/sign, /accounts/{address}/send, /ibc/..., /stake/delegations)
+func isSignRoute(r *http.Request) bool {
+	return r.Method == http.MethodPost && !isKeyRoute(r)
+}
+
+// authorized checks the bearer token or basic-auth credentials of r
+func (c authConfig) authorized(r *http.Request) bool {
+	if c.Token != "" {
+		auth := r.Header.Get("Authorization")
+		if strings.HasPrefix(auth, "Bearer ") &&
+			subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, "Bearer ")), []byte(c.Token)) == 1 {
+			return true
+		}
+	}
+	if c.User != "" && c.Password != "" {
+		user, pass, ok := r.BasicAuth()
+		if ok &&
+			subtle.ConstantTimeCompare([]byte(user), []byte(c.User)) == 1 &&
+			subtle.ConstantTimeCompare([]byte(pass), []byte(c.Password)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// authHandler rejects unauthenticated requests to the protected route groups
+func authHandler(c authConfig, h http.Handler) http.Handler {
+	if !c.enabled() {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		protected := (c.Keys && isKeyRoute(r)) || (c.Sign && isSignRoute(r))
+		if protected && !c.authorized(r) {
+			if c.User != "" {
+				w.Header().Set("WWW-Authenticate", `Basic realm="rest-server"`)
+			}
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte("Unauthorized"))
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// corsHandler sets the CORS headers for the allowed origins and answers
+// preflight requests. origins is a comma separated list, "*" allows all
+// origins but then no credentials, so that no website can use the
+// credentials of a browser against the rest-server.
+func corsHandler(origins string, h http.Handler) http.Handler {
+	allowed := client.ParseAllowedOrigins(origins)
+	if allowed.Empty() {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if allowed.Allows(origin) {
+			if allowed.Any() {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+				w.Header().Add("Vary", "Origin")
+			}
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+				w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// bucket is a token bucket refilled at the limiter rate
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// interval at which the buckets of idle clients are evicted
+const bucketSweepInterval = time.Minute
+
+// rateLimiter applies a token bucket per client
+type rateLimiter struct {
+	mtx       sync.Mutex
+	rate      float64 // tokens per second
+	burst     float64
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// allow takes a token from the bucket of client, if there is one
+func (l *rateLimiter) allow(client string, now time.Time) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if now.Sub(l.lastSweep) >= bucketSweepInterval {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[client] = b
+	}
+	b.tokens += now.Sub(b.last).Seconds() * l.rate
+	if b.tokens > l.burst {
+		b.tokens = l.burst
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// sweep evicts the buckets that refilled completely, they are the same
+// as the bucket of a new client. The caller must hold the lock.
+func (l *rateLimiter) sweep(now time.Time) {
+	refill := time.Duration(l.burst / l.rate * float64(time.Second))
+	for client, b := range l.buckets {
+		if now.Sub(b.last) >= refill {
+			delete(l.buckets, client)
+		}
+	}
+	l.lastSweep = now
+}
+
+// clientID identifies the client of r by its remote IP. Credentials are
+// not used, as unauthenticated requests could pick a new one every time.
+func clientID(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// rateLimitHandler limits every client to rate requests per second
+// with bursts of up to burst requests. A rate of 0 disables limiting.
+func rateLimitHandler(rate float64, burst int, h http.Handler) http.Handler {
+	if rate <= 0 {
+		return h
+	}
+	limiter := newRateLimiter(rate, burst)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !limiter.allow(clientID(r), time.Now()) {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte("Rate limit exceeded"))
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
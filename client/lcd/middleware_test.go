@@ -0,0 +1,122 @@
+package lcd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+func serve(h http.Handler, r *http.Request) int {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w.Code
+}
+
+func TestAuthHandler(t *testing.T) {
+	h := authHandler(authConfig{Token: "secret", User: "alice", Password: "pw", Keys: true}, okHandler)
+
+	// key routes are protected
+	r := httptest.NewRequest("GET", "/keys", nil)
+	assert.Equal(t, http.StatusUnauthorized, serve(h, r))
+	r.Header.Set("Authorization", "Bearer secret")
+	assert.Equal(t, http.StatusOK, serve(h, r))
+	r = httptest.NewRequest("DELETE", "/keys/foo", nil)
+	r.SetBasicAuth("alice", "pw")
+	assert.Equal(t, http.StatusOK, serve(h, r))
+	r.SetBasicAuth("alice", "wrong")
+	assert.Equal(t, http.StatusUnauthorized, serve(h, r))
+
+	// signing routes are not, unless configured
+	r = httptest.NewRequest("POST", "/txs/sign", nil)
+	assert.Equal(t, http.StatusOK, serve(h, r))
+	h = authHandler(authConfig{Token: "secret", Sign: true}, okHandler)
+	assert.Equal(t, http.StatusUnauthorized, serve(h, r))
+	r = httptest.NewRequest("GET", "/keys", nil)
+	assert.Equal(t, http.StatusOK, serve(h, r))
+}
+
+func TestAuthConfigValidate(t *testing.T) {
+	cases := []struct {
+		config authConfig
+		valid  bool
+	}{
+		{authConfig{}, true},
+		{authConfig{Token: "secret", Keys: true}, true},
+		{authConfig{User: "alice", Password: "pw", Sign: true}, true},
+		{authConfig{User: "alice", Keys: true}, false},
+		{authConfig{Password: "pw", Keys: true}, false},
+		{authConfig{Keys: true}, false},
+		{authConfig{Token: "secret"}, false},
+		{authConfig{User: "alice", Password: "pw"}, false},
+	}
+	for i, tc := range cases {
+		assert.Equal(t, tc.valid, tc.config.validate() == nil, "%d", i)
+	}
+
+	// basic auth never accepts an empty password
+	h := authHandler(authConfig{User: "alice", Keys: true}, okHandler)
+	r := httptest.NewRequest("GET", "/keys", nil)
+	r.SetBasicAuth("alice", "")
+	assert.Equal(t, http.StatusUnauthorized, serve(h, r))
+}
+
+func TestCORSHandler(t *testing.T) {
+	h := corsHandler("http://a.com, http://b.com", okHandler)
+
+	r := httptest.NewRequest("OPTIONS", "/keys", nil)
+	r.Header.Set("Origin", "http://b.com")
+	r.Header.Set("Access-Control-Request-Method", "POST")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "http://b.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+
+	r = httptest.NewRequest("GET", "/keys", nil)
+	r.Header.Set("Origin", "http://c.com")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "", w.Header().Get("Access-Control-Allow-Origin"))
+
+	// any origin, but never with credentials
+	h = corsHandler("*", okHandler)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "", w.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+func TestRateLimiter(t *testing.T) {
+	l := newRateLimiter(1, 2)
+	now := time.Now()
+	assert.True(t, l.allow("a", now))
+	assert.True(t, l.allow("a", now))
+	assert.False(t, l.allow("a", now))
+	// other clients have their own bucket
+	assert.True(t, l.allow("b", now))
+	// refilled after a second
+	assert.True(t, l.allow("a", now.Add(time.Second)))
+	assert.False(t, l.allow("a", now.Add(time.Second)))
+
+	// idle clients are evicted
+	assert.Len(t, l.buckets, 2)
+	assert.True(t, l.allow("c", now.Add(bucketSweepInterval)))
+	assert.Len(t, l.buckets, 1)
+}
+
+func TestClientID(t *testing.T) {
+	r := httptest.NewRequest("GET", "/keys", nil)
+	r.RemoteAddr = "1.2.3.4:5678"
+	assert.Equal(t, "1.2.3.4", clientID(r))
+	// credentials don't give a new bucket
+	r.Header.Set("Authorization", "Bearer foo")
+	assert.Equal(t, "1.2.3.4", clientID(r))
+}
@@ -1,6 +1,8 @@
 package lcd
 
 import (
+	"errors"
+	"net"
 	"net/http"
 	"os"
 
@@ -15,6 +17,7 @@ import (
 	client "github.com/tepleton/tepleton-sdk/client"
 	"github.com/tepleton/tepleton-sdk/client/context"
 	keys "github.com/tepleton/tepleton-sdk/client/keys"
+	"github.com/tepleton/tepleton-sdk/client/openapi"
 	rpc "github.com/tepleton/tepleton-sdk/client/rpc"
 	tx "github.com/tepleton/tepleton-sdk/client/tx"
 	version "github.com/tepleton/tepleton-sdk/version"
@@ -30,17 +33,51 @@ import (
 // to the cli, but over rest
 func ServeCommand(cdc *wire.Codec) *cobra.Command {
 	flagListenAddr := "laddr"
-	flagCORS := "cors"
+	flagTLSCert := "tls-cert"
+	flagTLSKey := "tls-key"
+	flagAuthToken := "auth-token"
+	flagAuthUser := "auth-user"
+	flagAuthPassword := "auth-password"
+	flagAuthKeys := "auth-keys"
+	flagAuthSign := "auth-sign"
+	flagRateLimit := "rate-limit"
+	flagRateBurst := "rate-burst"
 
 	cmd := &cobra.Command{
 		Use:   "rest-server",
 		Short: "Start LCD (light-client daemon), a local REST server",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			listenAddr := viper.GetString(flagListenAddr)
+			certFile := viper.GetString(flagTLSCert)
+			keyFile := viper.GetString(flagTLSKey)
+			if (certFile == "") != (keyFile == "") {
+				return errors.New("both --tls-cert and --tls-key are required to enable TLS")
+			}
+			auth := authConfig{
+				Token:    viper.GetString(flagAuthToken),
+				User:     viper.GetString(flagAuthUser),
+				Password: viper.GetString(flagAuthPassword),
+				Keys:     viper.GetBool(flagAuthKeys),
+				Sign:     viper.GetBool(flagAuthSign),
+			}
+			if err := auth.validate(); err != nil {
+				return err
+			}
+
 			handler := createHandler(cdc)
+			handler = authHandler(auth, handler)
+			handler = rateLimitHandler(viper.GetFloat64(flagRateLimit), viper.GetInt(flagRateBurst), handler)
+			handler = corsHandler(viper.GetString(client.FlagCORS), handler)
+
 			logger := log.NewTMLogger(log.NewSyncWriter(os.Stdout)).
 				With("module", "rest-server")
-			listener, err := tmserver.StartHTTPServer(listenAddr, handler, logger)
+			var listener net.Listener
+			var err error
+			if certFile != "" {
+				listener, err = tmserver.StartHTTPAndTLSServer(listenAddr, handler, certFile, keyFile, logger)
+			} else {
+				listener, err = tmserver.StartHTTPServer(listenAddr, handler, logger)
+			}
 			if err != nil {
 				return err
 			}
@@ -55,15 +92,36 @@ func ServeCommand(cdc *wire.Codec) *cobra.Command {
 		},
 	}
 	cmd.Flags().StringP(flagListenAddr, "a", "tcp://localhost:1317", "Address for server to listen on")
-	cmd.Flags().String(flagCORS, "", "Set to domains that can make CORS requests (* for all)")
+	cmd.Flags().String(client.FlagCORS, "", "Set to domains that can make CORS requests (* for all, without credentials)")
+	cmd.Flags().String(flagTLSCert, "", "Path to a TLS certificate file, enables TLS together with --tls-key")
+	cmd.Flags().String(flagTLSKey, "", "Path to the TLS private key file")
+	cmd.Flags().String(flagAuthToken, "", "API token accepted as 'Authorization: Bearer <token>'")
+	cmd.Flags().String(flagAuthUser, "", "User name accepted through basic auth")
+	cmd.Flags().String(flagAuthPassword, "", "Password accepted through basic auth")
+	cmd.Flags().Bool(flagAuthKeys, false, "Require authentication on key management routes")
+	cmd.Flags().Bool(flagAuthSign, false, "Require authentication on routes signing with a local key")
+	cmd.Flags().Float64(flagRateLimit, 0, "Requests per second allowed per client (0 for no limit)")
+	cmd.Flags().Int(flagRateBurst, 10, "Requests a client may burst above the rate limit")
 	cmd.Flags().StringP(client.FlagChainID, "c", "", "ID of chain we connect to")
 	cmd.Flags().StringP(client.FlagNode, "n", "tcp://localhost:46657", "Node to connect to")
+	cmd.Flags().String(client.FlagKeyringBackend, "db", "Keyring the keys are stored in (db|file|memory|test)")
 	return cmd
 }
 
 func createHandler(cdc *wire.Codec) http.Handler {
-	r := mux.NewRouter()
-	r.HandleFunc("/version", version.RequestHandler).Methods("GET")
+	return createRouter(cdc)
+}
+
+// createRouter registers all LCD routes along with their descriptions
+func createRouter(cdc *wire.Codec) *openapi.Router {
+	r := openapi.NewRouter(mux.NewRouter())
+	r.Handle(openapi.Route{
+		Method:   "GET",
+		Path:     "/version",
+		Summary:  "Version of the rest-server",
+		Response: "",
+		Handler:  version.RequestHandler,
+	})
 
 	kb, err := keys.GetKeyBase() //XXX
 	if err != nil {
@@ -80,5 +138,6 @@ func createHandler(cdc *wire.Codec) http.Handler {
 	bank.RegisterRoutes(ctx, r, cdc, kb)
 	ibc.RegisterRoutes(ctx, r, cdc, kb)
 	stake.RegisterRoutes(ctx, r, cdc, kb)
+	r.RegisterDocRoutes("Light Client Daemon", version.Version)
 	return r
 }
@@ -17,7 +17,6 @@ import (
 
 	wrsp "github.com/tepleton/wrsp/types"
 	crypto "github.com/tepleton/go-crypto"
-	crkeys "github.com/tepleton/go-crypto/keys"
 	tmcfg "github.com/tepleton/tepleton/config"
 	nm "github.com/tepleton/tepleton/node"
 	pvm "github.com/tepleton/tepleton/privval"
@@ -31,6 +30,7 @@ import (
 	"github.com/tepleton/tepleton-sdk/client"
 	keys "github.com/tepleton/tepleton-sdk/client/keys"
 	gapp "github.com/tepleton/tepleton-sdk/cmd/ton/app"
+	crkeys "github.com/tepleton/tepleton-sdk/crypto/keys"
 	"github.com/tepleton/tepleton-sdk/server"
 	"github.com/tepleton/tepleton-sdk/tests"
 	sdk "github.com/tepleton/tepleton-sdk/types"
@@ -83,9 +83,9 @@ func GetKB(t *testing.T) crkeys.Keybase {
 func CreateAddr(t *testing.T, name, password string, kb crkeys.Keybase) (addr sdk.Address, seed string) {
 	var info crkeys.Info
 	var err error
-	info, seed, err = kb.Create(name, password, crkeys.AlgoEd25519)
+	info, seed, err = kb.CreateMnemonic(name, crkeys.English, password, "", crkeys.Ed25519)
 	require.NoError(t, err)
-	addr = info.PubKey.Address()
+	addr = sdk.Address(info.GetPubKey().Address())
 	return
 }
 
@@ -116,10 +116,13 @@ func InitializeTestLCD(t *testing.T, nValidators int, initAddrs []sdk.Address) (
 	if nValidators < 1 {
 		panic("InitializeTestLCD must use at least one validator")
 	}
+	privKeys := []crypto.PrivKey{privVal.PrivKey}
 	for i := 1; i < nValidators; i++ {
+		privKey := crypto.GenPrivKeyEd25519()
+		privKeys = append(privKeys, privKey)
 		genDoc.Validators = append(genDoc.Validators,
 			tmtypes.GenesisValidator{
-				PubKey: crypto.GenPrivKeyEd25519().PubKey(),
+				PubKey: privKey.PubKey(),
 				Power:  1,
 				Name:   "val",
 			},
@@ -127,17 +130,23 @@ func InitializeTestLCD(t *testing.T, nValidators int, initAddrs []sdk.Address) (
 	}
 
 	// NOTE it's bad practice to reuse pk address for the owner address but doing in the
-	// test for simplicity
+	// test for simplicity: the genesis transactions are signed with the validator keys
+	valKeybase := crkeys.NewInMemory()
 	var appGenTxs []json.RawMessage
-	for _, gdValidator := range genDoc.Validators {
+	for i, gdValidator := range genDoc.Validators {
 		pk := gdValidator.PubKey
 		validatorsPKs = append(validatorsPKs, pk) // append keys for output
-		appGenTx, _, _, err := gapp.GaiaAppGenTxNF(cdc, pk, pk.Address(), "test_val1", true)
+		name := fmt.Sprintf("test_val%d", i+1)
+		_, err = valKeybase.ImportPrivKey(name, privKeys[i], "1234567890")
 		require.NoError(t, err)
+		appGenTx, _, validator, err := gapp.GaiaAppGenTxNF(cdc, pk, valKeybase, name, "1234567890",
+			genDoc.ChainID, sdk.Coin{"steak", 100})
+		require.NoError(t, err)
+		genDoc.Validators[i].Power = validator.Power
 		appGenTxs = append(appGenTxs, appGenTx)
 	}
 
-	genesisState, err := gapp.GaiaAppGenState(cdc, appGenTxs[:])
+	genesisState, err := gapp.GaiaAppGenState(cdc, genDoc.ChainID, appGenTxs)
 	require.NoError(t, err)
 
 	// add some tokens to init accounts
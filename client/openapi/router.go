@@ -0,0 +1,212 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// Route describes a REST route along with the Go types of its bodies.
+// Request and Response hold sample values of those types, nil if the
+// route has no body. Query describes the query parameters by name.
+type Route struct {
+	Method   string
+	Path     string
+	Summary  string
+	Query    map[string]string
+	Request  interface{}
+	Response interface{}
+	Handler  http.HandlerFunc
+}
+
+// Router registers routes on a mux.Router and keeps their description
+// to serve an OpenAPI document of all registered routes.
+type Router struct {
+	*mux.Router
+	routes []Route
+}
+
+// NewRouter wraps r, routes registered through Handle are described
+func NewRouter(r *mux.Router) *Router {
+	return &Router{Router: r}
+}
+
+// Handle registers route on the underlying router and records it
+func (r *Router) Handle(route Route) {
+	r.HandleFunc(route.Path, route.Handler).Methods(route.Method)
+	r.routes = append(r.routes, route)
+}
+
+// Routes returns the described routes in registration order
+func (r *Router) Routes() []Route {
+	return r.routes
+}
+
+// Undescribed returns the routes of the underlying router that were not
+// registered through Handle, or lack a summary, as "METHOD path"
+func (r *Router) Undescribed() ([]string, error) {
+	described := make(map[string]bool)
+	for _, route := range r.routes {
+		if route.Summary != "" {
+			described[route.Method+" "+route.Path] = true
+		}
+	}
+	var missing []string
+	err := r.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			methods = []string{"*"}
+		}
+		for _, method := range methods {
+			if !described[method+" "+path] {
+				missing = append(missing, method+" "+path)
+			}
+		}
+		return nil
+	})
+	return missing, err
+}
+
+// Document builds the OpenAPI document of the described routes
+func (r *Router) Document(title, version string) Document {
+	doc := Document{
+		OpenAPI: "3.0.0",
+		Info:    Info{Title: title, Version: version},
+		Paths:   make(map[string]PathItem),
+	}
+	for _, route := range r.routes {
+		path := docPath(route.Path)
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = make(PathItem)
+			doc.Paths[path] = item
+		}
+		item[strings.ToLower(route.Method)] = operation(route)
+	}
+	return doc
+}
+
+// RegisterDocRoutes serves the OpenAPI document at /openapi.json and a
+// browsable UI for it at /openapi
+func (r *Router) RegisterDocRoutes(title, version string) {
+	r.Handle(Route{
+		Method:  "GET",
+		Path:    "/openapi.json",
+		Summary: "OpenAPI document of the REST routes",
+		Handler: func(w http.ResponseWriter, req *http.Request) {
+			output, err := json.MarshalIndent(r.Document(title, version), "", "  ")
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(err.Error()))
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(output)
+		},
+	})
+	r.Handle(Route{
+		Method:  "GET",
+		Path:    "/openapi",
+		Summary: "Browsable UI of the OpenAPI document",
+		Handler: func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(fmt.Sprintf(uiPage, title)))
+		},
+	})
+}
+
+func operation(route Route) Operation {
+	op := Operation{
+		Summary:   route.Summary,
+		Responses: map[string]Response{"200": {Description: "OK"}},
+	}
+	for _, name := range pathParams(route.Path) {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+	names := make([]string, 0, len(route.Query))
+	for name := range route.Query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:        name,
+			In:          "query",
+			Description: route.Query[name],
+			Schema:      &Schema{Type: "string"},
+		})
+	}
+	if route.Request != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"application/json": {Schema: SchemaOf(route.Request)}},
+		}
+	}
+	if route.Response != nil {
+		op.Responses["200"] = Response{
+			Description: "OK",
+			Content:     map[string]MediaType{"application/json": {Schema: SchemaOf(route.Response)}},
+		}
+	}
+	return op
+}
+
+// pathParams returns the names of the {variables} in a route path
+func pathParams(path string) []string {
+	var names []string
+	for _, part := range strings.Split(path, "/") {
+		if name, ok := pathParam(part); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// docPath strips the mux patterns of the {variables} in a route path, as
+// OpenAPI paths name the variables only
+func docPath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if name, ok := pathParam(part); ok {
+			parts[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// pathParam returns the variable name of a {variable} path segment,
+// stripping mux patterns, as in {height:[0-9]+}
+func pathParam(part string) (string, bool) {
+	if !strings.HasPrefix(part, "{") || !strings.HasSuffix(part, "}") {
+		return "", false
+	}
+	name := strings.TrimSuffix(strings.TrimPrefix(part, "{"), "}")
+	return strings.SplitN(name, ":", 2)[0], true
+}
+
+const uiPage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>%s</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@3/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@3/swagger-ui-bundle.js"></script>
+  <script>SwaggerUIBundle({url: "/openapi.json", dom_id: "#swagger-ui"})</script>
+</body>
+</html>
+`
@@ -0,0 +1,77 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRequest struct {
+	Name    string           `json:"name"`
+	Amount  int64            `json:"amount"`
+	Tags    []string         `json:"tags,omitempty"`
+	Data    []byte           `json:"data"`
+	Extra   map[string]bool  `json:"extra"`
+	Skipped string           `json:"-"`
+	Next    *testRequest     `json:"next"`
+	Nested  struct{ A bool } `json:"nested"`
+}
+
+func noop(w http.ResponseWriter, r *http.Request) {}
+
+func TestDocument(t *testing.T) {
+	r := NewRouter(mux.NewRouter())
+	r.Handle(Route{
+		Method:   "POST",
+		Path:     "/things/{name}",
+		Summary:  "Create a thing",
+		Query:    map[string]string{"dry_run": "Don't persist"},
+		Request:  testRequest{},
+		Response: []testRequest{},
+		Handler:  noop,
+	})
+	doc := r.Document("test", "v0")
+
+	op, ok := doc.Paths["/things/{name}"]["post"]
+	require.True(t, ok)
+	assert.Equal(t, "Create a thing", op.Summary)
+	require.Len(t, op.Parameters, 2)
+	assert.Equal(t, Parameter{Name: "name", In: "path", Required: true, Schema: &Schema{Type: "string"}}, op.Parameters[0])
+	assert.Equal(t, "query", op.Parameters[1].In)
+
+	req := op.RequestBody.Content["application/json"].Schema
+	assert.Equal(t, "object", req.Type)
+	assert.Equal(t, &Schema{Type: "string"}, req.Properties["name"])
+	assert.Equal(t, &Schema{Type: "integer", Format: "int64"}, req.Properties["amount"])
+	assert.Equal(t, &Schema{Type: "array", Items: &Schema{Type: "string"}}, req.Properties["tags"])
+	assert.Equal(t, &Schema{Type: "string", Format: "byte"}, req.Properties["data"])
+	assert.Equal(t, &Schema{Type: "object", AdditionalProperties: &Schema{Type: "boolean"}}, req.Properties["extra"])
+	assert.Equal(t, &Schema{Type: "object"}, req.Properties["next"])
+	assert.Equal(t, "boolean", req.Properties["nested"].Properties["A"].Type)
+	_, ok = req.Properties["Skipped"]
+	assert.False(t, ok)
+
+	res := op.Responses["200"].Content["application/json"].Schema
+	assert.Equal(t, "array", res.Type)
+
+	// mux patterns are not part of OpenAPI paths
+	r.Handle(Route{Method: "GET", Path: "/things/{height:[0-9]+}/parts", Summary: "Get the parts", Handler: noop})
+	doc = r.Document("test", "v0")
+	op, ok = doc.Paths["/things/{height}/parts"]["get"]
+	require.True(t, ok)
+	assert.Equal(t, []Parameter{{Name: "height", In: "path", Required: true, Schema: &Schema{Type: "string"}}}, op.Parameters)
+}
+
+func TestUndescribed(t *testing.T) {
+	r := NewRouter(mux.NewRouter())
+	r.Handle(Route{Method: "GET", Path: "/a", Summary: "A", Handler: noop})
+	r.Handle(Route{Method: "GET", Path: "/b", Handler: noop})
+	r.HandleFunc("/c", noop).Methods("POST")
+
+	missing, err := r.Undescribed()
+	require.Nil(t, err)
+	assert.Equal(t, []string{"GET /b", "POST /c"}, missing)
+}
@@ -1,12 +1,15 @@
 package rpc
 
 import (
-	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
+	"github.com/tepleton/tepleton/p2p"
+	ctypes "github.com/tepleton/tepleton/rpc/core/types"
+
 	"github.com/tepleton/tepleton-sdk/client"
 	"github.com/tepleton/tepleton-sdk/client/context"
+	"github.com/tepleton/tepleton-sdk/client/openapi"
 )
 
 const (
@@ -44,11 +47,47 @@ func initClientCommand() *cobra.Command {
 }
 
 // Register REST endpoints
-func RegisterRoutes(ctx context.CoreContext, r *mux.Router) {
-	r.HandleFunc("/node_info", NodeInfoRequestHandlerFn(ctx)).Methods("GET")
-	r.HandleFunc("/syncing", NodeSyncingRequestHandlerFn(ctx)).Methods("GET")
-	r.HandleFunc("/blocks/latest", LatestBlockRequestHandlerFn(ctx)).Methods("GET")
-	r.HandleFunc("/blocks/{height}", BlockRequestHandlerFn(ctx)).Methods("GET")
-	r.HandleFunc("/validatorsets/latest", LatestValidatorSetRequestHandlerFn(ctx)).Methods("GET")
-	r.HandleFunc("/validatorsets/{height}", ValidatorSetRequestHandlerFn(ctx)).Methods("GET")
+func RegisterRoutes(ctx context.CoreContext, r *openapi.Router) {
+	r.Handle(openapi.Route{
+		Method:   "GET",
+		Path:     "/node_info",
+		Summary:  "Information about the connected node",
+		Response: p2p.NodeInfo{},
+		Handler:  NodeInfoRequestHandlerFn(ctx),
+	})
+	r.Handle(openapi.Route{
+		Method:   "GET",
+		Path:     "/syncing",
+		Summary:  "Whether the connected node is catching up",
+		Response: false,
+		Handler:  NodeSyncingRequestHandlerFn(ctx),
+	})
+	r.Handle(openapi.Route{
+		Method:   "GET",
+		Path:     "/blocks/latest",
+		Summary:  "Get the latest block",
+		Response: ctypes.ResultBlock{},
+		Handler:  LatestBlockRequestHandlerFn(ctx),
+	})
+	r.Handle(openapi.Route{
+		Method:   "GET",
+		Path:     "/blocks/{height:[0-9]+}",
+		Summary:  "Get the block at a height",
+		Response: ctypes.ResultBlock{},
+		Handler:  BlockRequestHandlerFn(ctx),
+	})
+	r.Handle(openapi.Route{
+		Method:   "GET",
+		Path:     "/validatorsets/latest",
+		Summary:  "Get the latest validator set",
+		Response: ResultValidatorsOutput{},
+		Handler:  LatestValidatorSetRequestHandlerFn(ctx),
+	})
+	r.Handle(openapi.Route{
+		Method:   "GET",
+		Path:     "/validatorsets/{height:[0-9]+}",
+		Summary:  "Get the validator set at a height",
+		Response: ResultValidatorsOutput{},
+		Handler:  ValidatorSetRequestHandlerFn(ctx),
+	})
 }
@@ -1,10 +1,10 @@
 package tx
 
 import (
-	"github.com/gorilla/mux"
 	"github.com/spf13/cobra"
 
 	"github.com/tepleton/tepleton-sdk/client/context"
+	"github.com/tepleton/tepleton-sdk/client/openapi"
 	"github.com/tepleton/tepleton-sdk/wire"
 )
 
@@ -17,9 +17,37 @@ func AddCommands(cmd *cobra.Command, cdc *wire.Codec) {
 }
 
 // register REST routes
-func RegisterRoutes(ctx context.CoreContext, r *mux.Router, cdc *wire.Codec) {
-	r.HandleFunc("/txs/{hash}", QueryTxRequestHandlerFn(cdc, ctx)).Methods("GET")
-	r.HandleFunc("/txs", SearchTxRequestHandlerFn(ctx, cdc)).Methods("GET")
-	// r.HandleFunc("/txs/sign", SignTxRequstHandler).Methods("POST")
+func RegisterRoutes(ctx context.CoreContext, r *openapi.Router, cdc *wire.Codec) {
+	r.Handle(openapi.Route{
+		Method:   "GET",
+		Path:     "/txs/{hash}",
+		Summary:  "Get a committed transaction by its hash",
+		Query:    map[string]string{"trust_node": "Don't verify proofs for the response"},
+		Response: txInfo{},
+		Handler:  QueryTxRequestHandlerFn(cdc, ctx),
+	})
+	r.Handle(openapi.Route{
+		Method:   "GET",
+		Path:     "/txs",
+		Summary:  "Search committed transactions by tag",
+		Query:    map[string]string{"tag": "key=value pair, postfix the key with _bech32 for bech32 addresses"},
+		Response: []txInfo{},
+		Handler:  SearchTxRequestHandlerFn(ctx, cdc),
+	})
+	r.Handle(openapi.Route{
+		Method:   "POST",
+		Path:     "/txs/sign",
+		Summary:  "Sign a sign document with a key unlocked through /keys/{name}/unlock",
+		Request:  SignTxBody{},
+		Response: SignTxOutput{},
+		Handler:  SignTxRequestHandlerFn(ctx),
+	})
+	r.Handle(openapi.Route{
+		Method:   "GET",
+		Path:     "/subscribe",
+		Summary:  "Websocket streaming tx and block events, driven by SubscribeRequest messages",
+		Response: EventOutput{},
+		Handler:  SubscribeRequestHandlerFn(cdc, ctx),
+	})
 	// r.HandleFunc("/txs/broadcast", BroadcastTxRequestHandler).Methods("POST")
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -93,6 +94,47 @@ func formatTxResults(cdc *wire.Codec, res []*ctypes.ResultTx) ([]txInfo, error)
 	return out, nil
 }
 
+// tagKey matches the tag keys of the node's query syntax
+var tagKey = regexp.MustCompile(`^[a-zA-Z0-9_.\-]+$`)
+
+// numeric matches the values compared as numbers by the node
+var numeric = regexp.MustCompile(`^[0-9]+$`)
+
+// parseTag converts a key=value tag to the query syntax of the node.
+// Keys postfixed with _bech32 take a bech32-encoded address or public key.
+// Values may be single-quoted, unquoted values are quoted unless numeric,
+// so that a tag always makes up exactly one condition of the query.
+func parseTag(tag string) (string, error) {
+	keyValue := strings.SplitN(tag, "=", 2)
+	if len(keyValue) != 2 {
+		return "", fmt.Errorf("Tag %q must be a key=value pair", tag)
+	}
+	key := keyValue[0]
+	value := keyValue[1]
+	if !tagKey.MatchString(key) {
+		return "", fmt.Errorf("Invalid tag key %q", key)
+	}
+	quoted := len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'")
+	if quoted {
+		value = value[1 : len(value)-1]
+	}
+	if strings.Contains(value, "'") {
+		return "", fmt.Errorf("Invalid tag value %q", keyValue[1])
+	}
+	if strings.HasSuffix(key, "_bech32") {
+		prefix := strings.Split(value, "1")[0]
+		bz, err := sdk.GetFromBech32(value, prefix)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSuffix(key, "_bech32") + "='" + sdk.Address(bz).String() + "'", nil
+	}
+	if !quoted && numeric.MatchString(value) {
+		return key + "=" + value, nil
+	}
+	return key + "='" + value + "'", nil
+}
+
 /////////////////////////////////////////
 // REST
 
@@ -105,20 +147,11 @@ func SearchTxRequestHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.Han
 			w.Write([]byte("You need to provide at least a tag as a key=value pair to search for. Postfix the key with _bech32 to search bech32-encoded addresses or public keys"))
 			return
 		}
-		keyValue := strings.Split(tag, "=")
-		key := keyValue[0]
-		value := keyValue[1]
-		if strings.HasSuffix(key, "_bech32") {
-			bech32address := strings.Trim(value, "'")
-			prefix := strings.Split(bech32address, "1")[0]
-			bz, err := sdk.GetFromBech32(bech32address, prefix)
-			if err != nil {
-				w.WriteHeader(400)
-				w.Write([]byte(err.Error()))
-				return
-			}
-
-			tag = strings.TrimRight(key, "_bech32") + "='" + sdk.Address(bz).String() + "'"
+		tag, err := parseTag(tag)
+		if err != nil {
+			w.WriteHeader(400)
+			w.Write([]byte(err.Error()))
+			return
 		}
 
 		txs, err := searchTxs(ctx, cdc, []string{tag})
@@ -1,48 +1,100 @@
 package tx
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"net/http"
 
+	"github.com/tepleton/tepleton-sdk/client/context"
 	keybase "github.com/tepleton/tepleton-sdk/client/keys"
-	keys "github.com/tepleton/go-crypto/keys"
+	sdk "github.com/tepleton/tepleton-sdk/types"
+	"github.com/tepleton/tepleton-sdk/x/auth"
 )
 
 // REST request body
-// TODO does this need to be exposed?
+// The key must have been unlocked through /keys/{name}/unlock, the
+// resulting session token is used instead of the password.
 type SignTxBody struct {
-	Name     string `json="name"`
-	Password string `json="password"`
-	TxBytes  string `json="tx"`
+	Name    string `json:"name"`
+	Token   string `json:"token"`
+	TxBytes []byte `json:"tx"`
+}
+
+// decoded sign document, shown to the client along with the signature
+type SignTxDoc struct {
+	ChainID        string          `json:"chain_id"`
+	AccountNumbers []int64         `json:"account_numbers"`
+	Sequences      []int64         `json:"sequences"`
+	Fee            auth.StdFee     `json:"fee"`
+	Msg            json.RawMessage `json:"msg"`
+}
+
+// REST response body
+type SignTxOutput struct {
+	Doc       SignTxDoc `json:"doc"`
+	PubKey    string    `json:"pub_key"`
+	Signature string    `json:"signature"`
 }
 
 // sign transaction REST Handler
-func SignTxRequstHandler(w http.ResponseWriter, r *http.Request) {
-	var kb keys.Keybase
-	var m SignTxBody
-
-	decoder := json.NewDecoder(r.Body)
-	err := decoder.Decode(&m)
-	if err != nil {
-		w.WriteHeader(400)
-		w.Write([]byte(err.Error()))
-		return
-	}
+// Only canonical StdSignBytes for the chain the LCD is configured for
+// are signed, arbitrary bytes are refused.
+func SignTxRequestHandlerFn(ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var m SignTxBody
 
-	kb, err = keybase.GetKeyBase()
-	if err != nil {
-		w.WriteHeader(500)
-		w.Write([]byte(err.Error()))
-		return
-	}
+		decoder := json.NewDecoder(r.Body)
+		err := decoder.Decode(&m)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
 
-	//TODO check if account exists
-	sig, _, err := kb.Sign(m.Name, m.Password, []byte(m.TxBytes))
-	if err != nil {
-		w.WriteHeader(403)
-		w.Write([]byte(err.Error()))
-		return
-	}
+		if ctx.ChainID == "" {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("Chain ID required but not configured"))
+			return
+		}
+
+		doc, fee, err := auth.ParseStdSignBytes(ctx.ChainID, m.TxBytes)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		sig, pub, err := keybase.SignWithSession(m.Token, m.Name, m.TxBytes)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(err.Error()))
+			return
+		}
 
-	w.Write(sig.Bytes())
+		bechPub, err := sdk.Bech32ifyAccPub(pub)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		output, err := json.MarshalIndent(SignTxOutput{
+			Doc: SignTxDoc{
+				ChainID:        doc.ChainID,
+				AccountNumbers: doc.AccountNumbers,
+				Sequences:      doc.Sequences,
+				Fee:            fee,
+				Msg:            json.RawMessage(doc.MsgBytes),
+			},
+			PubKey:    bechPub,
+			Signature: base64.StdEncoding.EncodeToString(sig.Bytes()),
+		}, "", "  ")
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		w.Write(output)
+	}
 }
@@ -0,0 +1,520 @@
+package tx
+
+import (
+	gocontext "context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/spf13/viper"
+	wrsp "github.com/tepleton/wrsp/types"
+
+	tmquery "github.com/tepleton/tepleton/libs/pubsub/query"
+	rpcclient "github.com/tepleton/tepleton/rpc/client"
+	tmtypes "github.com/tepleton/tepleton/types"
+
+	"github.com/tepleton/tepleton-sdk/client"
+	"github.com/tepleton/tepleton-sdk/client/context"
+	"github.com/tepleton/tepleton-sdk/wire"
+)
+
+// event kinds a websocket client can subscribe to
+const (
+	EventTx       = "tx"
+	EventNewBlock = "new_block"
+)
+
+// capacity of the channel the node delivers events on, per subscription
+const subscriptionBuffer = 100
+
+// blocks are committed every few seconds, a node connection that delivered
+// none for nodeTimeout is taken for dropped and replaced
+const (
+	nodeTimeout         = time.Minute
+	maxReconnectBackoff = 30 * time.Second
+)
+
+// bounds on what a subscription may replay, live events queue up in the
+// subscription buffer meanwhile, so replays have to stay short
+const (
+	maxReplayBlocks = 100
+	maxReplayTxs    = 1000
+)
+
+// SubscribeRequest is sent by websocket clients to (un)subscribe.
+// Tags are key=value pairs as for /txs, all of which have to match.
+// FromHeight replays committed events starting at that height before
+// forwarding new ones, so that clients can resume after reconnecting.
+// Subscriptions resume on their own when the connection to the node drops.
+type SubscribeRequest struct {
+	Action     string   `json:"action"` // subscribe or unsubscribe
+	ID         string   `json:"id"`
+	Event      string   `json:"event"`
+	Tags       []string `json:"tags"`
+	FromHeight int64    `json:"from_height"`
+}
+
+// EventOutput is pushed to websocket clients for every matching event
+type EventOutput struct {
+	ID     string          `json:"id"`
+	Event  string          `json:"event"`
+	Height int64           `json:"height"`
+	Data   json.RawMessage `json:"data,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// eventConn forwards the events of one websocket connection
+type eventConn struct {
+	cdc     *wire.Codec
+	nodeURI string
+	name    string
+
+	wmtx sync.Mutex
+	ws   *websocket.Conn
+
+	mtx  sync.Mutex
+	subs map[string]gocontext.CancelFunc
+}
+
+// subscription forwards the events of one subscription. It has its own
+// connection to the node, which it replaces when it drops, resuming from
+// the events it delivered last.
+type subscription struct {
+	c        *eventConn
+	req      SubscribeRequest
+	query    tmquery.Query
+	tagConds []string
+
+	node *rpcclient.HTTP
+	// next is the height to resume from: the next block to deliver, or
+	// the height of the last tx delivered, whose txs are recorded in seen
+	next int64
+	seen map[string]bool
+}
+
+// websocket handshakes are not subject to CORS, so the upgrader checks
+// the origin against the origins allowed with --cors itself
+var upgrader = websocket.Upgrader{
+	CheckOrigin: checkOrigin,
+}
+
+// checkOrigin allows requests without an origin, as sent by non-browser
+// clients, same-origin requests and requests from the allowed origins
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	return client.ParseAllowedOrigins(viper.GetString(client.FlagCORS)).Allows(origin)
+}
+
+// websocket REST handler forwarding the node's event subscriptions
+func SubscribeRequestHandlerFn(cdc *wire.Codec, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if ctx.NodeURI == "" {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("Must define node URI"))
+			return
+		}
+
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// Upgrade already wrote the error response
+			return
+		}
+		defer ws.Close()
+
+		c := &eventConn{
+			cdc:     cdc,
+			nodeURI: ctx.NodeURI,
+			name:    fmt.Sprintf("rest-server-%s", r.RemoteAddr),
+			ws:      ws,
+			subs:    make(map[string]gocontext.CancelFunc),
+		}
+		defer c.unsubscribeAll()
+
+		for {
+			var req SubscribeRequest
+			if err := ws.ReadJSON(&req); err != nil {
+				// the client went away
+				return
+			}
+			switch req.Action {
+			case "subscribe":
+				err = c.subscribe(req)
+			case "unsubscribe":
+				err = c.unsubscribe(req.ID)
+			default:
+				err = fmt.Errorf("Unknown action %q", req.Action)
+			}
+			if err != nil {
+				c.writeOutput(EventOutput{ID: req.ID, Event: req.Event, Error: err.Error()})
+			}
+		}
+	}
+}
+
+// eventQuery builds the node subscription query for an event kind and
+// its tags, along with the tag conditions alone for searching past txs
+func eventQuery(event string, tags []string) (query string, tagConds []string, err error) {
+	var eventType string
+	switch event {
+	case EventTx:
+		eventType = tmtypes.EventTx
+	case EventNewBlock:
+		if len(tags) != 0 {
+			return "", nil, fmt.Errorf("Tags can only be used with %s events", EventTx)
+		}
+		eventType = tmtypes.EventNewBlock
+	default:
+		return "", nil, fmt.Errorf("Unknown event %q", event)
+	}
+	for _, tag := range tags {
+		cond, err := parseTag(tag)
+		if err != nil {
+			return "", nil, err
+		}
+		tagConds = append(tagConds, cond)
+	}
+	conds := append([]string{fmt.Sprintf("%s='%s'", tmtypes.EventTypeKey, eventType)}, tagConds...)
+	return strings.Join(conds, " AND "), tagConds, nil
+}
+
+func (c *eventConn) subscribe(req SubscribeRequest) error {
+	if req.ID == "" {
+		return fmt.Errorf("Subscriptions need an id")
+	}
+	queryStr, tagConds, err := eventQuery(req.Event, req.Tags)
+	if err != nil {
+		return err
+	}
+	query, err := tmquery.New(queryStr)
+	if err != nil {
+		return err
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if _, ok := c.subs[req.ID]; ok {
+		return fmt.Errorf("Subscription %s already exists", req.ID)
+	}
+
+	s := &subscription{
+		c:        c,
+		req:      req,
+		query:    query,
+		tagConds: tagConds,
+		seen:     make(map[string]bool),
+	}
+	// subscribe before looking up the latest height, so that no event
+	// falls in between. Resuming from the latest height may send events
+	// committed just before subscribing, but never misses any.
+	out, beats, err := s.connect()
+	if err != nil {
+		return err
+	}
+	latest, err := s.latestHeight()
+	if err != nil {
+		s.disconnect()
+		return err
+	}
+	s.next = latest
+	replay := req.FromHeight > 0
+	if replay {
+		if err := s.checkReplay(req.FromHeight, latest); err != nil {
+			s.disconnect()
+			return err
+		}
+		s.next = req.FromHeight
+	}
+
+	subCtx, cancel := gocontext.WithCancel(gocontext.Background())
+	c.subs[req.ID] = cancel
+	go s.forward(subCtx, out, beats, replay)
+	return nil
+}
+
+// connect subscribes on a new connection to the node. Subscriptions to txs
+// get the new blocks on beats too, to notice when the connection drops.
+func (s *subscription) connect() (out, beats chan interface{}, err error) {
+	node := rpcclient.NewHTTP(s.c.nodeURI, "/websocket")
+	if err := node.Start(); err != nil {
+		return nil, nil, err
+	}
+	subscriber := s.c.subscriber(s.req.ID)
+	out = make(chan interface{}, subscriptionBuffer)
+	err = node.Subscribe(gocontext.Background(), subscriber, s.query, out)
+	if err != nil {
+		node.Stop()
+		return nil, nil, err
+	}
+	if s.req.Event == EventTx {
+		beats = make(chan interface{}, subscriptionBuffer)
+		err = node.Subscribe(gocontext.Background(), subscriber+"-blocks", tmtypes.EventQueryNewBlock, beats)
+		if err != nil {
+			node.Stop()
+			return nil, nil, err
+		}
+	}
+	s.node = node
+	return out, beats, nil
+}
+
+// disconnect drops the subscription and the connection to the node
+func (s *subscription) disconnect() {
+	s.node.UnsubscribeAll(gocontext.Background(), s.c.subscriber(s.req.ID))
+	s.node.UnsubscribeAll(gocontext.Background(), s.c.subscriber(s.req.ID)+"-blocks")
+	s.node.Stop()
+}
+
+// reconnect replaces a dropped connection to the node, retrying until it
+// succeeds or the subscription is cancelled, and replays the events the
+// subscription missed meanwhile
+func (s *subscription) reconnect(subCtx gocontext.Context) (out, beats chan interface{}, err error) {
+	s.disconnect()
+	backoff := time.Second
+	for {
+		out, beats, err = s.connect()
+		if err == nil {
+			break
+		}
+		select {
+		case <-subCtx.Done():
+			return nil, nil, subCtx.Err()
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxReconnectBackoff {
+			backoff = maxReconnectBackoff
+		}
+	}
+
+	latest, err := s.latestHeight()
+	if err == nil {
+		err = s.checkReplay(s.next, latest)
+	}
+	if err != nil {
+		s.c.writeOutput(EventOutput{ID: s.req.ID, Event: s.req.Event,
+			Error: fmt.Sprintf("Missed the events from height %d on, resubscribe with from_height: %v", s.next, err)})
+		if latest > 0 {
+			s.skipTo(latest + 1)
+		}
+		return out, beats, nil
+	}
+	s.replay()
+	return out, beats, nil
+}
+
+// forward replays past events if requested, then sends live events
+// until the subscription is cancelled
+func (s *subscription) forward(subCtx gocontext.Context, out, beats chan interface{}, replay bool) {
+	defer s.disconnect()
+	if replay {
+		s.replay()
+	}
+
+	var err error
+	timeout := time.NewTimer(nodeTimeout)
+	defer timeout.Stop()
+	for {
+		select {
+		case <-subCtx.Done():
+			return
+		case data, ok := <-out:
+			if ok {
+				s.deliver(data)
+			} else if out, beats, err = s.reconnect(subCtx); err != nil {
+				// cancelled while reconnecting
+				return
+			}
+		case <-beats:
+		case <-timeout.C:
+			if out, beats, err = s.reconnect(subCtx); err != nil {
+				return
+			}
+		}
+		if !timeout.Stop() {
+			select {
+			case <-timeout.C:
+			default:
+			}
+		}
+		timeout.Reset(nodeTimeout)
+	}
+}
+
+// deliver sends a live event, unless it was delivered already
+func (s *subscription) deliver(data interface{}) {
+	switch ev := data.(type) {
+	case tmtypes.EventDataTx:
+		s.writeTx(ev.Height, ev.Index, ev.Tx, ev.Result)
+	case tmtypes.EventDataNewBlock:
+		s.writeBlock(ev.Block.Height, ev.Block.Header)
+	}
+}
+
+func (s *subscription) latestHeight() (int64, error) {
+	status, err := s.node.Status()
+	if err != nil {
+		return 0, err
+	}
+	return status.SyncInfo.LatestBlockHeight, nil
+}
+
+// checkReplay rejects replays from height of more than maxReplayBlocks
+// blocks or maxReplayTxs txs
+func (s *subscription) checkReplay(height, latest int64) error {
+	if latest-height >= maxReplayBlocks {
+		return fmt.Errorf("Cannot replay more than %d blocks, latest height is %d", maxReplayBlocks, latest)
+	}
+	if s.req.Event != EventTx {
+		return nil
+	}
+	res, err := s.node.TxSearch(replayTxQuery(height, s.tagConds), false, 1, 1)
+	if err != nil {
+		return err
+	}
+	if res.TotalCount > maxReplayTxs {
+		return fmt.Errorf("Cannot replay more than %d txs, %d match", maxReplayTxs, res.TotalCount)
+	}
+	return nil
+}
+
+func replayTxQuery(height int64, tagConds []string) string {
+	conds := append(append([]string{}, tagConds...), fmt.Sprintf("tx.height>=%d", height))
+	return strings.Join(conds, " AND ")
+}
+
+// replay sends the committed events from s.next on
+func (s *subscription) replay() {
+	err := s.replayFrom(s.next)
+	if err != nil {
+		s.c.writeOutput(EventOutput{ID: s.req.ID, Event: s.req.Event, Error: err.Error()})
+	}
+}
+
+// replayFrom sends the committed events from height from on. The node
+// returns the txs ordered by height.
+func (s *subscription) replayFrom(from int64) error {
+	switch s.req.Event {
+	case EventTx:
+		query := replayTxQuery(from, s.tagConds)
+		for page := 1; ; page++ {
+			res, err := s.node.TxSearch(query, false, page, 100)
+			if err != nil {
+				return err
+			}
+			for _, tx := range res.Txs {
+				s.writeTx(tx.Height, tx.Index, tx.Tx, tx.TxResult)
+			}
+			if len(res.Txs) < 100 {
+				return nil
+			}
+		}
+	case EventNewBlock:
+		latest, err := s.latestHeight()
+		if err != nil {
+			return err
+		}
+		for height := from; height <= latest; height++ {
+			h := height
+			res, err := s.node.Block(&h)
+			if err != nil {
+				return err
+			}
+			s.writeBlock(h, res.Block.Header)
+		}
+	}
+	return nil
+}
+
+// skipTo gives up on the events below height
+func (s *subscription) skipTo(height int64) {
+	if height > s.next {
+		s.next = height
+		s.seen = make(map[string]bool)
+	}
+}
+
+// writeTx sends a tx, unless it was sent already
+func (s *subscription) writeTx(height int64, index uint32, txBytes []byte, result wrsp.ResponseDeliverTx) {
+	key := txKey(height, index)
+	if height < s.next || s.seen[key] {
+		return
+	}
+	s.skipTo(height)
+	s.seen[key] = true
+
+	c := s.c
+	tx, err := parseTx(c.cdc, txBytes)
+	if err != nil {
+		c.writeOutput(EventOutput{ID: s.req.ID, Event: EventTx, Height: height, Error: err.Error()})
+		return
+	}
+	c.write(s.req.ID, EventTx, height, txInfo{Height: height, Tx: tx, Result: result})
+}
+
+// writeBlock sends a block header, unless it was sent already
+func (s *subscription) writeBlock(height int64, header tmtypes.Header) {
+	if height < s.next {
+		return
+	}
+	s.next = height + 1
+	s.c.write(s.req.ID, EventNewBlock, height, header)
+}
+
+// write sends data over the websocket, decoded with the app codec
+func (c *eventConn) write(id, event string, height int64, data interface{}) {
+	out := EventOutput{ID: id, Event: event, Height: height}
+	bz, err := c.cdc.MarshalJSON(data)
+	if err != nil {
+		out.Error = err.Error()
+	} else {
+		out.Data = bz
+	}
+	c.writeOutput(out)
+}
+
+func (c *eventConn) writeOutput(out EventOutput) {
+	c.wmtx.Lock()
+	defer c.wmtx.Unlock()
+	c.ws.WriteJSON(out)
+}
+
+func (c *eventConn) unsubscribe(id string) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	cancel, ok := c.subs[id]
+	if !ok {
+		return fmt.Errorf("Subscription %s not found", id)
+	}
+	cancel()
+	delete(c.subs, id)
+	return nil
+}
+
+func (c *eventConn) unsubscribeAll() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for id, cancel := range c.subs {
+		cancel()
+		delete(c.subs, id)
+	}
+}
+
+// subscriber is the name subscription id is known by to the node
+func (c *eventConn) subscriber(id string) string {
+	return c.name + "-" + id
+}
+
+func txKey(height int64, index uint32) string {
+	return fmt.Sprintf("%d/%d", height, index)
+}
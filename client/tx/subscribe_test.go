@@ -0,0 +1,79 @@
+package tx
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tepleton/tepleton-sdk/client"
+)
+
+func TestEventQuery(t *testing.T) {
+	query, tagConds, err := eventQuery(EventTx, []string{"sender='abc'", "action='send'"})
+	require.Nil(t, err)
+	assert.Equal(t, "tm.event='Tx' AND sender='abc' AND action='send'", query)
+	assert.Equal(t, []string{"sender='abc'", "action='send'"}, tagConds)
+
+	query, tagConds, err = eventQuery(EventNewBlock, nil)
+	require.Nil(t, err)
+	assert.Equal(t, "tm.event='NewBlock'", query)
+	assert.Empty(t, tagConds)
+
+	// blocks are not tagged
+	_, _, err = eventQuery(EventNewBlock, []string{"sender='abc'"})
+	assert.NotNil(t, err)
+
+	_, _, err = eventQuery("vote", nil)
+	assert.NotNil(t, err)
+
+	_, _, err = eventQuery(EventTx, []string{"sender"})
+	assert.NotNil(t, err)
+}
+
+func TestParseTag(t *testing.T) {
+	cases := []struct {
+		tag, cond string
+	}{
+		{"sender='abc'", "sender='abc'"},
+		{"action=send", "action='send'"},
+		{"tx.height=5", "tx.height=5"},
+		{"tx.hash='1234'", "tx.hash='1234'"},
+		{"key.with-dash_and.dots='a b'", "key.with-dash_and.dots='a b'"},
+	}
+	for _, tc := range cases {
+		cond, err := parseTag(tc.tag)
+		require.Nil(t, err, tc.tag)
+		assert.Equal(t, tc.cond, cond)
+	}
+
+	// every tag is exactly one condition
+	for _, tag := range []string{
+		"sender='abc' OR tm.event='NewBlock'",
+		"sender=abc' OR tm.event='NewBlock",
+		"sender='abc' AND action=send",
+		"tm.event='Tx' OR sender=abc",
+		"sender",
+		"=abc",
+	} {
+		_, err := parseTag(tag)
+		assert.NotNil(t, err, tag)
+	}
+}
+
+func TestCheckOrigin(t *testing.T) {
+	viper.Set(client.FlagCORS, "http://a.com")
+	defer viper.Set(client.FlagCORS, "")
+
+	r := httptest.NewRequest("GET", "http://localhost:1317/subscribe", nil)
+	// non-browser clients send no origin
+	assert.True(t, checkOrigin(r))
+	r.Header.Set("Origin", "http://localhost:1317")
+	assert.True(t, checkOrigin(r))
+	r.Header.Set("Origin", "http://a.com")
+	assert.True(t, checkOrigin(r))
+	r.Header.Set("Origin", "http://b.com")
+	assert.False(t, checkOrigin(r))
+}
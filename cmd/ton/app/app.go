@@ -14,6 +14,7 @@ import (
 	sdk "github.com/tepleton/tepleton-sdk/types"
 	"github.com/tepleton/tepleton-sdk/wire"
 	"github.com/tepleton/tepleton-sdk/x/auth"
+	"github.com/tepleton/tepleton-sdk/x/authz"
 	"github.com/tepleton/tepleton-sdk/x/bank"
 	"github.com/tepleton/tepleton-sdk/x/ibc"
 	"github.com/tepleton/tepleton-sdk/x/slashing"
@@ -36,19 +37,25 @@ type GaiaApp struct {
 	cdc *wire.Codec
 
 	// keys to access the substores
-	keyMain     *sdk.KVStoreKey
-	keyAccount  *sdk.KVStoreKey
-	keyIBC      *sdk.KVStoreKey
-	keyStake    *sdk.KVStoreKey
-	keySlashing *sdk.KVStoreKey
+	keyMain          *sdk.KVStoreKey
+	keyAccount       *sdk.KVStoreKey
+	keyFeeCollection *sdk.KVStoreKey
+	keyIBC           *sdk.KVStoreKey
+	keyStake         *sdk.KVStoreKey
+	keySlashing      *sdk.KVStoreKey
+	keyBank          *sdk.KVStoreKey
+	keyFeeGrant      *sdk.KVStoreKey
+	keyAuthz         *sdk.KVStoreKey
 
 	// Manage getting and setting accounts
 	accountMapper       auth.AccountMapper
 	feeCollectionKeeper auth.FeeCollectionKeeper
+	feeGrantKeeper      auth.FeeGrantKeeper
 	coinKeeper          bank.Keeper
 	ibcMapper           ibc.Mapper
 	stakeKeeper         stake.Keeper
 	slashingKeeper      slashing.Keeper
+	authzKeeper         authz.Keeper
 }
 
 func NewGaiaApp(logger log.Logger, db dbm.DB) *GaiaApp {
@@ -56,13 +63,17 @@ func NewGaiaApp(logger log.Logger, db dbm.DB) *GaiaApp {
 
 	// create your application object
 	var app = &GaiaApp{
-		BaseApp:     bam.NewBaseApp(appName, cdc, logger, db),
-		cdc:         cdc,
-		keyMain:     sdk.NewKVStoreKey("main"),
-		keyAccount:  sdk.NewKVStoreKey("acc"),
-		keyIBC:      sdk.NewKVStoreKey("ibc"),
-		keyStake:    sdk.NewKVStoreKey("stake"),
-		keySlashing: sdk.NewKVStoreKey("slashing"),
+		BaseApp:          bam.NewBaseApp(appName, cdc, logger, db),
+		cdc:              cdc,
+		keyMain:          sdk.NewKVStoreKey("main"),
+		keyAccount:       sdk.NewKVStoreKey("acc"),
+		keyFeeCollection: sdk.NewKVStoreKey("fee"),
+		keyIBC:           sdk.NewKVStoreKey("ibc"),
+		keyStake:         sdk.NewKVStoreKey("stake"),
+		keySlashing:      sdk.NewKVStoreKey("slashing"),
+		keyBank:          sdk.NewKVStoreKey("bank"),
+		keyFeeGrant:      sdk.NewKVStoreKey("feegrant"),
+		keyAuthz:         sdk.NewKVStoreKey("authz"),
 	}
 
 	// define the accountMapper
@@ -73,24 +84,38 @@ func NewGaiaApp(logger log.Logger, db dbm.DB) *GaiaApp {
 	)
 
 	// add handlers
-	app.coinKeeper = bank.NewKeeper(app.accountMapper)
+	app.feeCollectionKeeper = auth.NewFeeCollectionKeeper(app.cdc, app.keyFeeCollection)
+	app.feeGrantKeeper = auth.NewFeeGrantKeeper(app.cdc, app.keyFeeGrant)
+	app.coinKeeper = bank.NewKeeperWithSupply(app.cdc, app.keyBank, app.accountMapper)
 	app.ibcMapper = ibc.NewMapper(app.cdc, app.keyIBC, app.RegisterCodespace(ibc.DefaultCodespace))
 	app.stakeKeeper = stake.NewKeeper(app.cdc, app.keyStake, app.coinKeeper, app.RegisterCodespace(stake.DefaultCodespace))
 	app.slashingKeeper = slashing.NewKeeper(app.cdc, app.keySlashing, app.stakeKeeper, app.RegisterCodespace(slashing.DefaultCodespace))
+	app.authzKeeper = authz.NewKeeper(app.cdc, app.keyAuthz, app.Router(), app.RegisterCodespace(authz.DefaultCodespace))
+
+	// stake tracks the bond denomination in its pool, the other modules
+	// may not mint or burn it
+	app.coinKeeper = app.coinKeeper.WithReservedDenoms(app.bondDenoms)
 
 	// register message routes
 	app.Router().
 		AddRoute("bank", bank.NewHandler(app.coinKeeper)).
+		AddRoute("feegrant", auth.NewFeeGrantHandler(app.feeGrantKeeper, app.accountMapper)).
 		AddRoute("ibc", ibc.NewHandler(app.ibcMapper, app.coinKeeper)).
 		AddRoute("stake", stake.NewHandler(app.stakeKeeper)).
-		AddRoute("slashing", slashing.NewHandler(app.slashingKeeper))
+		AddRoute("slashing", slashing.NewHandler(app.slashingKeeper)).
+		AddRoute("authz", authz.NewHandler(app.authzKeeper))
+
+	// register the invariants checked at the end of blocks
+	bank.RegisterInvariants(app, app.accountMapper)
+	app.RegisterInvariant("bank/total-supply", bank.TotalCoinsInvariant(app.accountMapper, app.coinKeeper, app.heldCoins))
+	stake.RegisterInvariants(app, app.stakeKeeper)
 
 	// initialize BaseApp
 	app.SetInitChainer(app.initChainer)
 	app.SetBeginBlocker(app.BeginBlocker)
 	app.SetEndBlocker(app.EndBlocker)
-	app.SetAnteHandler(auth.NewAnteHandler(app.accountMapper, app.feeCollectionKeeper))
-	app.MountStoresIAVL(app.keyMain, app.keyAccount, app.keyIBC, app.keyStake, app.keySlashing)
+	app.SetAnteHandler(auth.NewAnteHandlerWithFeeGrants(app.accountMapper, app.feeCollectionKeeper, app.feeGrantKeeper))
+	app.MountStoresIAVL(app.keyMain, app.keyAccount, app.keyFeeCollection, app.keyIBC, app.keyStake, app.keySlashing, app.keyBank, app.keyFeeGrant, app.keyAuthz)
 	err := app.LoadLatestVersion(app.keyMain)
 	if err != nil {
 		cmn.Exit(err.Error())
@@ -106,6 +131,7 @@ func MakeCodec() *wire.Codec {
 	bank.RegisterWire(cdc)
 	stake.RegisterWire(cdc)
 	slashing.RegisterWire(cdc)
+	authz.RegisterWire(cdc)
 	auth.RegisterWire(cdc)
 	sdk.RegisterWire(cdc)
 	wire.RegisterCrypto(cdc)
@@ -130,6 +156,25 @@ func (app *GaiaApp) EndBlocker(ctx sdk.Context, req wrsp.RequestEndBlock) wrsp.R
 	}
 }
 
+// the bond denomination, whose supply stake manages
+func (app *GaiaApp) bondDenoms(ctx sdk.Context) []string {
+	return []string{app.stakeKeeper.GetParams(ctx).BondDenom}
+}
+
+// coins held outside of the accounts: the collected fees, and the tokens
+// of the bond denomination held by the validators or provisioned without
+// being distributed to an account yet
+func (app *GaiaApp) heldCoins(ctx sdk.Context) sdk.Coins {
+	pool := app.stakeKeeper.GetPool(ctx)
+	denom := app.stakeKeeper.GetParams(ctx).BondDenom
+	held := app.feeCollectionKeeper.GetCollectedFees(ctx)
+	staked := pool.BondedTokens + pool.UnbondingTokens + pool.UnbondedTokens + pool.Provisions
+	if staked != 0 {
+		held = held.Plus(sdk.Coins{{denom, staked}})
+	}
+	return held
+}
+
 // custom logic for ton initialization
 func (app *GaiaApp) initChainer(ctx sdk.Context, req wrsp.RequestInitChain) wrsp.ResponseInitChain {
 	stateJSON := req.AppStateBytes
@@ -149,15 +194,63 @@ func (app *GaiaApp) initChainer(ctx sdk.Context, req wrsp.RequestInitChain) wrsp
 		app.accountMapper.SetAccount(ctx, acc)
 	}
 
-	// load the initial stake information
-	stake.InitGenesis(ctx, app.stakeKeeper, genesisState.StakeData)
+	// load the supply and the issuers of the coins
+	bankData := genesisState.BankData
+	bankData.Supply, err = genesisSupply(genesisState)
+	if err != nil {
+		panic(err) // TODO https://github.com/tepleton/tepleton-sdk/issues/468
+	}
+	err = bank.InitGenesis(ctx, app.coinKeeper, bankData)
+	if err != nil {
+		panic(err) // TODO https://github.com/tepleton/tepleton-sdk/issues/468
+		// return sdk.ErrGenesisParse("").TraceCause(err, "")
+	}
+
+	// load the collected fees and the fee grants
+	err = auth.InitCollectedFeesGenesis(ctx, app.feeCollectionKeeper, genesisState.CollectedFees)
+	if err != nil {
+		panic(err) // TODO https://github.com/tepleton/tepleton-sdk/issues/468
+	}
+	err = auth.InitFeeGrantGenesis(ctx, app.feeGrantKeeper, genesisState.FeeGrants)
+	if err != nil {
+		panic(err) // TODO https://github.com/tepleton/tepleton-sdk/issues/468
+	}
+
+	// load the initial stake information, with the provisions no account holds
+	genesisState.StakeData.Pool.Provisions, err = genesisProvisions(genesisState)
+	if err != nil {
+		panic(err) // TODO https://github.com/tepleton/tepleton-sdk/issues/468
+	}
+	err = stake.InitGenesis(ctx, app.stakeKeeper, genesisState.StakeData)
+	if err != nil {
+		panic(err) // TODO https://github.com/tepleton/tepleton-sdk/issues/468
+		// return sdk.ErrGenesisParse("").TraceCause(err, "")
+	}
+
+	// load the signing infos of the validators and the authorization grants
+	err = slashing.InitGenesis(ctx, app.slashingKeeper, genesisState.SlashingData)
+	if err != nil {
+		panic(err) // TODO https://github.com/tepleton/tepleton-sdk/issues/468
+	}
+	err = authz.InitGenesis(ctx, app.authzKeeper, genesisState.AuthzData)
+	if err != nil {
+		panic(err) // TODO https://github.com/tepleton/tepleton-sdk/issues/468
+	}
 
 	return wrsp.ResponseInitChain{}
 }
 
+// load a particular height, to export the state at it
+func (app *GaiaApp) LoadHeight(height int64) error {
+	return app.LoadVersion(height, app.keyMain)
+}
+
 // export the state of ton for a genesis file
-func (app *GaiaApp) ExportAppStateAndValidators() (appState json.RawMessage, validators []tmtypes.GenesisValidator, err error) {
-	ctx := app.NewContext(true, wrsp.Header{})
+func (app *GaiaApp) ExportAppStateAndValidators(forZeroHeight bool) (appState json.RawMessage, validators []tmtypes.GenesisValidator, err error) {
+	ctx := app.NewContext(true, wrsp.Header{Height: app.LastBlockHeight()})
+	if forZeroHeight {
+		app.prepForZeroHeightGenesis(ctx)
+	}
 
 	// iterate to get the accounts
 	accounts := []GenesisAccount{}
@@ -169,8 +262,13 @@ func (app *GaiaApp) ExportAppStateAndValidators() (appState json.RawMessage, val
 	app.accountMapper.IterateAccounts(ctx, appendAccount)
 
 	genState := GenesisState{
-		Accounts:  accounts,
-		StakeData: stake.WriteGenesis(ctx, app.stakeKeeper),
+		Accounts:      accounts,
+		StakeData:     stake.WriteGenesis(ctx, app.stakeKeeper),
+		BankData:      bank.WriteGenesis(ctx, app.coinKeeper),
+		FeeGrants:     auth.WriteFeeGrantGenesis(ctx, app.feeGrantKeeper),
+		CollectedFees: auth.WriteCollectedFeesGenesis(ctx, app.feeCollectionKeeper),
+		SlashingData:  slashing.WriteGenesis(ctx, app.slashingKeeper),
+		AuthzData:     authz.WriteGenesis(ctx, app.authzKeeper),
 	}
 	appState, err = wire.MarshalJSONIndent(app.cdc, genState)
 	if err != nil {
@@ -179,3 +277,9 @@ func (app *GaiaApp) ExportAppStateAndValidators() (appState json.RawMessage, val
 	validators = stake.WriteValidators(ctx, app.stakeKeeper)
 	return appState, validators, nil
 }
+
+// prepare the state to start a new chain from it at height zero
+func (app *GaiaApp) prepForZeroHeightGenesis(ctx sdk.Context) {
+	stake.PrepForZeroHeightGenesis(ctx, app.stakeKeeper)
+	slashing.PrepForZeroHeightGenesis(ctx, app.slashingKeeper)
+}
@@ -1,11 +1,25 @@
 package app
 
 import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	crypto "github.com/tepleton/go-crypto"
+	tmtypes "github.com/tepleton/tepleton/types"
+	dbm "github.com/tepleton/tmlibs/db"
+	"github.com/tepleton/tmlibs/log"
+	wrsp "github.com/tepleton/wrsp/types"
+
+	"github.com/tepleton/tepleton-sdk/crypto/keys"
+	sdk "github.com/tepleton/tepleton-sdk/types"
 	"github.com/tepleton/tepleton-sdk/wire"
 	"github.com/tepleton/tepleton-sdk/x/auth"
+	"github.com/tepleton/tepleton-sdk/x/authz"
+	"github.com/tepleton/tepleton-sdk/x/bank"
+	"github.com/tepleton/tepleton-sdk/x/ibc"
 	"github.com/tepleton/tepleton-sdk/x/stake"
-
-	wrsp "github.com/tepleton/wrsp/types"
 )
 
 func setGenesis(gapp *GaiaApp, accs ...*auth.BaseAccount) error {
@@ -31,3 +45,226 @@ func setGenesis(gapp *GaiaApp, accs ...*auth.BaseAccount) error {
 
 	return nil
 }
+
+func TestGaiadExport(t *testing.T) {
+	db := dbm.NewMemDB()
+	gapp := NewGaiaApp(log.NewNopLogger(), db)
+
+	keybase := keys.NewInMemory()
+	var genTxs []json.RawMessage
+	var addrs []sdk.Address
+	var pks []crypto.PubKey
+	for _, name := range []string{"validator1", "validator2"} {
+		info, _, err := keybase.CreateMnemonic(name, keys.English, "1234567890", "", keys.Ed25519)
+		require.Nil(t, err)
+		pk := crypto.GenPrivKeyEd25519().PubKey()
+		genTx, _, _, err := GaiaAppGenTxNF(gapp.cdc, pk, keybase, name, "1234567890", "test-chain", sdk.Coin{"steak", 100})
+		require.Nil(t, err)
+		genTxs = append(genTxs, genTx)
+		addrs = append(addrs, sdk.Address(info.GetPubKey().Address()))
+		pks = append(pks, pk)
+	}
+	genState, err := GaiaAppGenState(gapp.cdc, "test-chain", genTxs)
+	require.Nil(t, err)
+	stateBytes, err := wire.MarshalJSONIndent(gapp.cdc, genState)
+	require.Nil(t, err)
+	gapp.InitChain(wrsp.RequestInitChain{AppStateBytes: stateBytes})
+	gapp.Commit()
+
+	// leave an unbonding and a redelegation pending, and the signing infos
+	// of the validators which signed the last block
+	header := wrsp.Header{Height: 2}
+	var signing []wrsp.SigningValidator
+	for _, pk := range pks {
+		val := wrsp.Validator{PubKey: tmtypes.TM2PB.PubKey(pk), Power: 100}
+		signing = append(signing, wrsp.SigningValidator{Validator: val, SignedLastBlock: true})
+	}
+	gapp.BeginBlock(wrsp.RequestBeginBlock{Header: header, Validators: signing})
+	ctx := gapp.NewContext(false, header)
+	handler := stake.NewHandler(gapp.stakeKeeper)
+	for _, msg := range []sdk.Msg{
+		stake.NewMsgDelegate(addrs[0], addrs[0], sdk.Coin{"steak", 20}),
+		stake.NewMsgBeginUnbonding(addrs[0], addrs[0], sdk.NewRat(5)),
+		stake.NewMsgBeginRedelegate(addrs[0], addrs[0], addrs[1], sdk.NewRat(5)),
+	} {
+		res := handler(ctx, msg)
+		require.True(t, res.IsOK(), res.Log)
+	}
+
+	// the fee grants and the authorization grants are exported
+	gapp.feeGrantKeeper.SetFeeGrant(ctx, auth.NewFeeGrant(addrs[0], addrs[1], sdk.Coins{{"steak", 10}}, 0))
+	gapp.authzKeeper.SetGrant(ctx, addrs[0], addrs[1], authz.NewGrant(authz.NewGenericAuthorization("bank/MsgSend"), 0))
+
+	// slashing burns tokens, decreasing the supply
+	supply := gapp.coinKeeper.GetSupply(ctx, "steak")
+	gapp.stakeKeeper.Slash(ctx, pks[1], 2, 100, sdk.NewRat(1, 10))
+	require.True(t, gapp.coinKeeper.GetSupply(ctx, "steak") < supply)
+	gapp.EndBlock(wrsp.RequestEndBlock{})
+	gapp.Commit()
+	require.Nil(t, gapp.CheckInvariants())
+
+	for _, forZeroHeight := range []bool{false, true} {
+		exported, _, err := gapp.ExportAppStateAndValidators(forZeroHeight)
+		require.Nil(t, err)
+
+		// pending unbondings and redelegations are kept, unless the new
+		// chain starts at height zero
+		var exportedState GenesisState
+		require.Nil(t, gapp.cdc.UnmarshalJSON(exported, &exportedState))
+		pending := 1
+		if forZeroHeight {
+			pending = 0
+		}
+		require.Len(t, exportedState.StakeData.UnbondingDelegations, pending)
+		require.Len(t, exportedState.StakeData.Redelegations, pending)
+		require.Len(t, exportedState.FeeGrants, 1)
+		require.Len(t, exportedState.AuthzData.Grants, 1)
+
+		// the signing infos are kept, with their signed blocks unless the
+		// new chain starts at height zero
+		require.Len(t, exportedState.SlashingData.SigningInfos, len(pks))
+		for _, signingInfo := range exportedState.SlashingData.SigningInfos {
+			if forZeroHeight {
+				require.Equal(t, int64(0), signingInfo.Info.StartHeight)
+				require.Empty(t, signingInfo.SignedBlocks)
+			} else {
+				require.Equal(t, int64(2), signingInfo.Info.StartHeight)
+				require.Equal(t, []int64{0}, signingInfo.SignedBlocks)
+			}
+		}
+
+		// starting a new chain from the export must export the same state
+		newGapp := NewGaiaApp(log.NewNopLogger(), dbm.NewMemDB())
+		newGapp.InitChain(wrsp.RequestInitChain{AppStateBytes: exported})
+		newGapp.Commit()
+		reexported, _, err := newGapp.ExportAppStateAndValidators(forZeroHeight)
+		require.Nil(t, err)
+		require.Equal(t, string(exported), string(reexported))
+	}
+
+	// export at a past height
+	gapp.BeginBlock(wrsp.RequestBeginBlock{Header: wrsp.Header{Height: 3}})
+	gapp.EndBlock(wrsp.RequestEndBlock{})
+	gapp.Commit()
+	latest, _, err := gapp.ExportAppStateAndValidators(false)
+	require.Nil(t, err)
+
+	gapp = NewGaiaApp(log.NewNopLogger(), db)
+	err = gapp.LoadHeight(2)
+	require.Nil(t, err)
+	require.Equal(t, int64(2), gapp.LastBlockHeight())
+	past, _, err := gapp.ExportAppStateAndValidators(false)
+	require.Nil(t, err)
+	require.Equal(t, string(latest), string(past))
+}
+
+func TestBlockedAddrs(t *testing.T) {
+	gapp := NewGaiaApp(log.NewNopLogger(), dbm.NewMemDB())
+	addr := sdk.Address(crypto.GenPrivKeyEd25519().PubKey().Address())
+	blocked := sdk.Address(crypto.GenPrivKeyEd25519().PubKey().Address())
+	acc := auth.NewBaseAccountWithAddress(addr)
+	acc.Coins = sdk.Coins{{"steak", 10}}
+
+	// the genesis blocks the recipient
+	genesisState := GenesisState{
+		Accounts:  []GenesisAccount{NewGenesisAccount(&acc)},
+		StakeData: stake.DefaultGenesisState(),
+		BankData:  bank.GenesisState{BlockedAddrs: []sdk.Address{blocked}},
+	}
+	genesisState.StakeData.Pool.LooseTokens = 10
+	stateBytes, err := wire.MarshalJSONIndent(gapp.cdc, genesisState)
+	require.Nil(t, err)
+	gapp.InitChain(wrsp.RequestInitChain{AppStateBytes: stateBytes})
+	gapp.Commit()
+
+	header := wrsp.Header{Height: 2}
+	gapp.BeginBlock(wrsp.RequestBeginBlock{Header: header})
+	ctx := gapp.NewContext(false, header)
+	_, sdkErr := gapp.coinKeeper.SendCoins(ctx, addr, blocked, sdk.Coins{{"steak", 5}})
+	require.Equal(t, bank.CodeBlockedRecipient, sdkErr.Code())
+}
+
+func TestCollectedFeesInvariant(t *testing.T) {
+	gapp := NewGaiaApp(log.NewNopLogger(), dbm.NewMemDB())
+	priv := crypto.GenPrivKeyEd25519()
+	addr := sdk.Address(priv.PubKey().Address())
+	to := sdk.Address(crypto.GenPrivKeyEd25519().PubKey().Address())
+	acc := auth.NewBaseAccountWithAddress(addr)
+	acc.Coins = sdk.Coins{{"steak", 100}}
+	genesisState := GenesisState{
+		Accounts:  []GenesisAccount{NewGenesisAccount(&acc)},
+		StakeData: stake.DefaultGenesisState(),
+	}
+	genesisState.StakeData.Pool.LooseTokens = 100
+	stateBytes, err := wire.MarshalJSONIndent(gapp.cdc, genesisState)
+	require.Nil(t, err)
+	gapp.InitChain(wrsp.RequestInitChain{AppStateBytes: stateBytes})
+	gapp.Commit()
+
+	// the fee moves from the account to the fee collector, out of the
+	// accounts but still in the supply
+	header := wrsp.Header{Height: 2, ChainID: "test-chain"}
+	gapp.BeginBlock(wrsp.RequestBeginBlock{Header: header})
+	coins := sdk.Coins{{"steak", 10}}
+	msg := bank.NewMsgSend([]bank.Input{bank.NewInput(addr, coins)}, []bank.Output{bank.NewOutput(to, coins)})
+	fee := auth.NewStdFee(100000, sdk.Coin{"steak", 5})
+	sig := priv.Sign(auth.StdSignBytes("test-chain", []int64{0}, []int64{0}, fee, msg))
+	tx := auth.NewStdTx(msg, fee, []auth.StdSignature{{PubKey: priv.PubKey(), Signature: sig}})
+	res := gapp.Deliver(tx)
+	require.True(t, res.IsOK(), res.Log)
+	gapp.EndBlock(wrsp.RequestEndBlock{})
+	gapp.Commit()
+
+	ctx := gapp.NewContext(true, wrsp.Header{})
+	require.Equal(t, sdk.Coins{{"steak", 5}}, gapp.feeCollectionKeeper.GetCollectedFees(ctx))
+	require.Equal(t, int64(85), gapp.accountMapper.GetAccount(ctx, addr).GetCoins().AmountOf("steak"))
+	require.Nil(t, gapp.CheckInvariants())
+
+	// the collected fees are exported, so a new chain holds them too
+	exported, _, err := gapp.ExportAppStateAndValidators(false)
+	require.Nil(t, err)
+	newGapp := NewGaiaApp(log.NewNopLogger(), dbm.NewMemDB())
+	newGapp.InitChain(wrsp.RequestInitChain{AppStateBytes: exported})
+	newGapp.Commit()
+	require.Nil(t, newGapp.CheckInvariants())
+	reexported, _, err := newGapp.ExportAppStateAndValidators(false)
+	require.Nil(t, err)
+	require.Equal(t, string(exported), string(reexported))
+}
+
+func TestReservedBondDenom(t *testing.T) {
+	gapp := NewGaiaApp(log.NewNopLogger(), dbm.NewMemDB())
+	addr := sdk.Address(crypto.GenPrivKeyEd25519().PubKey().Address())
+	acc := auth.NewBaseAccountWithAddress(addr)
+	acc.Coins = sdk.Coins{{"fooToken", 10}, {"steak", 100}}
+	genesisState := GenesisState{
+		Accounts:  []GenesisAccount{NewGenesisAccount(&acc)},
+		StakeData: stake.DefaultGenesisState(),
+	}
+	genesisState.StakeData.Pool.LooseTokens = 100
+	stateBytes, err := wire.MarshalJSONIndent(gapp.cdc, genesisState)
+	require.Nil(t, err)
+	gapp.InitChain(wrsp.RequestInitChain{AppStateBytes: stateBytes})
+	gapp.Commit()
+
+	// steak, whose supply stake tracks in its pool, can't be burned by the
+	// bank or leave the chain through IBC, unlike other coins
+	header := wrsp.Header{Height: 2}
+	gapp.BeginBlock(wrsp.RequestBeginBlock{Header: header})
+	ctx := gapp.NewContext(false, header)
+	reserved := sdk.ToWRSPCode(bank.DefaultCodespace, bank.CodeReservedDenom)
+	handler := bank.NewHandler(gapp.coinKeeper)
+	res := handler(ctx, bank.NewMsgBurn(addr, sdk.Coins{{"steak", 10}}))
+	require.Equal(t, reserved, res.Code)
+	res = handler(ctx, bank.NewMsgBurn(addr, sdk.Coins{{"fooToken", 5}}))
+	require.True(t, res.IsOK(), res.Log)
+	packet := ibc.NewIBCPacket(addr, addr, sdk.Coins{{"steak", 10}}, "test-chain", "other-chain")
+	res = ibc.NewHandler(gapp.ibcMapper, gapp.coinKeeper)(ctx, ibc.IBCTransferMsg{IBCPacket: packet})
+	require.Equal(t, reserved, res.Code)
+	gapp.EndBlock(wrsp.RequestEndBlock{})
+	gapp.Commit()
+
+	require.Nil(t, gapp.CheckInvariants())
+	ctx = gapp.NewContext(true, wrsp.Header{})
+	require.Equal(t, sdk.Coins{{"fooToken", 5}, {"steak", 100}}, gapp.accountMapper.GetAccount(ctx, addr).GetCoins())
+}
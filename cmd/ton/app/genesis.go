@@ -1,25 +1,38 @@
 package app
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	crypto "github.com/tepleton/go-crypto"
 	tmtypes "github.com/tepleton/tepleton/types"
 
+	"github.com/tepleton/tepleton-sdk/client"
+	clkeys "github.com/tepleton/tepleton-sdk/client/keys"
+	"github.com/tepleton/tepleton-sdk/crypto/keys"
 	"github.com/tepleton/tepleton-sdk/server"
 	sdk "github.com/tepleton/tepleton-sdk/types"
 	"github.com/tepleton/tepleton-sdk/wire"
 	"github.com/tepleton/tepleton-sdk/x/auth"
+	"github.com/tepleton/tepleton-sdk/x/authz"
+	"github.com/tepleton/tepleton-sdk/x/bank"
+	"github.com/tepleton/tepleton-sdk/x/slashing"
 	"github.com/tepleton/tepleton-sdk/x/stake"
 )
 
 // State to Unmarshal
 type GenesisState struct {
-	Accounts  []GenesisAccount   `json:"accounts"`
-	StakeData stake.GenesisState `json:"stake"`
+	Accounts      []GenesisAccount      `json:"accounts"`
+	StakeData     stake.GenesisState    `json:"stake"`
+	BankData      bank.GenesisState     `json:"bank"`
+	FeeGrants     []auth.FeeGrant       `json:"fee_grants"`
+	CollectedFees sdk.Coins             `json:"collected_fees"`
+	SlashingData  slashing.GenesisState `json:"slashing"`
+	AuthzData     authz.GenesisState    `json:"authz"`
 }
 
 // GenesisAccount doesn't need pubkey or sequence
@@ -54,6 +67,8 @@ var (
 	flagName       = "name"
 	flagClientHome = "home-client"
 	flagOWK        = "owk"
+	flagAmount     = "amount"
+	flagChainID    = "chain-id"
 
 	// bonded tokens given to genesis validators/accounts
 	freeFermionVal  = int64(100)
@@ -65,27 +80,46 @@ func GaiaAppInit() server.AppInit {
 	fsAppGenState := pflag.NewFlagSet("", pflag.ContinueOnError)
 
 	fsAppGenTx := pflag.NewFlagSet("", pflag.ContinueOnError)
-	fsAppGenTx.String(flagName, "", "validator moniker, required")
+	fsAppGenTx.String(flagName, "", "validator moniker, required; an existing key of this name signs the genesis transaction")
 	fsAppGenTx.String(flagClientHome, DefaultCLIHome,
 		"home directory for the client, used for key generation")
 	fsAppGenTx.Bool(flagOWK, false, "overwrite the accounts created")
+	fsAppGenTx.String(flagAmount, fmt.Sprintf("%dsteak", freeFermionVal),
+		"self-delegation of the validator, signed for collect-gentxs")
 
 	return server.AppInit{
-		FlagsAppGenState: fsAppGenState,
-		FlagsAppGenTx:    fsAppGenTx,
-		AppGenTx:         GaiaAppGenTx,
-		AppGenState:      GaiaAppGenStateJSON,
+		FlagsAppGenState:  fsAppGenState,
+		FlagsAppGenTx:     fsAppGenTx,
+		AppGenTx:          GaiaAppGenTx,
+		AppGenState:       GaiaAppGenStateJSON,
+		ValidateAppState:  GaiaValidateGenesisState,
+		Migrations:        GaiaMigrations(),
+		CollectGenTxs:     GaiaCollectGenTxs,
+		AddGenesisAccount: GaiaAddGenesisAccount,
 	}
 }
 
+// genesis migrations of ton, by version
+func GaiaMigrations() server.MigrationMap {
+	migrations := make(server.MigrationMap)
+	migrations.Register("v0.20", "stake", stake.MigrateGenesisV020)
+	return migrations
+}
+
 // simple genesis tx
 type GaiaGenTx struct {
 	Name    string        `json:"name"`
 	Address sdk.Address   `json:"address"`
 	PubKey  crypto.PubKey `json:"pub_key"`
+
+	// creation of the validator signed by the account, nil if unsigned
+	Tx *auth.StdTx `json:"tx,omitempty"`
 }
 
-// Generate a ton genesis transaction with flags
+// Generate a ton genesis transaction with flags. An existing key of the
+// validator name signs it, after prompting for its passphrase, so that a
+// participant of a multi-party network bonds from the account funded with
+// add-genesis-account. Otherwise a new key is created, for a local network.
 func GaiaAppGenTx(cdc *wire.Codec, pk crypto.PubKey) (
 	appGenTx, cliPrint json.RawMessage, validator tmtypes.GenesisValidator, err error) {
 	clientRoot := viper.GetString(flagClientHome)
@@ -95,49 +129,113 @@ func GaiaAppGenTx(cdc *wire.Codec, pk crypto.PubKey) (
 		return nil, nil, tmtypes.GenesisValidator{}, errors.New("Must specify --name (validator moniker)")
 	}
 
-	var addr sdk.Address
-	var secret string
-	addr, secret, err = server.GenerateSaveCoinKey(clientRoot, name, "1234567890", overwrite)
+	var selfDelegation sdk.Coin
+	selfDelegation, err = sdk.ParseCoin(viper.GetString(flagAmount))
 	if err != nil {
 		return
 	}
-	mm := map[string]string{"secret": secret}
+	var keybase keys.Keybase
+	keybase, err = clkeys.GetKeyBaseFromDir(clientRoot)
+	if err != nil {
+		return
+	}
+
+	var info keys.Info
+	var passphrase string
+	mm := map[string]string{}
+	info, err = keybase.Get(name)
+	if err == nil && !overwrite {
+		passphrase, err = client.GetPassword(fmt.Sprintf("Password of the key %s to sign the genesis transaction:", name),
+			client.BufferStdin())
+		if err != nil {
+			return
+		}
+		mm["address"] = sdk.MustBech32ifyAcc(sdk.Address(info.GetPubKey().Address()))
+	} else {
+		var secret string
+		passphrase = "1234567890"
+		_, secret, err = server.GenerateSaveCoinKey(clientRoot, name, passphrase, overwrite)
+		if err != nil {
+			return
+		}
+		mm["secret"] = secret
+	}
 	var bz []byte
 	bz, err = cdc.MarshalJSON(mm)
 	if err != nil {
 		return
 	}
 	cliPrint = json.RawMessage(bz)
-	appGenTx,_,validator,err = GaiaAppGenTxNF(cdc, pk, addr, name, overwrite)
+
+	// sign the creation of the validator so that init can verify it
+	appGenTx, _, validator, err = GaiaAppGenTxNF(cdc, pk, keybase, name, passphrase,
+		viper.GetString(flagChainID), selfDelegation)
 	return
 }
 
-// Generate a ton genesis transaction without flags
-func GaiaAppGenTxNF(cdc *wire.Codec, pk crypto.PubKey, addr sdk.Address, name string, overwrite bool) (
-	appGenTx, cliPrint json.RawMessage, validator tmtypes.GenesisValidator, err error) {
+// SignGenTx signs the creation of the validator of genTx, bonding
+// selfDelegation of its account, with the key name of keybase
+func SignGenTx(keybase keys.Keybase, name, passphrase, chainID string,
+	genTx GaiaGenTx, selfDelegation sdk.Coin) (GaiaGenTx, error) {
 
-	var bz []byte
+	if chainID == "" {
+		return genTx, errors.New("Must specify --chain-id to sign the genesis transaction for")
+	}
+	msg := stake.NewMsgCreateValidator(genTx.Address, genTx.PubKey, selfDelegation,
+		stake.NewDescription(genTx.Name, "", "", ""))
+	fee := auth.NewStdFee(0)
+	signBytes := auth.StdSignBytes(chainID, []int64{0}, []int64{0}, fee, msg)
+	sig, pubKey, err := keybase.Sign(name, passphrase, signBytes)
+	if err != nil {
+		return genTx, err
+	}
+	tx := auth.NewStdTx(msg, fee, []auth.StdSignature{{
+		PubKey:    pubKey,
+		Signature: sig,
+	}})
+	genTx.Tx = &tx
+	return genTx, nil
+}
+
+// Generate a ton genesis transaction without flags, signed with the key
+// name of keybase, which owns the validator
+func GaiaAppGenTxNF(cdc *wire.Codec, pk crypto.PubKey, keybase keys.Keybase, name, passphrase, chainID string,
+	selfDelegation sdk.Coin) (appGenTx, cliPrint json.RawMessage, validator tmtypes.GenesisValidator, err error) {
+
+	var info keys.Info
+	info, err = keybase.Get(name)
+	if err != nil {
+		return
+	}
 	tonGenTx := GaiaGenTx{
 		Name:    name,
-		Address: addr,
+		Address: sdk.Address(info.GetPubKey().Address()),
 		PubKey:  pk,
 	}
+	tonGenTx, err = SignGenTx(keybase, name, passphrase, chainID, tonGenTx, selfDelegation)
+	if err != nil {
+		return
+	}
+	var bz []byte
 	bz, err = wire.MarshalJSONIndent(cdc, tonGenTx)
 	if err != nil {
 		return
 	}
 	appGenTx = json.RawMessage(bz)
 
+	// the power of the validator is the self-delegation bonded by GaiaAppGenState
 	validator = tmtypes.GenesisValidator{
 		PubKey: pk,
-		Power:  freeFermionVal,
+		Power:  selfDelegation.Amount,
 	}
 	return
 }
 
-// Create the core parameters for genesis initialization for ton
+// Create the core parameters for genesis initialization for ton. Every
+// genesis transaction must be signed for chainID by the account of its
+// validator, which bonds the signed self-delegation.
 // note that the pubkey input is this machines pubkey
-func GaiaAppGenState(cdc *wire.Codec, appGenTxs []json.RawMessage) (genesisState GenesisState, err error) {
+func GaiaAppGenState(cdc *wire.Codec, chainID string, appGenTxs []json.RawMessage) (genesisState GenesisState, err error) {
 
 	if len(appGenTxs) == 0 {
 		err = errors.New("must provide at least genesis transaction")
@@ -156,6 +254,15 @@ func GaiaAppGenState(cdc *wire.Codec, appGenTxs []json.RawMessage) (genesisState
 		if err != nil {
 			return
 		}
+		var msg stake.MsgCreateValidator
+		msg, err = verifyGenTx(chainID, genTx)
+		if err != nil {
+			return
+		}
+		if msg.SelfDelegation.Denom != stakeData.Params.BondDenom {
+			err = fmt.Errorf("validator %s must bond %s", genTx.Name, stakeData.Params.BondDenom)
+			return
+		}
 
 		// create the genesis account, give'm few steaks and a buncha token with there name
 		accAuth := auth.NewBaseAccountWithAddress(genTx.Address)
@@ -165,19 +272,19 @@ func GaiaAppGenState(cdc *wire.Codec, appGenTxs []json.RawMessage) (genesisState
 		}
 		acc := NewGenesisAccount(&accAuth)
 		genaccs[i] = acc
-		stakeData.Pool.LooseUnbondedTokens += freeFermionsAcc // increase the supply
-
-		// add the validator
-		if len(genTx.Name) > 0 {
-			desc := stake.NewDescription(genTx.Name, "", "", "")
-			validator := stake.NewValidator(genTx.Address, genTx.PubKey, desc)
-			validator.PoolShares = stake.NewBondedShares(sdk.NewRat(freeFermionVal))
-			stakeData.Validators = append(stakeData.Validators, validator)
-
-			// pool logic
-			stakeData.Pool.BondedTokens += freeFermionVal
-			stakeData.Pool.BondedShares = sdk.NewRat(stakeData.Pool.BondedTokens)
-		}
+		stakeData.Pool.LooseTokens += freeFermionsAcc // increase the supply
+
+		// add the validator, bonding its self-delegation
+		stakeData.Pool.LooseTokens += msg.SelfDelegation.Amount
+		validator := stake.NewValidator(msg.ValidatorAddr, msg.PubKey, msg.Description)
+		var shares sdk.Rat
+		validator, stakeData.Pool, shares = validator.AddTokensFromDel(stakeData.Pool, msg.SelfDelegation.Amount)
+		stakeData.Validators = append(stakeData.Validators, validator)
+		stakeData.Bonds = append(stakeData.Bonds, stake.Delegation{
+			DelegatorAddr: msg.ValidatorAddr,
+			ValidatorAddr: msg.ValidatorAddr,
+			Shares:        shares,
+		})
 	}
 
 	// create the final app state
@@ -188,14 +295,229 @@ func GaiaAppGenState(cdc *wire.Codec, appGenTxs []json.RawMessage) (genesisState
 	return
 }
 
-// GaiaAppGenState but with JSON
+// GaiaAppGenState but with JSON, for the chain-id of the init command
 func GaiaAppGenStateJSON(cdc *wire.Codec, appGenTxs []json.RawMessage) (appState json.RawMessage, err error) {
 
 	// create the final app state
-	genesisState, err := GaiaAppGenState(cdc, appGenTxs)
+	genesisState, err := GaiaAppGenState(cdc, viper.GetString(flagChainID), appGenTxs)
 	if err != nil {
 		return nil, err
 	}
 	appState, err = wire.MarshalJSONIndent(cdc, genesisState)
 	return
 }
+
+// GaiaValidateGenesisState checks the app state of a ton genesis file
+func GaiaValidateGenesisState(cdc *wire.Codec, appState json.RawMessage) error {
+	var genesisState GenesisState
+	err := cdc.UnmarshalJSON(appState, &genesisState)
+	if err != nil {
+		return err
+	}
+
+	addrs := make(map[string]bool, len(genesisState.Accounts))
+	for _, acc := range genesisState.Accounts {
+		if addrs[string(acc.Address)] {
+			return fmt.Errorf("duplicate account %v in genesis state", acc.Address)
+		}
+		addrs[string(acc.Address)] = true
+		if !acc.Coins.IsValid() {
+			return fmt.Errorf("invalid coins %v of account %v", acc.Coins, acc.Address)
+		}
+	}
+	if !genesisState.CollectedFees.IsValid() || !genesisState.CollectedFees.IsNotNegative() {
+		return fmt.Errorf("invalid collected fees %v", genesisState.CollectedFees)
+	}
+	err = bank.ValidateGenesis(genesisState.BankData)
+	if err != nil {
+		return err
+	}
+	_, err = genesisSupply(genesisState)
+	if err != nil {
+		return err
+	}
+	return stake.ValidateGenesis(genesisState.StakeData)
+}
+
+// genesisSupply returns the supply of the genesis: the coins of the
+// accounts, the collected fees and those held by stake. A supply set in the
+// bank genesis, as exported, must match it, so that every coin can be burned.
+func genesisSupply(genesisState GenesisState) (sdk.Coins, error) {
+	provisions, err := genesisProvisions(genesisState)
+	if err != nil {
+		return nil, err
+	}
+	supply := genesisState.CollectedFees.Sort()
+	for _, acc := range genesisState.Accounts {
+		supply = supply.Plus(acc.Coins.Sort())
+	}
+	pool := genesisState.StakeData.Pool
+	denom := genesisState.StakeData.Params.BondDenom
+	staked := pool.BondedTokens + pool.UnbondingTokens + pool.UnbondedTokens + provisions
+	if staked != 0 {
+		supply = supply.Plus(sdk.Coins{{denom, staked}})
+	}
+
+	if len(genesisState.BankData.Supply) != 0 && !genesisState.BankData.Supply.IsEqual(supply) {
+		return nil, fmt.Errorf("the supply %v of the genesis doesn't match the coins of the accounts, fees and stake %v",
+			genesisState.BankData.Supply, supply)
+	}
+	return supply, nil
+}
+
+// genesisProvisions returns the provisions no account holds: the loose
+// tokens of the stake pool beyond those of the accounts and the collected
+// fees. They are tracked by the pool, except in a state migrated from
+// before v0.20, whose untracked provisions are found this way.
+func genesisProvisions(genesisState GenesisState) (int64, error) {
+	pool := genesisState.StakeData.Pool
+	denom := genesisState.StakeData.Params.BondDenom
+	held := genesisState.CollectedFees.AmountOf(denom)
+	for _, acc := range genesisState.Accounts {
+		held += acc.Coins.AmountOf(denom)
+	}
+	provisions := pool.LooseTokens - held
+	if provisions < 0 || (pool.Provisions != 0 && pool.Provisions != provisions) {
+		return 0, fmt.Errorf("the loose tokens %d of the stake pool don't match the %d held by the accounts and the fees, and the provisions %d",
+			pool.LooseTokens, held, pool.Provisions)
+	}
+	return provisions, nil
+}
+
+// GaiaCollectGenTxs applies the signed genesis transactions of all
+// validators to appState. Every validator must be signed for chainID by
+// its account, and the account must hold the self-delegation in the genesis.
+func GaiaCollectGenTxs(cdc *wire.Codec, chainID string, appState json.RawMessage, appGenTxs []json.RawMessage) (
+	json.RawMessage, []tmtypes.GenesisValidator, error) {
+
+	var genesisState GenesisState
+	err := cdc.UnmarshalJSON(appState, &genesisState)
+	if err != nil {
+		return nil, nil, err
+	}
+	accounts := make(map[string]int, len(genesisState.Accounts))
+	for i, acc := range genesisState.Accounts {
+		accounts[string(acc.Address)] = i
+	}
+
+	stakeData := genesisState.StakeData
+	var validators []tmtypes.GenesisValidator
+	for _, appGenTx := range appGenTxs {
+		var genTx GaiaGenTx
+		err = cdc.UnmarshalJSON(appGenTx, &genTx)
+		if err != nil {
+			return nil, nil, err
+		}
+		msg, err := verifyGenTx(chainID, genTx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// move the self-delegation from the account to the validator
+		i, ok := accounts[string(msg.ValidatorAddr)]
+		if !ok {
+			return nil, nil, fmt.Errorf("account %v of validator %s is not in the genesis", msg.ValidatorAddr, genTx.Name)
+		}
+		acc := &genesisState.Accounts[i]
+		amount := msg.SelfDelegation.Amount
+		if msg.SelfDelegation.Denom != stakeData.Params.BondDenom {
+			return nil, nil, fmt.Errorf("validator %s must bond %s", genTx.Name, stakeData.Params.BondDenom)
+		}
+		if !acc.Coins.IsGTE(sdk.Coins{msg.SelfDelegation}) {
+			return nil, nil, fmt.Errorf("account %v of validator %s has %v, less than its self-delegation %v",
+				acc.Address, genTx.Name, acc.Coins, msg.SelfDelegation)
+		}
+		if stakeData.Pool.LooseTokens < amount {
+			return nil, nil, fmt.Errorf("the loose tokens of the genesis pool don't cover the self-delegation of validator %s", genTx.Name)
+		}
+		acc.Coins = acc.Coins.Minus(sdk.Coins{msg.SelfDelegation})
+
+		validator := stake.NewValidator(msg.ValidatorAddr, msg.PubKey, msg.Description)
+		var shares sdk.Rat
+		validator, stakeData.Pool, shares = validator.AddTokensFromDel(stakeData.Pool, amount)
+		stakeData.Validators = append(stakeData.Validators, validator)
+		stakeData.Bonds = append(stakeData.Bonds, stake.Delegation{
+			DelegatorAddr: msg.ValidatorAddr,
+			ValidatorAddr: msg.ValidatorAddr,
+			Shares:        shares,
+		})
+		validators = append(validators, tmtypes.GenesisValidator{
+			PubKey: msg.PubKey,
+			Power:  amount,
+			Name:   msg.Moniker,
+		})
+	}
+	err = stake.ValidateGenesis(stakeData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	genesisState.StakeData = stakeData
+	appState, err = wire.MarshalJSONIndent(cdc, genesisState)
+	if err != nil {
+		return nil, nil, err
+	}
+	return appState, validators, nil
+}
+
+// verifyGenTx checks that the creation of the validator of genTx is signed
+// by its account for chainID
+func verifyGenTx(chainID string, genTx GaiaGenTx) (msg stake.MsgCreateValidator, err error) {
+	if genTx.Tx == nil {
+		return msg, fmt.Errorf("genesis transaction of %s is not signed", genTx.Name)
+	}
+	tx := *genTx.Tx
+	msg, ok := tx.Msg.(stake.MsgCreateValidator)
+	if !ok {
+		return msg, fmt.Errorf("genesis transaction of %s must create a validator", genTx.Name)
+	}
+	if sdkErr := msg.ValidateBasic(); sdkErr != nil {
+		return msg, fmt.Errorf("invalid genesis transaction of %s: %v", genTx.Name, sdkErr.Error())
+	}
+	if !msg.PubKey.Equals(genTx.PubKey) || !bytes.Equal(msg.ValidatorAddr, genTx.Address) {
+		return msg, fmt.Errorf("genesis transaction of %s doesn't match its validator", genTx.Name)
+	}
+	if len(tx.Signatures) != 1 {
+		return msg, fmt.Errorf("genesis transaction of %s must have one signature", genTx.Name)
+	}
+	sig := tx.Signatures[0]
+	if sig.PubKey == nil || !bytes.Equal(sig.PubKey.Address(), msg.ValidatorAddr) {
+		return msg, fmt.Errorf("genesis transaction of %s isn't signed by its account", genTx.Name)
+	}
+	signBytes := auth.StdSignBytes(chainID, []int64{sig.AccountNumber}, []int64{sig.Sequence}, tx.Fee, msg)
+	if !sig.PubKey.VerifyBytes(signBytes, sig.Signature) {
+		return msg, fmt.Errorf("invalid signature of the genesis transaction of %s for chain %s", genTx.Name, chainID)
+	}
+	return msg, nil
+}
+
+// GaiaAddGenesisAccount adds an account holding coins to appState. Its coins
+// of the bond denomination are loose tokens of the stake pool, and a supply
+// set in the bank genesis grows with its coins.
+func GaiaAddGenesisAccount(cdc *wire.Codec, appState json.RawMessage, addr sdk.Address, coins sdk.Coins) (
+	json.RawMessage, error) {
+
+	var genesisState GenesisState
+	err := cdc.UnmarshalJSON(appState, &genesisState)
+	if err != nil {
+		return nil, err
+	}
+	coins = coins.Sort()
+	if !coins.IsValid() || !coins.IsPositive() {
+		return nil, fmt.Errorf("invalid coins %v of account %v", coins, addr)
+	}
+	for _, acc := range genesisState.Accounts {
+		if bytes.Equal(acc.Address, addr) {
+			return nil, fmt.Errorf("account %v is already in the genesis", addr)
+		}
+	}
+
+	authAcc := auth.NewBaseAccountWithAddress(addr)
+	authAcc.Coins = coins
+	genesisState.Accounts = append(genesisState.Accounts, NewGenesisAccount(&authAcc))
+	genesisState.StakeData.Pool.LooseTokens += coins.AmountOf(genesisState.StakeData.Params.BondDenom)
+	if len(genesisState.BankData.Supply) != 0 {
+		genesisState.BankData.Supply = genesisState.BankData.Supply.Plus(coins)
+	}
+	return wire.MarshalJSONIndent(cdc, genesisState)
+}
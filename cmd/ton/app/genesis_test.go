@@ -1,12 +1,21 @@
 package app
 
 import (
+	"encoding/json"
 	"testing"
 
+	"github.com/tepleton/tepleton-sdk/crypto/keys"
 	sdk "github.com/tepleton/tepleton-sdk/types"
+	"github.com/tepleton/tepleton-sdk/wire"
 	"github.com/tepleton/tepleton-sdk/x/auth"
+	"github.com/tepleton/tepleton-sdk/x/bank"
+	"github.com/tepleton/tepleton-sdk/x/stake"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	crypto "github.com/tepleton/go-crypto"
+	dbm "github.com/tepleton/tmlibs/db"
+	"github.com/tepleton/tmlibs/log"
+	wrsp "github.com/tepleton/wrsp/types"
 )
 
 func TestToAccount(t *testing.T) {
@@ -28,9 +37,219 @@ func TestGaiaAppGenTx(t *testing.T) {
 
 func TestGaiaAppGenState(t *testing.T) {
 	cdc := MakeCodec()
-	_ = cdc
+	keybase := keys.NewInMemory()
+	_, _, err := keybase.CreateMnemonic("validator", keys.English, "1234567890", "", keys.Ed25519)
+	require.Nil(t, err)
+	pk := crypto.GenPrivKeyEd25519().PubKey()
+
+	_, err = GaiaAppGenState(cdc, "test-chain", nil)
+	require.NotNil(t, err)
+
+	// the validator bonds the self-delegation it signed, with the power of
+	// its genesis validator
+	appGenTx, _, validator, err := GaiaAppGenTxNF(cdc, pk, keybase, "validator", "1234567890",
+		"test-chain", sdk.Coin{"steak", 70})
+	require.Nil(t, err)
+	genState, err := GaiaAppGenState(cdc, "test-chain", []json.RawMessage{appGenTx})
+	require.Nil(t, err)
+	require.Equal(t, int64(70), validator.Power)
+	require.Equal(t, 1, len(genState.Accounts))
+	require.Equal(t, 1, len(genState.StakeData.Validators))
+	require.Equal(t, validator.Power, genState.StakeData.Validators[0].PoolShares.Tokens(genState.StakeData.Pool).RoundInt64())
+	require.Nil(t, stake.ValidateGenesis(genState.StakeData))
+
+	// signed for another chain
+	_, err = GaiaAppGenState(cdc, "other-chain", []json.RawMessage{appGenTx})
+	require.NotNil(t, err)
+
+	// unsigned
+	var genTx GaiaGenTx
+	require.Nil(t, cdc.UnmarshalJSON(appGenTx, &genTx))
+	genTx.Tx = nil
+	appGenTx, err = wire.MarshalJSONIndent(cdc, genTx)
+	require.Nil(t, err)
+	_, err = GaiaAppGenState(cdc, "test-chain", []json.RawMessage{appGenTx})
+	require.NotNil(t, err)
+}
+
+func TestGaiaValidateGenesisState(t *testing.T) {
+	cdc := MakeCodec()
+	priv := crypto.GenPrivKeyEd25519()
+	addr := sdk.Address(priv.PubKey().Address())
+	authAcc := auth.NewBaseAccountWithAddress(addr)
+	authAcc.Coins = sdk.Coins{{"steak", 10}}
+	genState := GenesisState{
+		Accounts:  []GenesisAccount{NewGenesisAccount(&authAcc)},
+		StakeData: stake.DefaultGenesisState(),
+	}
+	genState.StakeData.Pool.LooseTokens = 10
+	appState, err := wire.MarshalJSONIndent(cdc, genState)
+	assert.Nil(t, err)
+	assert.Nil(t, GaiaValidateGenesisState(cdc, appState))
+
+	// duplicate accounts are rejected
+	genState.Accounts = append(genState.Accounts, NewGenesisAccount(&authAcc))
+	appState, err = wire.MarshalJSONIndent(cdc, genState)
+	assert.Nil(t, err)
+	assert.NotNil(t, GaiaValidateGenesisState(cdc, appState))
+}
+
+func TestGenesisSupply(t *testing.T) {
+	addr := sdk.Address(crypto.GenPrivKeyEd25519().PubKey().Address())
+	authAcc := auth.NewBaseAccountWithAddress(addr)
+	authAcc.Coins = sdk.Coins{{"fooToken", 20}, {"steak", 10}}
+	genState := GenesisState{
+		Accounts:  []GenesisAccount{NewGenesisAccount(&authAcc)},
+		StakeData: stake.DefaultGenesisState(),
+	}
+	genState.StakeData.Pool.LooseTokens = 10
+	genState.StakeData.Pool.BondedTokens = 5
+
+	// the supply is derived from the accounts and the pool
+	supply, err := genesisSupply(genState)
+	assert.Nil(t, err)
+	assert.True(t, supply.IsEqual(sdk.Coins{{"fooToken", 20}, {"steak", 15}}))
+
+	// loose tokens beyond those of the accounts and the collected fees are
+	// provisions, part of the supply
+	genState.CollectedFees = sdk.Coins{{"steak", 1}}
+	genState.StakeData.Pool.LooseTokens = 13
+	provisions, err := genesisProvisions(genState)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), provisions)
+	supply, err = genesisSupply(genState)
+	assert.Nil(t, err)
+	assert.True(t, supply.IsEqual(sdk.Coins{{"fooToken", 20}, {"steak", 18}}))
+
+	// the provisions tracked by the pool must match them, and the loose
+	// tokens must cover the accounts and the fees
+	genState.StakeData.Pool.Provisions = 2
+	_, err = genesisSupply(genState)
+	assert.Nil(t, err)
+	genState.StakeData.Pool.Provisions = 1
+	_, err = genesisSupply(genState)
+	assert.NotNil(t, err)
+	genState.StakeData.Pool.Provisions = 0
+	genState.StakeData.Pool.LooseTokens = 10
+	_, err = genesisSupply(genState)
+	assert.NotNil(t, err)
+	genState.StakeData.Pool.LooseTokens = 13
+
+	// an empty supply in the bank genesis is derived too, a set one must match
+	genState.BankData = bank.DefaultGenesisState()
+	supply, err = genesisSupply(genState)
+	assert.Nil(t, err)
+	assert.True(t, supply.IsEqual(sdk.Coins{{"fooToken", 20}, {"steak", 18}}))
+	genState.BankData.Supply = sdk.Coins{{"fooToken", 20}, {"steak", 18}}
+	_, err = genesisSupply(genState)
+	assert.Nil(t, err)
+	genState.BankData.Supply = sdk.Coins{{"steak", 100}}
+	_, err = genesisSupply(genState)
+	assert.NotNil(t, err)
+}
+
+func TestGaiaCollectGenTxs(t *testing.T) {
+	cdc := MakeCodec()
+	keybase := keys.NewInMemory()
+	info, _, err := keybase.CreateMnemonic("validator", keys.English, "1234567890", "", keys.Ed25519)
+	require.Nil(t, err)
+	addr := sdk.Address(info.GetPubKey().Address())
+	valPubKey := crypto.GenPrivKeyEd25519().PubKey()
+
+	// the genesis declares the account holding the self-delegation
+	authAcc := auth.NewBaseAccountWithAddress(addr)
+	authAcc.Coins = sdk.Coins{{"steak", 150}}
+	stakeData := stake.DefaultGenesisState()
+	stakeData.Pool.LooseTokens = 150
+	appState, err := wire.MarshalJSONIndent(cdc, GenesisState{
+		Accounts:  []GenesisAccount{NewGenesisAccount(&authAcc)},
+		StakeData: stakeData,
+	})
+	require.Nil(t, err)
+
+	genTx := GaiaGenTx{Name: "validator", Address: addr, PubKey: valPubKey}
+	genTx, err = SignGenTx(keybase, "validator", "1234567890", "test-chain", genTx, sdk.Coin{"steak", 100})
+	require.Nil(t, err)
+	appGenTx, err := wire.MarshalJSONIndent(cdc, genTx)
+	require.Nil(t, err)
+
+	collected, validators, err := GaiaCollectGenTxs(cdc, "test-chain", appState, []json.RawMessage{appGenTx})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(validators))
+	require.True(t, valPubKey.Equals(validators[0].PubKey))
+	require.Equal(t, int64(100), validators[0].Power)
+	var genesisState GenesisState
+	err = cdc.UnmarshalJSON(collected, &genesisState)
+	require.Nil(t, err)
+	require.Equal(t, sdk.Coins{{"steak", 50}}, genesisState.Accounts[0].Coins)
+	require.Equal(t, 1, len(genesisState.StakeData.Validators))
+	require.Equal(t, 1, len(genesisState.StakeData.Bonds))
+	require.Nil(t, GaiaValidateGenesisState(cdc, collected))
+
+	// signed for another chain
+	_, _, err = GaiaCollectGenTxs(cdc, "other-chain", appState, []json.RawMessage{appGenTx})
+	require.NotNil(t, err)
+
+	// self-delegation above the balance of the account
+	genTx, err = SignGenTx(keybase, "validator", "1234567890", "test-chain", genTx, sdk.Coin{"steak", 200})
+	require.Nil(t, err)
+	appGenTx, err = wire.MarshalJSONIndent(cdc, genTx)
+	require.Nil(t, err)
+	_, _, err = GaiaCollectGenTxs(cdc, "test-chain", appState, []json.RawMessage{appGenTx})
+	require.NotNil(t, err)
+
+	// unsigned
+	genTx.Tx = nil
+	appGenTx, err = wire.MarshalJSONIndent(cdc, genTx)
+	require.Nil(t, err)
+	_, _, err = GaiaCollectGenTxs(cdc, "test-chain", appState, []json.RawMessage{appGenTx})
+	require.NotNil(t, err)
+}
+
+func TestGaiaAddGenesisAccount(t *testing.T) {
+	cdc := MakeCodec()
+	keybase := keys.NewInMemory()
+
+	// the coordinator creates the genesis with its own validator
+	_, _, err := keybase.CreateMnemonic("coordinator", keys.English, "1234567890", "", keys.Ed25519)
+	require.Nil(t, err)
+	appGenTx, _, _, err := GaiaAppGenTxNF(cdc, crypto.GenPrivKeyEd25519().PubKey(), keybase, "coordinator", "1234567890",
+		"test-chain", sdk.Coin{"steak", 100})
+	require.Nil(t, err)
+	genState, err := GaiaAppGenState(cdc, "test-chain", []json.RawMessage{appGenTx})
+	require.Nil(t, err)
+	appState, err := wire.MarshalJSONIndent(cdc, genState)
+	require.Nil(t, err)
+
+	// and funds the account of a participant, created beforehand
+	info, _, err := keybase.CreateMnemonic("participant", keys.English, "participantpass", "", keys.Ed25519)
+	require.Nil(t, err)
+	addr := sdk.Address(info.GetPubKey().Address())
+	appState, err = GaiaAddGenesisAccount(cdc, appState, addr, sdk.Coins{{"steak", 150}})
+	require.Nil(t, err)
+	require.Nil(t, GaiaValidateGenesisState(cdc, appState))
+	_, err = GaiaAddGenesisAccount(cdc, appState, addr, sdk.Coins{{"steak", 150}})
+	require.NotNil(t, err)
+	_, err = GaiaAddGenesisAccount(cdc, appState, sdk.Address(crypto.GenPrivKeyEd25519().PubKey().Address()), sdk.Coins{})
+	require.NotNil(t, err)
+
+	// the participant signs its validator with the key of its account
+	valPubKey := crypto.GenPrivKeyEd25519().PubKey()
+	appGenTx, _, _, err = GaiaAppGenTxNF(cdc, valPubKey, keybase, "participant", "participantpass",
+		"test-chain", sdk.Coin{"steak", 100})
+	require.Nil(t, err)
+	collected, validators, err := GaiaCollectGenTxs(cdc, "test-chain", appState, []json.RawMessage{appGenTx})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(validators))
+	require.True(t, valPubKey.Equals(validators[0].PubKey))
+	require.Nil(t, GaiaValidateGenesisState(cdc, collected))
 
-	// TODO test must provide at least genesis transaction
-	// TODO test with both one and two genesis transactions:
-	// TODO        correct: genesis account created, canididates created, pool token variance
+	// the chain starts from the collected genesis
+	gapp := NewGaiaApp(log.NewNopLogger(), dbm.NewMemDB())
+	gapp.InitChain(wrsp.RequestInitChain{AppStateBytes: collected})
+	gapp.Commit()
+	require.Nil(t, gapp.CheckInvariants())
+	ctx := gapp.NewContext(true, wrsp.Header{})
+	require.Equal(t, int64(50), gapp.accountMapper.GetAccount(ctx, addr).GetCoins().AmountOf("steak"))
+	require.Equal(t, 2, len(gapp.stakeKeeper.GetAllValidators(ctx)))
 }
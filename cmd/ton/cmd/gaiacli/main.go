@@ -12,6 +12,7 @@ import (
 	"github.com/tepleton/tepleton-sdk/client/tx"
 	"github.com/tepleton/tepleton-sdk/version"
 	authcmd "github.com/tepleton/tepleton-sdk/x/auth/client/cli"
+	authzcmd "github.com/tepleton/tepleton-sdk/x/authz/client/cli"
 	bankcmd "github.com/tepleton/tepleton-sdk/x/bank/client/cli"
 	ibccmd "github.com/tepleton/tepleton-sdk/x/ibc/client/cli"
 	slashingcmd "github.com/tepleton/tepleton-sdk/x/slashing/client/cli"
@@ -105,10 +106,22 @@ func main() {
 	rootCmd.AddCommand(
 		client.GetCommands(
 			authcmd.GetAccountCmd("acc", cdc, authcmd.GetAccountDecoder(cdc)),
+			bankcmd.GetCmdQuerySupply("bank", cdc),
+			authcmd.GetCmdQueryFeeGrants("feegrant", cdc),
+			authzcmd.GetCmdQueryGrants("authz", cdc),
 		)...)
 	rootCmd.AddCommand(
 		client.PostCommands(
 			bankcmd.SendTxCmd(cdc),
+			bankcmd.IssueTxCmd(cdc),
+			bankcmd.BurnTxCmd(cdc),
+			bankcmd.SetIssuerTxCmd(cdc),
+			bankcmd.SetSendEnabledTxCmd(cdc),
+			authcmd.GetCmdGrantFeeAllowance(cdc),
+			authcmd.GetCmdRevokeFeeAllowance(cdc),
+			authzcmd.GetCmdGrant(cdc),
+			authzcmd.GetCmdRevoke(cdc),
+			authzcmd.GetCmdExec(cdc),
 		)...)
 
 	// add proxy, version and key info
@@ -237,7 +237,11 @@ func (app *GaiaApp) initChainer(ctx sdk.Context, req wrsp.RequestInitChain) wrsp
 	}
 
 	// load the initial stake information
-	stake.InitGenesis(ctx, app.stakeKeeper, genesisState.StakeData)
+	err = stake.InitGenesis(ctx, app.stakeKeeper, genesisState.StakeData)
+	if err != nil {
+		panic(err) // TODO https://github.com/tepleton/tepleton-sdk/issues/468
+		// return sdk.ErrGenesisParse("").TraceCause(err, "")
+	}
 	return wrsp.ResponseInitChain{}
 
 }
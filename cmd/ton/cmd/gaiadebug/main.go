@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	ton "github.com/tepleton/tepleton-sdk/cmd/ton/app"
+	"github.com/tepleton/tepleton-sdk/store"
 	"github.com/tepleton/tepleton-sdk/x/auth"
 	"github.com/spf13/cobra"
 	crypto "github.com/tepleton/go-crypto"
@@ -21,6 +22,7 @@ func init() {
 	rootCmd.AddCommand(pubkeyCmd)
 	rootCmd.AddCommand(hackCmd)
 	rootCmd.AddCommand(rawBytesCmd)
+	rootCmd.AddCommand(traceDiffCmd)
 }
 
 var rootCmd = &cobra.Command{
@@ -53,6 +55,44 @@ var rawBytesCmd = &cobra.Command{
 	RunE:  runRawBytesCmd,
 }
 
+var traceDiffCmd = &cobra.Command{
+	Use:   "trace-diff <trace-a> <trace-b>",
+	Short: "Report the first divergence of two store traces written with --trace-store",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runTraceDiffCmd,
+}
+
+func runTraceDiffCmd(cmd *cobra.Command, args []string) error {
+	a, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+	b, err := os.Open(args[1])
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	div, err := store.FirstTraceDivergence(a, b)
+	if err != nil {
+		return err
+	}
+	if div == nil {
+		fmt.Println("Traces are identical")
+		return nil
+	}
+	for i, op := range []*store.TraceOperation{div.A, div.B} {
+		if op == nil {
+			fmt.Printf("%s: <end of trace>\n", args[i])
+			continue
+		}
+		fmt.Printf("%s: %s %s key=%X value=%X %v\n", args[i], op.Operation, op.Store, op.Key, op.Value, op.Metadata)
+	}
+	// fail, to signal the divergence to scripts
+	return fmt.Errorf("Traces diverge at line %d", div.Line)
+}
+
 func runRawBytesCmd(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
 		return fmt.Errorf("Expected single arg")
@@ -38,7 +38,13 @@ func newApp(logger log.Logger, db dbm.DB) wrsp.Application {
 	return app.NewGaiaApp(logger, db)
 }
 
-func exportAppStateAndTMValidators(logger log.Logger, db dbm.DB) (json.RawMessage, []tmtypes.GenesisValidator, error) {
+func exportAppStateAndTMValidators(logger log.Logger, db dbm.DB, height int64, forZeroHeight bool) (json.RawMessage, []tmtypes.GenesisValidator, error) {
 	gapp := app.NewGaiaApp(logger, db)
-	return gapp.ExportAppStateAndValidators()
+	if height != -1 {
+		err := gapp.LoadHeight(height)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return gapp.ExportAppStateAndValidators(forZeroHeight)
 }
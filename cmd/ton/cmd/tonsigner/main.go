@@ -0,0 +1,304 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	tcrypto "github.com/tepleton/tepleton/crypto"
+	pvm "github.com/tepleton/tepleton/privval"
+	"github.com/tepleton/tepleton/types"
+	"github.com/tepleton/tmlibs/cli"
+	"github.com/tepleton/tmlibs/log"
+
+	"github.com/tepleton/tepleton-sdk/client"
+	"github.com/tepleton/tepleton-sdk/crypto/keys"
+	"github.com/tepleton/tepleton-sdk/crypto/keys/remote"
+)
+
+const (
+	// identityKey is the name of the key the signer authenticates with
+	identityKey = "identity"
+
+	flagAlgo         = "algo"
+	flagLaddr        = "laddr"
+	flagValidatorKey = "validator-key"
+	flagAllow        = "allow"
+)
+
+// DefaultSignerHome is the default home of the signer, holding its keys
+// and the last height, round and step signed
+var DefaultSignerHome = os.ExpandEnv("$HOME/.tonsigner")
+
+var rootCmd = &cobra.Command{
+	Use:   "tonsigner",
+	Short: "Remote signer for validator and client keys",
+}
+
+func main() {
+	cobra.EnableCommandSorting = false
+
+	keysCmd := &cobra.Command{
+		Use:   "keys",
+		Short: "Manage the keys of the signer",
+	}
+	keysCmd.AddCommand(
+		addKeyCmd(),
+		importValidatorCmd(),
+		listKeysCmd(),
+		updateKeysCmd(),
+	)
+	rootCmd.AddCommand(
+		initCmd(),
+		keysCmd,
+		startCmd(),
+	)
+
+	executor := cli.PrepareBaseCmd(rootCmd, "TS", DefaultSignerHome)
+	executor.Execute()
+}
+
+// keybase opens the keybase of the signer, all its keys are encrypted with
+// the same passphrase
+func keybase() keys.Keybase {
+	dir := filepath.Join(viper.GetString(cli.HomeFlag), "keys")
+	return keys.NewWithStorage(keys.NewFileStorage(dir))
+}
+
+func initCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "init",
+		Short: "Generate the identity key the signer authenticates to its clients with",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kb := keybase()
+			if _, err := kb.Get(identityKey); err == nil {
+				return fmt.Errorf("the signer is initialized already")
+			}
+			buf := client.BufferStdin()
+			passwd, err := client.GetCheckPassword(
+				"Enter a passphrase to encrypt the keys of the signer:",
+				"Repeat the passphrase:", buf)
+			if err != nil {
+				return err
+			}
+			info, err := kb.ImportPrivKey(identityKey, tcrypto.GenPrivKeyEd25519(), passwd)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Signer identity: %X\n", info.GetPubKey().Bytes())
+			return nil
+		},
+	}
+}
+
+func addKeyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Create a new key, printing its mnemonic",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			passwd, err := client.GetPassword("Passphrase of the signer:", client.BufferStdin())
+			if err != nil {
+				return err
+			}
+			algo := keys.SigningAlgo(viper.GetString(flagAlgo))
+			info, mnemonic, err := keybase().CreateMnemonic(args[0], keys.English, passwd, "", algo)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s\t%X\n", info.GetName(), info.GetPubKey().Bytes())
+			fmt.Printf("**Important** write this mnemonic down in a safe place.\n%s\n", mnemonic)
+			return nil
+		},
+	}
+	cmd.Flags().String(flagAlgo, string(keys.Secp256k1), "Signing algo of the key: secp256k1, ed25519 or secp256r1")
+	return cmd
+}
+
+func importValidatorCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "import-validator <name> <priv_validator.json>",
+		Short: "Import the validator key of a priv_validator.json file",
+		Long: `Import the validator key of a priv_validator.json file. Delete the file
+from the node once the signer runs, the signer keeps track of the last
+height, round and step signed by itself.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			passwd, err := client.GetPassword("Passphrase of the signer:", client.BufferStdin())
+			if err != nil {
+				return err
+			}
+			filePV := pvm.LoadFilePV(args[1])
+			info, err := keybase().ImportPrivKey(args[0], filePV.PrivKey, passwd)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s\t%X\n", info.GetName(), info.GetPubKey().Bytes())
+			return nil
+		},
+	}
+}
+
+func listKeysCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the keys of the signer",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			infos, err := keybase().List()
+			if err != nil {
+				return err
+			}
+			for _, info := range infos {
+				fmt.Printf("%s\t%X\n", info.GetName(), info.GetPubKey().Bytes())
+			}
+			return nil
+		},
+	}
+}
+
+func updateKeysCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "update",
+		Short: "Encrypt all keys of the signer again in the current format, optionally with a new passphrase",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			buf := client.BufferStdin()
+			oldpass, err := client.GetPassword("Passphrase of the signer:", buf)
+			if err != nil {
+				return err
+			}
+			newpass, err := client.GetCheckPassword(
+				"Enter the new passphrase, or the current one to keep it:",
+				"Repeat the new passphrase:", buf)
+			if err != nil {
+				return err
+			}
+			kb := keybase()
+			infos, err := kb.List()
+			if err != nil {
+				return err
+			}
+			// check the passphrase of every key before updating any, so
+			// that all keys keep sharing the same passphrase
+			for _, info := range infos {
+				if _, err := kb.ExportPrivKey(info.GetName(), oldpass); err != nil {
+					return fmt.Errorf("%s: %v", info.GetName(), err)
+				}
+			}
+			for _, info := range infos {
+				if err := kb.Update(info.GetName(), oldpass, newpass); err != nil {
+					return fmt.Errorf("%s: %v", info.GetName(), err)
+				}
+				fmt.Printf("%s\tupdated\n", info.GetName())
+			}
+			return nil
+		},
+	}
+}
+
+func startCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Serve the keys of the signer to its clients",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger := log.NewTMLogger(log.NewSyncWriter(os.Stdout)).With("module", "signer")
+			kb := keybase()
+			passwd, err := client.GetPassword("Passphrase of the signer:", client.BufferStdin())
+			if err != nil {
+				return err
+			}
+			identity, err := kb.ExportPrivKey(identityKey, passwd)
+			if err != nil {
+				return err
+			}
+			allowed, err := parsePubKeys(viper.GetString(flagAllow))
+			if err != nil {
+				return err
+			}
+			if len(allowed) == 0 {
+				return fmt.Errorf("no client allowed, set their identities with --%s", flagAllow)
+			}
+
+			// the validator key only signs votes, proposals and
+			// heartbeats, so that nothing bypasses the double sign guard
+			signer := keybaseSigner{kb: kb, passwd: passwd, excluded: []string{identityKey}}
+			var privVal types.PrivValidator
+			if name := viper.GetString(flagValidatorKey); name != "" {
+				valKey, err := kb.ExportPrivKey(name, passwd)
+				if err != nil {
+					return err
+				}
+				stateFile := filepath.Join(viper.GetString(cli.HomeFlag), "sign_state.json")
+				guardedPV, err := remote.NewGuardedPV(valKey, stateFile)
+				if err != nil {
+					return err
+				}
+				logger.Info("Signing votes", "address", guardedPV.GetAddress(), "state", guardedPV.State())
+				privVal = guardedPV
+				signer.excluded = append(signer.excluded, name)
+			}
+
+			server := remote.NewServer(identity, signer, privVal, logger).
+				WithAllowedClients(allowed...)
+			return server.ListenAndServe(viper.GetString(flagLaddr))
+		},
+	}
+	cmd.Flags().String(flagLaddr, "tcp://127.0.0.1:46659", "Address to listen on, tcp://host:port or unix:///path")
+	cmd.Flags().String(flagValidatorKey, "", "Name of the key to sign votes with, none to only sign bytes")
+	cmd.Flags().String(flagAllow, "", "Comma separated hex identities of the clients allowed (required)")
+	return cmd
+}
+
+// parsePubKeys parses comma separated hex encoded public keys
+func parsePubKeys(s string) ([]tcrypto.PubKey, error) {
+	var pubKeys []tcrypto.PubKey
+	for _, h := range strings.Split(s, ",") {
+		h = strings.TrimSpace(h)
+		if h == "" {
+			continue
+		}
+		bz, err := hex.DecodeString(h)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client identity %s: %v", h, err)
+		}
+		pubKey, err := tcrypto.PubKeyFromBytes(bz)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client identity %s: %v", h, err)
+		}
+		pubKeys = append(pubKeys, pubKey)
+	}
+	return pubKeys, nil
+}
+
+// keybaseSigner signs with the keys of the keybase of the signer, they are
+// all encrypted with passwd, but the excluded ones
+type keybaseSigner struct {
+	kb       keys.Keybase
+	passwd   string
+	excluded []string
+}
+
+func (s keybaseSigner) PubKey(keyName string) (tcrypto.PubKey, error) {
+	info, err := s.kb.Get(keyName)
+	if err != nil {
+		return nil, err
+	}
+	return info.GetPubKey(), nil
+}
+
+func (s keybaseSigner) Sign(keyName string, msg []byte) (tcrypto.Signature, tcrypto.PubKey, error) {
+	for _, excluded := range s.excluded {
+		if keyName == excluded {
+			return nil, nil, fmt.Errorf("key %s doesn't sign bytes", keyName)
+		}
+	}
+	return s.kb.Sign(keyName, s.passwd, msg)
+}
@@ -16,4 +16,10 @@ func init() {
 func RegisterAmino(cdc *amino.Codec) {
 	cdc.RegisterConcrete(PrivKeyLedgerSecp256k1{},
 		"tepleton/PrivKeyLedgerSecp256k1", nil)
+	cdc.RegisterConcrete(PrivKeySecp256r1{},
+		"tepleton/PrivKeySecp256r1", nil)
+	cdc.RegisterConcrete(PubKeySecp256r1{},
+		"tepleton/PubKeySecp256r1", nil)
+	cdc.RegisterConcrete(SignatureSecp256r1{},
+		"tepleton/SignatureSecp256r1", nil)
 }
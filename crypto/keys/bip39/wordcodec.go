@@ -3,7 +3,8 @@ package bip39
 import (
 	"strings"
 
-	"github.com/bartekn/go-bip39"
+	"github.com/tyler-smith/go-bip39"
+	"golang.org/x/text/unicode/norm"
 )
 
 // ValidSentenceLen defines the mnemonic sentence lengths supported by this BIP 39 library.
@@ -21,8 +22,14 @@ const (
 )
 
 // NewMnemonic will return a string consisting of the mnemonic words for
-// the given sentence length.
+// the given sentence length, in english.
 func NewMnemonic(len ValidSentenceLen) (words []string, err error) {
+	return NewMnemonicInWordlist(len, English)
+}
+
+// NewMnemonicInWordlist returns the mnemonic words for the given sentence
+// length, taken from the given wordlist.
+func NewMnemonicInWordlist(len ValidSentenceLen, wordlist Wordlist) (words []string, err error) {
 	// len = (entropySize + checksum) / 11
 	var entropySize int
 	switch len {
@@ -38,29 +45,40 @@ func NewMnemonic(len ValidSentenceLen) (words []string, err error) {
 	if err != nil {
 		return
 	}
-	var mnemonic string
-	mnemonic, err = bip39.NewMnemonic(entropy)
-	if err != nil {
-		return
-	}
-	words = strings.Split(mnemonic, " ")
-	return
+	return wordlist.Mnemonic(entropy)
 }
 
 // MnemonicToSeed creates a BIP 39 seed from the passed mnemonic (with an empty BIP 39 password).
 // This method does not validate the mnemonics checksum.
 func MnemonicToSeed(mne string) (seed []byte) {
 	// we do not checksum here...
-	seed = bip39.NewSeed(mne, "")
-	return
+	return newSeed(mne, "")
 }
 
 // MnemonicToSeedWithErrChecking returns the same seed as MnemonicToSeed.
 // It creates a BIP 39 seed from the passed mnemonic (with an empty BIP 39 password).
 //
-// Different from MnemonicToSeed it validates the checksum.
+// Different from MnemonicToSeed it validates the checksum, in any of the
+// supported wordlists.
 // For details on the checksum see the BIP 39 spec.
 func MnemonicToSeedWithErrChecking(mne string) (seed []byte, err error) {
-	seed, err = bip39.NewSeedWithErrorChecking(mne, "")
-	return
+	return MnemonicToSeedWithPassphrase(mne, "")
+}
+
+// MnemonicToSeedWithPassphrase creates a BIP 39 seed from the passed mnemonic
+// and BIP 39 passphrase, the optional "25th word". It validates the checksum
+// of the mnemonic, in any of the supported wordlists.
+func MnemonicToSeedWithPassphrase(mne, passphrase string) (seed []byte, err error) {
+	_, err = DetectWordlist(mne)
+	if err != nil {
+		return
+	}
+	return newSeed(mne, passphrase), nil
+}
+
+// newSeed derives the seed from the NFKD normalized mnemonic and passphrase,
+// as required by the spec for the non-english wordlists. The words are
+// joined with single spaces, whatever white space separated them.
+func newSeed(mne, passphrase string) []byte {
+	return bip39.NewSeed(strings.Join(splitMnemonic(mne), " "), norm.NFKD.String(passphrase))
 }
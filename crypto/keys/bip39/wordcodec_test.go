@@ -1,9 +1,12 @@
 package bip39
 
 import (
+	"encoding/hex"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"golang.org/x/text/unicode/norm"
 )
 
 func TestWordCodec_NewMnemonic(t *testing.T) {
@@ -13,3 +16,68 @@ func TestWordCodec_NewMnemonic(t *testing.T) {
 	_, err = NewMnemonic(FreshKey)
 	require.NoError(t, err, "unexpected error generating new 24-word mnemonic")
 }
+
+func TestWordCodec_Wordlists(t *testing.T) {
+	for _, wl := range Wordlists {
+		words, err := NewMnemonicInWordlist(FreshKey, wl)
+		require.NoError(t, err, wl.Name)
+		require.Len(t, words, 24, wl.Name)
+
+		// recovery detects the language
+		detected, err := DetectWordlist(wl.Join(words))
+		require.NoError(t, err, wl.Name)
+		_, err = detected.Entropy(words)
+		require.NoError(t, err, wl.Name)
+	}
+
+	// a mnemonic mixing languages, or with a wrong checksum, is refused
+	english, err := NewMnemonic(FundRaiser)
+	require.NoError(t, err)
+	japanese, err := NewMnemonicInWordlist(FundRaiser, Japanese)
+	require.NoError(t, err)
+	_, err = DetectWordlist(English.Join(append(english[:11:11], japanese[11])))
+	require.Equal(t, ErrUnknownWords, err)
+	_, err = MnemonicToSeedWithErrChecking(strings.Repeat("abandon ", 11) + "abandon")
+	require.Equal(t, ErrInvalidChecksum, err)
+}
+
+func TestWordCodec_Vectors(t *testing.T) {
+	// vectors from the BIP 39 spec, and from the japanese test vectors it links to
+	cases := []struct {
+		wordlist   Wordlist
+		mnemonic   string
+		passphrase string
+		seed       string
+	}{
+		{
+			English,
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			"TREZOR",
+			"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		},
+		{
+			Japanese,
+			"あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あおぞら",
+			"㍍ガバヴァぱばぐゞちぢ十人十色",
+			"a262d6fb6122ecf45be09c50492b31f92e9beb7d9a845987a02cefda57a15f9c467a17872029a9e92299b5cbdf306e3a0ee620245cbd508959b6cb7ca637bd55",
+		},
+	}
+	for _, tc := range cases {
+		words, err := tc.wordlist.Mnemonic(make([]byte, 16))
+		require.NoError(t, err)
+		require.Equal(t, norm.NFKD.String(tc.mnemonic), norm.NFKD.String(tc.wordlist.Join(words)))
+
+		seed, err := MnemonicToSeedWithPassphrase(tc.mnemonic, tc.passphrase)
+		require.NoError(t, err)
+		require.Equal(t, tc.seed, hex.EncodeToString(seed))
+
+		// the passphrase changes the seed
+		require.NotEqual(t, seed, MnemonicToSeed(tc.mnemonic))
+
+		// the white space between the words doesn't
+		spaced := " " + strings.Join(strings.Fields(norm.NFKD.String(tc.mnemonic)), " \n\t ") + "\n"
+		seed, err = MnemonicToSeedWithPassphrase(spaced, tc.passphrase)
+		require.NoError(t, err)
+		require.Equal(t, tc.seed, hex.EncodeToString(seed))
+	}
+}
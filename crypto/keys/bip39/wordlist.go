@@ -0,0 +1,145 @@
+package bip39
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tyler-smith/go-bip39/wordlists"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	// ErrUnknownWords is returned for a mnemonic whose words are not all in
+	// one of the supported wordlists.
+	ErrUnknownWords = errors.New("mnemonic words are not all in one BIP 39 wordlist")
+	// ErrInvalidChecksum is returned for a mnemonic whose checksum doesn't match.
+	ErrInvalidChecksum = errors.New("invalid mnemonic checksum")
+)
+
+// Wordlist is one of the BIP 39 wordlists, 2048 words in one language.
+type Wordlist struct {
+	// Name is the name of the language, as in the BIP 39 spec.
+	Name      string
+	words     []string
+	index     map[string]int
+	separator string
+}
+
+func newWordlist(name string, words []string, separator string) Wordlist {
+	if len(words) != 2048 {
+		panic(fmt.Sprintf("the %s wordlist has %d words, expected 2048", name, len(words)))
+	}
+	index := make(map[string]int, len(words))
+	for i, word := range words {
+		index[norm.NFKD.String(word)] = i
+	}
+	return Wordlist{
+		Name:      name,
+		words:     words,
+		index:     index,
+		separator: separator,
+	}
+}
+
+// The supported wordlists.
+var (
+	English            = newWordlist("english", wordlists.English, " ")
+	Japanese           = newWordlist("japanese", wordlists.Japanese, "\u3000")
+	Korean             = newWordlist("korean", wordlists.Korean, " ")
+	Spanish            = newWordlist("spanish", wordlists.Spanish, " ")
+	ChineseSimplified  = newWordlist("chinese_simplified", wordlists.ChineseSimplified, " ")
+	ChineseTraditional = newWordlist("chinese_traditional", wordlists.ChineseTraditional, " ")
+	French             = newWordlist("french", wordlists.French, " ")
+	Italian            = newWordlist("italian", wordlists.Italian, " ")
+
+	// Wordlists holds all supported wordlists, in the order DetectWordlist
+	// tries them.
+	Wordlists = []Wordlist{English, Japanese, Korean, Spanish, ChineseSimplified, ChineseTraditional, French, Italian}
+)
+
+// Join writes words as a mnemonic sentence, separated the way the language
+// of the wordlist is written. Japanese uses an ideographic space.
+func (wl Wordlist) Join(words []string) string {
+	return strings.Join(words, wl.separator)
+}
+
+// Mnemonic encodes entropy of 128 to 256 bits, in steps of 32 bits, as the
+// words of a mnemonic sentence with checksum.
+func (wl Wordlist) Mnemonic(entropy []byte) ([]string, error) {
+	if len(entropy) < 16 || len(entropy) > 32 || len(entropy)%4 != 0 {
+		return nil, fmt.Errorf("entropy must be 128 to 256 bits in steps of 32, got %d bits", len(entropy)*8)
+	}
+	checksum := sha256.Sum256(entropy)
+	bits := append(append([]byte{}, entropy...), checksum[0])
+
+	words := make([]string, len(entropy)*3/4)
+	for i := range words {
+		index := 0
+		for j := 0; j < 11; j++ {
+			index = index<<1 | bitAt(bits, i*11+j)
+		}
+		words[i] = wl.words[index]
+	}
+	return words, nil
+}
+
+// Entropy decodes the words of a mnemonic sentence back to its entropy.
+// It returns ErrUnknownWords if a word isn't in the wordlist, and
+// ErrInvalidChecksum if the checksum doesn't match.
+func (wl Wordlist) Entropy(words []string) ([]byte, error) {
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return nil, fmt.Errorf("mnemonic must have 12 to 24 words in steps of 3, got %d words", len(words))
+	}
+	bits := make([]byte, (len(words)*11+7)/8)
+	for i, word := range words {
+		index, ok := wl.index[norm.NFKD.String(word)]
+		if !ok {
+			return nil, ErrUnknownWords
+		}
+		for j := 0; j < 11; j++ {
+			if index&(1<<uint(10-j)) != 0 {
+				pos := i*11 + j
+				bits[pos/8] |= 1 << uint(7-pos%8)
+			}
+		}
+	}
+
+	entropyLen := len(words) / 3 * 4
+	entropy := bits[:entropyLen]
+	checksum := sha256.Sum256(entropy)
+	for i := 0; i < len(words)/3; i++ {
+		if bitAt(bits, entropyLen*8+i) != bitAt(checksum[:], i) {
+			return nil, ErrInvalidChecksum
+		}
+	}
+	return append([]byte{}, entropy...), nil
+}
+
+// DetectWordlist returns the wordlist a mnemonic sentence is written in,
+// with a valid checksum. Wordlists sharing words, like the two Chinese
+// ones, are tried in the order of Wordlists.
+func DetectWordlist(mnemonic string) (Wordlist, error) {
+	words := splitMnemonic(mnemonic)
+	err := ErrUnknownWords
+	for _, wl := range Wordlists {
+		_, wlErr := wl.Entropy(words)
+		if wlErr == nil {
+			return wl, nil
+		}
+		if wlErr != ErrUnknownWords {
+			err = wlErr
+		}
+	}
+	return Wordlist{}, err
+}
+
+// splitMnemonic splits a mnemonic sentence in its words, at any white space
+func splitMnemonic(mnemonic string) []string {
+	return strings.Fields(norm.NFKD.String(mnemonic))
+}
+
+func bitAt(bz []byte, i int) int {
+	return int(bz[i/8]>>uint(7-i%8)) & 1
+}
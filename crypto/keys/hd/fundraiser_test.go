@@ -7,8 +7,8 @@ import (
 	"io/ioutil"
 	"testing"
 
-	"github.com/bartekn/go-bip39"
 	"github.com/stretchr/testify/require"
+	"github.com/tyler-smith/go-bip39"
 
 	"github.com/tepleton/tepleton/crypto"
 )
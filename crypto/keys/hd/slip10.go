@@ -0,0 +1,148 @@
+package hd
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Curve is the elliptic curve keys are derived for. Keys on curves other
+// than secp256k1 are derived as specified by SLIP-0010, see
+// https://github.com/satoshilabs/slips/blob/master/slip-0010.md
+type Curve string
+
+const (
+	// CurveSecp256k1 derives keys as BIP 32 does.
+	CurveSecp256k1 = Curve("secp256k1")
+	// CurveEd25519 derives ed25519 seeds, only hardened derivation is possible.
+	CurveEd25519 = Curve("ed25519")
+	// CurveSecp256r1 derives keys on the NIST P-256 curve.
+	CurveSecp256r1 = Curve("secp256r1")
+)
+
+// ComputeMastersFromSeedForCurve returns the master secret and chain code
+// of the seed for keys on curve.
+func ComputeMastersFromSeedForCurve(seed []byte, curve Curve) (secret [32]byte, chainCode [32]byte, err error) {
+	switch curve {
+	case CurveSecp256k1:
+		secret, chainCode = ComputeMastersFromSeed(seed)
+	case CurveEd25519:
+		secret, chainCode = i64([]byte("ed25519 seed"), seed)
+	case CurveSecp256r1:
+		key := []byte("Nist256p1 seed")
+		secret, chainCode = i64(key, seed)
+		// retry with the whole hash if it isn't a valid key
+		for !validP256Scalar(secret[:]) {
+			secret, chainCode = i64(key, append(secret[:], chainCode[:]...))
+		}
+	default:
+		err = fmt.Errorf("unsupported curve %q", curve)
+	}
+	return
+}
+
+// DerivePrivateKeyForPathOnCurve derives the private key for a key on curve
+// by following the BIP 32/44 path from privKeyBytes, using the given chainCode.
+// All indexes must be hardened for ed25519.
+func DerivePrivateKeyForPathOnCurve(privKeyBytes [32]byte, chainCode [32]byte, path string, curve Curve) ([32]byte, error) {
+	switch curve {
+	case CurveSecp256k1:
+		return DerivePrivateKeyForPath(privKeyBytes, chainCode, path)
+	case CurveEd25519, CurveSecp256r1:
+	default:
+		return [32]byte{}, fmt.Errorf("unsupported curve %q", curve)
+	}
+
+	data := privKeyBytes
+	for _, part := range strings.Split(path, "/") {
+		harden := strings.HasSuffix(part, "'")
+		idx, err := strconv.ParseUint(strings.TrimSuffix(part, "'"), 10, 31)
+		if err != nil {
+			return [32]byte{}, fmt.Errorf("invalid BIP 32 path: %s", err)
+		}
+		if curve == CurveEd25519 {
+			if !harden {
+				return [32]byte{}, errors.New("invalid BIP 32 path: ed25519 keys only support hardened derivation")
+			}
+			data, chainCode = deriveEd25519PrivateKey(data, chainCode, uint32(idx))
+		} else {
+			data, chainCode = deriveSecp256r1PrivateKey(data, chainCode, uint32(idx), harden)
+		}
+	}
+	return data, nil
+}
+
+// HardenPath returns the BIP 32 path with all indexes hardened, the path
+// ed25519 keys are derived on in place of path.
+func HardenPath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if !strings.HasSuffix(part, "'") {
+			parts[i] = part + "'"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// deriveEd25519PrivateKey derives the hardened child key with index.
+func deriveEd25519PrivateKey(privKeyBytes [32]byte, chainCode [32]byte, index uint32) ([32]byte, [32]byte) {
+	data := append([]byte{byte(0)}, privKeyBytes[:]...)
+	data = append(data, uint32ToBytes(index|0x80000000)...)
+	return i64(chainCode[:], data)
+}
+
+// deriveSecp256r1PrivateKey derives the child key with index, hardened or not.
+func deriveSecp256r1PrivateKey(privKeyBytes [32]byte, chainCode [32]byte, index uint32, harden bool) ([32]byte, [32]byte) {
+	curve := elliptic.P256()
+	var data []byte
+	if harden {
+		index = index | 0x80000000
+		data = append([]byte{byte(0)}, privKeyBytes[:]...)
+	} else {
+		x, y := curve.ScalarBaseMult(privKeyBytes[:])
+		data = marshalCompressed(x, y)
+	}
+	data = append(data, uint32ToBytes(index)...)
+	for {
+		il, ir := i64(chainCode[:], data)
+		if validP256Scalar(il[:]) {
+			key := new(big.Int).SetBytes(il[:])
+			key.Add(key, new(big.Int).SetBytes(privKeyBytes[:]))
+			key.Mod(key, curve.Params().N)
+			if key.Sign() != 0 {
+				var derived [32]byte
+				fillBytes(key, derived[:])
+				return derived, ir
+			}
+		}
+		// retry with the next hash if it isn't a valid key
+		data = append(append([]byte{byte(1)}, ir[:]...), uint32ToBytes(index)...)
+	}
+}
+
+// validP256Scalar is true if bz is a non-zero scalar below the P-256 order
+func validP256Scalar(bz []byte) bool {
+	k := new(big.Int).SetBytes(bz)
+	return k.Sign() != 0 && k.Cmp(elliptic.P256().Params().N) < 0
+}
+
+// marshalCompressed returns the compressed form of a P-256 point, its x
+// coordinate prefixed with 0x02 or 0x03 depending on the parity of y
+func marshalCompressed(x, y *big.Int) []byte {
+	data := make([]byte, 33)
+	data[0] = byte(2 + y.Bit(0))
+	fillBytes(x, data[1:])
+	return data
+}
+
+// fillBytes sets buf to the big endian bytes of n, zero padded
+func fillBytes(n *big.Int, buf []byte) {
+	for i := range buf {
+		buf[i] = 0
+	}
+	bz := n.Bytes()
+	copy(buf[len(buf)-len(bz):], bz)
+}
@@ -0,0 +1,56 @@
+package hd
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// test vector 1 of SLIP-0010
+func TestSLIP10Vectors(t *testing.T) {
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	require.NoError(t, err)
+
+	cases := []struct {
+		curve     Curve
+		master    string
+		chainCode string
+		path      string
+		derived   string
+	}{
+		{
+			CurveEd25519,
+			"2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7",
+			"90046a93de5380a72b5e45010748567d5ea02bbf6522f979e05c0d8d8ca9fffb",
+			"0'/1'/2'/2'/1000000000'",
+			"8f94d394a8e8fd6b1bc2f3f49f5c47e385281d5c17e65324b0f62483e37e8793",
+		},
+		{
+			CurveSecp256r1,
+			"612091aaa12e22dd2abef664f8a01a82cae99ad7441b7ef8110424915c268bc2",
+			"beeb672fe4621673f722f38529c07392fecaa61015c80c34f29ce8b41b3cb6ea",
+			"0'/1/2'/2/1000000000",
+			"21c4f269ef0a5fd1badf47eeacebeeaa3de22eb8e5b0adcd0f27dd99d34d0119",
+		},
+	}
+	for _, tc := range cases {
+		master, chainCode, err := ComputeMastersFromSeedForCurve(seed, tc.curve)
+		require.NoError(t, err)
+		require.Equal(t, tc.master, hex.EncodeToString(master[:]))
+		require.Equal(t, tc.chainCode, hex.EncodeToString(chainCode[:]))
+
+		derived, err := DerivePrivateKeyForPathOnCurve(master, chainCode, tc.path, tc.curve)
+		require.NoError(t, err)
+		require.Equal(t, tc.derived, hex.EncodeToString(derived[:]))
+	}
+
+	// ed25519 has no public derivation
+	master, chainCode, err := ComputeMastersFromSeedForCurve(seed, CurveEd25519)
+	require.NoError(t, err)
+	_, err = DerivePrivateKeyForPathOnCurve(master, chainCode, FullFundraiserPath, CurveEd25519)
+	require.Error(t, err)
+
+	_, _, err = ComputeMastersFromSeedForCurve(seed, Curve("secp384r1"))
+	require.Error(t, err)
+}
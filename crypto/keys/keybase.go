@@ -7,91 +7,122 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/tepleton/ed25519"
 	tcrypto "github.com/tepleton/tepleton/crypto"
 	dbm "github.com/tepleton/tmlibs/db"
 
 	"github.com/tepleton/tepleton-sdk/crypto"
 	"github.com/tepleton/tepleton-sdk/crypto/keys/bip39"
 	"github.com/tepleton/tepleton-sdk/crypto/keys/hd"
+	"github.com/tepleton/tepleton-sdk/crypto/keys/remote"
 )
 
 var _ Keybase = dbKeybase{}
 
 // Language is a language to create the BIP 39 mnemonic in.
-// Currently, only english is supported though.
 // Find a list of all supported languages in the BIP 39 spec (word lists).
 type Language int
 
 const (
 	// English is the default language to create a mnemonic.
-	// It is the only supported language by this package.
 	English Language = iota + 1
-	// Japanese is currently not supported.
+	// Japanese mnemonics are separated by ideographic spaces.
 	Japanese
-	// Korean is currently not supported.
+	// Korean wordlist.
 	Korean
-	// Spanish is currently not supported.
+	// Spanish wordlist.
 	Spanish
-	// ChineseSimplified is currently not supported.
+	// ChineseSimplified wordlist.
 	ChineseSimplified
-	// ChineseTraditional is currently not supported.
+	// ChineseTraditional wordlist.
 	ChineseTraditional
-	// French is currently not supported.
+	// French wordlist.
 	French
-	// Italian is currently not supported.
+	// Italian wordlist.
 	Italian
 )
 
+// wordlists maps the languages to their BIP 39 wordlists
+var wordlists = map[Language]bip39.Wordlist{
+	English:            bip39.English,
+	Japanese:           bip39.Japanese,
+	Korean:             bip39.Korean,
+	Spanish:            bip39.Spanish,
+	ChineseSimplified:  bip39.ChineseSimplified,
+	ChineseTraditional: bip39.ChineseTraditional,
+	French:             bip39.French,
+	Italian:            bip39.Italian,
+}
+
 var (
-	// ErrUnsupportedSigningAlgo is raised when the caller tries to use a different signing scheme than secp256k1.
-	ErrUnsupportedSigningAlgo = errors.New("unsupported signing algo: only secp256k1 is supported")
-	// ErrUnsupportedLanguage is raised when the caller tries to use a different language than english for creating
+	// ErrUnsupportedSigningAlgo is raised when the caller tries to use a signing scheme the keybase, or the
+	// Ledger, doesn't support.
+	ErrUnsupportedSigningAlgo = errors.New("unsupported signing algo")
+	// ErrUnsupportedLanguage is raised when the caller tries to use a language without BIP 39 wordlist for creating
 	// a mnemonic sentence.
-	ErrUnsupportedLanguage = errors.New("unsupported language: only english is supported")
+	ErrUnsupportedLanguage = errors.New("unsupported language: no BIP 39 wordlist for it")
 )
 
 // dbKeybase combines encryption and storage implementation to provide
 // a full-featured key manager
 type dbKeybase struct {
-	db dbm.DB
+	storage Storage
 }
 
 // New creates a new keybase instance using the passed DB for reading and writing keys.
 func New(db dbm.DB) Keybase {
+	return NewWithStorage(NewDBStorage(db))
+}
+
+// NewWithStorage creates a new keybase instance reading and writing keys
+// to the passed Storage.
+func NewWithStorage(storage Storage) Keybase {
 	return dbKeybase{
-		db: db,
+		storage: storage,
 	}
 }
 
+// NewInMemory creates a new keybase that only holds keys in memory,
+// useful for tests.
+func NewInMemory() Keybase {
+	return NewWithStorage(NewMemStorage())
+}
+
 // CreateMnemonic generates a new key and persists it to storage, encrypted
 // using the provided password.
+// The key is derived from the mnemonic together with bip39Passphrase, the
+// optional BIP 39 passphrase, which must be given again to recover it.
 // It returns the generated mnemonic and the key Info.
 // It returns an error if it fails to
 // generate a key for the given algo type, or if another key is
 // already stored under the same name.
-func (kb dbKeybase) CreateMnemonic(name string, language Language, passwd string, algo SigningAlgo) (info Info, mnemonic string, err error) {
-	if language != English {
+func (kb dbKeybase) CreateMnemonic(name string, language Language, passwd, bip39Passphrase string, algo SigningAlgo) (info Info, mnemonic string, err error) {
+	wordlist, ok := wordlists[language]
+	if !ok {
 		return nil, "", ErrUnsupportedLanguage
 	}
-	if algo != Secp256k1 {
+	if _, ok := curves[algo]; !ok {
 		err = ErrUnsupportedSigningAlgo
 		return
 	}
 
 	// default number of words (24):
-	mnemonicS, err := bip39.NewMnemonic(bip39.FreshKey)
+	mnemonicS, err := bip39.NewMnemonicInWordlist(bip39.FreshKey, wordlist)
+	if err != nil {
+		return
+	}
+	mnemonic = wordlist.Join(mnemonicS)
+	seed, err := bip39.MnemonicToSeedWithPassphrase(mnemonic, bip39Passphrase)
 	if err != nil {
 		return
 	}
-	mnemonic = strings.Join(mnemonicS, " ")
-	seed := bip39.MnemonicToSeed(mnemonic)
-	info, err = kb.persistDerivedKey(seed, passwd, name, hd.FullFundraiserPath)
+	info, err = kb.persistDerivedKey(seed, passwd, name, hd.FullFundraiserPath, algo)
 	return
 }
 
 // TEMPORARY METHOD UNTIL WE FIGURE OUT USER FACING HD DERIVATION API
 func (kb dbKeybase) CreateKey(name, mnemonic, passwd string) (info Info, err error) {
-	words := strings.Split(mnemonic, " ")
+	words := strings.Fields(mnemonic)
 	if len(words) != 12 && len(words) != 24 {
 		err = fmt.Errorf("recovering only works with 12 word (fundraiser) or 24 word mnemonics, got: %v words", len(words))
 		return
@@ -100,7 +131,7 @@ func (kb dbKeybase) CreateKey(name, mnemonic, passwd string) (info Info, err err
 	if err != nil {
 		return
 	}
-	info, err = kb.persistDerivedKey(seed, passwd, name, hd.FullFundraiserPath)
+	info, err = kb.persistDerivedKey(seed, passwd, name, hd.FullFundraiserPath, Secp256k1)
 	return
 }
 
@@ -108,7 +139,7 @@ func (kb dbKeybase) CreateKey(name, mnemonic, passwd string) (info Info, err err
 // encrypted with the given password.
 // TODO(ismail)
 func (kb dbKeybase) CreateFundraiserKey(name, mnemonic, passwd string) (info Info, err error) {
-	words := strings.Split(mnemonic, " ")
+	words := strings.Fields(mnemonic)
 	if len(words) != 12 {
 		err = fmt.Errorf("recovering only works with 12 word (fundraiser), got: %v words", len(words))
 		return
@@ -117,16 +148,22 @@ func (kb dbKeybase) CreateFundraiserKey(name, mnemonic, passwd string) (info Inf
 	if err != nil {
 		return
 	}
-	info, err = kb.persistDerivedKey(seed, passwd, name, hd.FullFundraiserPath)
+	info, err = kb.persistDerivedKey(seed, passwd, name, hd.FullFundraiserPath, Secp256k1)
 	return
 }
 
-func (kb dbKeybase) Derive(name, mnemonic, passwd string, params hd.BIP44Params) (info Info, err error) {
-	seed, err := bip39.MnemonicToSeedWithErrChecking(mnemonic)
+// Derive derives the key for algo at the BIP 44 path from the mnemonic, in
+// any of the supported languages, and the optional BIP 39 passphrase.
+func (kb dbKeybase) Derive(name, mnemonic, passwd, bip39Passphrase string, params hd.BIP44Params, algo SigningAlgo) (info Info, err error) {
+	if _, ok := curves[algo]; !ok {
+		err = ErrUnsupportedSigningAlgo
+		return
+	}
+	seed, err := bip39.MnemonicToSeedWithPassphrase(mnemonic, bip39Passphrase)
 	if err != nil {
 		return
 	}
-	info, err = kb.persistDerivedKey(seed, passwd, name, params.String())
+	info, err = kb.persistDerivedKey(seed, passwd, name, params.String(), algo)
 
 	return
 }
@@ -151,32 +188,84 @@ func (kb dbKeybase) CreateOffline(name string, pub tcrypto.PubKey) (Info, error)
 	return kb.writeOfflineKey(pub, name), nil
 }
 
-func (kb *dbKeybase) persistDerivedKey(seed []byte, passwd, name, fullHdPath string) (info Info, err error) {
+// CreateRemote creates a reference to the key keyName of the remote signer
+// at addr, tcp://host:port or unix:///path. It connects to the signer to
+// fetch the public key. identity is the ed25519 key the keybase
+// authenticates to the signer with, it's stored encrypted with passwd. If
+// signerPubKey isn't nil, the signer must authenticate with it.
+func (kb dbKeybase) CreateRemote(name, addr, keyName string, signerPubKey tcrypto.PubKey, identity tcrypto.PrivKey, passwd string) (Info, error) {
+	client, err := remote.Dial(addr, identity, signerPubKey)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	pub, err := client.PubKey(keyName)
+	if err != nil {
+		return nil, err
+	}
+	identityArmor := encryptArmorPrivKey(identity, passwd, "", nil)
+	info := newRemoteInfo(name, pub, addr, keyName, signerPubKey, identityArmor)
+	kb.writeInfo(info, name)
+	return info, nil
+}
+
+// curves maps the signing algos keys can be derived for to their curves
+var curves = map[SigningAlgo]hd.Curve{
+	Secp256k1: hd.CurveSecp256k1,
+	Ed25519:   hd.CurveEd25519,
+	Secp256r1: hd.CurveSecp256r1,
+}
+
+func (kb *dbKeybase) persistDerivedKey(seed []byte, passwd, name, fullHdPath string, algo SigningAlgo) (info Info, err error) {
+	curve := curves[algo]
+	if curve == hd.CurveEd25519 {
+		// ed25519 keys can only be derived on hardened paths
+		fullHdPath = hd.HardenPath(fullHdPath)
+	}
+
 	// create master key and derive first key:
-	masterPriv, ch := hd.ComputeMastersFromSeed(seed)
-	derivedPriv, err := hd.DerivePrivateKeyForPath(masterPriv, ch, fullHdPath)
+	masterPriv, ch, err := hd.ComputeMastersFromSeedForCurve(seed, curve)
+	if err != nil {
+		return
+	}
+	derivedPriv, err := hd.DerivePrivateKeyForPathOnCurve(masterPriv, ch, fullHdPath, curve)
 	if err != nil {
 		return
 	}
+	priv := newPrivKey(algo, derivedPriv)
 
 	// if we have a password, use it to encrypt the private key and store it
 	// else store the public key only
 	if passwd != "" {
-		info = kb.writeLocalKey(tcrypto.PrivKeySecp256k1(derivedPriv), name, passwd)
+		info = kb.writeLocalKey(priv, name, passwd, fullHdPath, nil)
 	} else {
-		pubk := tcrypto.PrivKeySecp256k1(derivedPriv).PubKey()
-		info = kb.writeOfflineKey(pubk, name)
+		info = kb.writeOfflineKey(priv.PubKey(), name)
 	}
 	return
 }
 
+// newPrivKey makes the private key for algo from the derived secret
+func newPrivKey(algo SigningAlgo, derivedPriv [32]byte) tcrypto.PrivKey {
+	switch algo {
+	case Ed25519:
+		// the derived secret is the seed of the key, the public key fills
+		// the second half
+		var priv tcrypto.PrivKeyEd25519
+		copy(priv[:32], derivedPriv[:])
+		ed25519.MakePublicKey((*[64]byte)(&priv))
+		return priv
+	case Secp256r1:
+		return crypto.PrivKeySecp256r1(derivedPriv)
+	default:
+		return tcrypto.PrivKeySecp256k1(derivedPriv)
+	}
+}
+
 // List returns the keys from storage in alphabetical order.
 func (kb dbKeybase) List() ([]Info, error) {
 	var res []Info
-	iter := kb.db.Iterator(nil, nil)
-	defer iter.Close()
-	for ; iter.Valid(); iter.Next() {
-		info, err := readInfo(iter.Value())
+	for _, name := range kb.storage.Names() {
+		info, err := readInfo(kb.storage.Get(name))
 		if err != nil {
 			return nil, err
 		}
@@ -187,7 +276,7 @@ func (kb dbKeybase) List() ([]Info, error) {
 
 // Get returns the public information about one key.
 func (kb dbKeybase) Get(name string) (Info, error) {
-	bs := kb.db.Get(infoKey(name))
+	bs := kb.storage.Get(name)
 	return readInfo(bs)
 }
 
@@ -228,6 +317,8 @@ func (kb dbKeybase) Sign(name, passphrase string, msg []byte) (sig tcrypto.Signa
 		}
 		cdc.MustUnmarshalBinary([]byte(signed), sig)
 		return sig, linfo.GetPubKey(), nil
+	case remoteInfo:
+		return signRemote(info.(remoteInfo), passphrase, msg)
 	}
 	sig, err = priv.Sign(msg)
 	if err != nil {
@@ -237,8 +328,30 @@ func (kb dbKeybase) Sign(name, passphrase string, msg []byte) (sig tcrypto.Signa
 	return sig, pub, nil
 }
 
+// signRemote signs msg with the key of the remote signer referenced by info,
+// authenticating with the identity decrypted with passphrase
+func signRemote(info remoteInfo, passphrase string, msg []byte) (tcrypto.Signature, tcrypto.PubKey, error) {
+	identity, err := unarmorDecryptPrivKey(info.IdentityArmor, passphrase)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := remote.Dial(info.Addr, identity, info.SignerPubKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer client.Close()
+	sig, pub, err := client.Sign(info.KeyName, msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !pub.Equals(info.PubKey) || !pub.VerifyBytes(msg, sig) {
+		return nil, nil, fmt.Errorf("remote signer returned a signature by another key than %s", info.Name)
+	}
+	return sig, pub, nil
+}
+
 func (kb dbKeybase) Export(name string) (armor string, err error) {
-	bz := kb.db.Get(infoKey(name))
+	bz := kb.storage.Get(name)
 	if bz == nil {
 		return "", fmt.Errorf("no key to export with name %s", name)
 	}
@@ -249,7 +362,7 @@ func (kb dbKeybase) Export(name string) (armor string, err error) {
 // Retrieve a Info object by its name and return the public key in
 // a portable format.
 func (kb dbKeybase) ExportPubKey(name string) (armor string, err error) {
-	bz := kb.db.Get(infoKey(name))
+	bz := kb.storage.Get(name)
 	if bz == nil {
 		return "", fmt.Errorf("no key to export with name %s", name)
 	}
@@ -261,7 +374,7 @@ func (kb dbKeybase) ExportPubKey(name string) (armor string, err error) {
 }
 
 func (kb dbKeybase) Import(name string, armor string) (err error) {
-	bz := kb.db.Get(infoKey(name))
+	bz := kb.storage.Get(name)
 	if len(bz) > 0 {
 		return errors.New("Cannot overwrite data for name " + name)
 	}
@@ -269,7 +382,7 @@ func (kb dbKeybase) Import(name string, armor string) (err error) {
 	if err != nil {
 		return
 	}
-	kb.db.Set(infoKey(name), infoBytes)
+	kb.storage.Set(name, infoBytes)
 	return nil
 }
 
@@ -277,7 +390,7 @@ func (kb dbKeybase) Import(name string, armor string) (err error) {
 // Store a new Info object holding a public key only, i.e. it will
 // not be possible to sign with it as it lacks the secret key.
 func (kb dbKeybase) ImportPubKey(name string, armor string) (err error) {
-	bz := kb.db.Get(infoKey(name))
+	bz := kb.storage.Get(name)
 	if len(bz) > 0 {
 		return errors.New("Cannot overwrite data for name " + name)
 	}
@@ -285,7 +398,8 @@ func (kb dbKeybase) ImportPubKey(name string, armor string) (err error) {
 	if err != nil {
 		return
 	}
-	pubKey, err := tcrypto.PubKeyFromBytes(pubBytes)
+	var pubKey tcrypto.PubKey
+	err = cdc.UnmarshalBinaryBare(pubBytes, &pubKey)
 	if err != nil {
 		return
 	}
@@ -293,10 +407,72 @@ func (kb dbKeybase) ImportPubKey(name string, armor string) (err error) {
 	return
 }
 
+// ImportPrivKey stores priv, encrypted with passphrase, under name.
+func (kb dbKeybase) ImportPrivKey(name string, priv tcrypto.PrivKey, passphrase string) (Info, error) {
+	bz := kb.storage.Get(name)
+	if len(bz) > 0 {
+		return nil, errors.New("Cannot overwrite data for name " + name)
+	}
+	return kb.writeLocalKey(priv, name, passphrase, "", nil), nil
+}
+
+// ExportPrivKey returns the decrypted private key of a locally stored key.
+func (kb dbKeybase) ExportPrivKey(name, passphrase string) (tcrypto.PrivKey, error) {
+	info, err := kb.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	linfo, ok := info.(localInfo)
+	if !ok {
+		return nil, fmt.Errorf("locally stored key required")
+	}
+	return unarmorDecryptPrivKey(linfo.PrivKeyArmor, passphrase)
+}
+
+// ExportPrivKeyArmor returns the private key of a locally stored key,
+// decrypted with decryptPassphrase and encrypted again with
+// encryptPassphrase, in ASCII armored format. The armor carries the type,
+// the derivation path and the metadata of the key.
+func (kb dbKeybase) ExportPrivKeyArmor(name, decryptPassphrase, encryptPassphrase string) (armor string, err error) {
+	info, err := kb.Get(name)
+	if err != nil {
+		return "", err
+	}
+	linfo, ok := info.(localInfo)
+	if !ok {
+		return "", fmt.Errorf("locally stored key required")
+	}
+	priv, path, err := decryptArmorPrivKey(linfo.PrivKeyArmor, decryptPassphrase)
+	if err != nil {
+		return "", err
+	}
+	return encryptArmorPrivKey(priv, encryptPassphrase, path, linfo.Metadata), nil
+}
+
+// ImportPrivKeyArmor imports an ASCII armored private key encrypted with
+// passphrase, in the current or in the legacy format, and stores it
+// encrypted with passphrase in the current format.
+func (kb dbKeybase) ImportPrivKeyArmor(name, armor, passphrase string) error {
+	bz := kb.storage.Get(name)
+	if len(bz) > 0 {
+		return errors.New("Cannot overwrite data for name " + name)
+	}
+	priv, path, err := decryptArmorPrivKey(armor, passphrase)
+	if err != nil {
+		return err
+	}
+	metadata, err := armorMetadata(armor)
+	if err != nil {
+		return err
+	}
+	kb.writeLocalKey(priv, name, passphrase, path, metadata)
+	return nil
+}
+
 // Delete removes key forever, but we must present the
 // proper passphrase before deleting it (for security).
 // A passphrase of 'yes' is used to delete stored
-// references to offline and Ledger / HW wallet keys
+// references to offline, remote and Ledger / HW wallet keys
 func (kb dbKeybase) Delete(name, passphrase string) error {
 	// verify we have the proper password before deleting
 	info, err := kb.Get(name)
@@ -310,21 +486,22 @@ func (kb dbKeybase) Delete(name, passphrase string) error {
 		if err != nil {
 			return err
 		}
-		kb.db.DeleteSync(infoKey(name))
+		kb.storage.Delete(name)
 		return nil
 	case ledgerInfo:
-	case offlineInfo:
+	case offlineInfo, remoteInfo:
 		if passphrase != "yes" {
 			return fmt.Errorf("enter 'yes' exactly to delete the key - this cannot be undone")
 		}
-		kb.db.DeleteSync(infoKey(name))
+		kb.storage.Delete(name)
 		return nil
 	}
 	return nil
 }
 
 // Update changes the passphrase with which an already stored key is
-// encrypted.
+// encrypted. The key is encrypted again in the current format, so updating
+// a key with the same passphrase upgrades a key in the legacy format.
 //
 // oldpass must be the current passphrase used for encryption,
 // newpass will be the only valid passphrase from this time forward.
@@ -336,23 +513,52 @@ func (kb dbKeybase) Update(name, oldpass, newpass string) error {
 	switch info.(type) {
 	case localInfo:
 		linfo := info.(localInfo)
-		key, err := unarmorDecryptPrivKey(linfo.PrivKeyArmor, oldpass)
+		key, path, err := decryptArmorPrivKey(linfo.PrivKeyArmor, oldpass)
 		if err != nil {
 			return err
 		}
-		kb.writeLocalKey(key, name, newpass)
+		kb.writeLocalKey(key, name, newpass, path, linfo.Metadata)
 		return nil
 	default:
 		return fmt.Errorf("locally stored key required")
 	}
 }
 
-func (kb dbKeybase) writeLocalKey(priv tcrypto.PrivKey, name, passphrase string) Info {
+// SetMetadata replaces the metadata of a stored key. Fields with an empty
+// value are dropped. The metadata is part of the info of the key, so it is
+// deleted with the key and moves with it on export and import.
+func (kb dbKeybase) SetMetadata(name string, metadata map[string]string) (Info, error) {
+	info, err := kb.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	fields := metadataFields(metadata)
+	switch i := info.(type) {
+	case localInfo:
+		i.Metadata = fields
+		info = i
+	case ledgerInfo:
+		i.Metadata = fields
+		info = i
+	case offlineInfo:
+		i.Metadata = fields
+		info = i
+	case remoteInfo:
+		i.Metadata = fields
+		info = i
+	default:
+		return nil, fmt.Errorf("unknown key type %T", info)
+	}
+	kb.writeInfo(info, name)
+	return info, nil
+}
+
+func (kb dbKeybase) writeLocalKey(priv tcrypto.PrivKey, name, passphrase, path string, metadata []MetadataField) Info {
 	// encrypt private key using passphrase
-	privArmor := encryptArmorPrivKey(priv, passphrase)
+	privArmor := encryptArmorPrivKey(priv, passphrase, path, nil)
 	// make Info
 	pub := priv.PubKey()
-	info := newLocalInfo(name, pub, privArmor)
+	info := newLocalInfo(name, pub, privArmor, metadata)
 	kb.writeInfo(info, name)
 	return info
 }
@@ -371,9 +577,12 @@ func (kb dbKeybase) writeOfflineKey(pub tcrypto.PubKey, name string) Info {
 
 func (kb dbKeybase) writeInfo(info Info, name string) {
 	// write the info by key
-	kb.db.SetSync(infoKey(name), writeInfo(info))
+	kb.storage.Set(name, writeInfo(info))
 }
 
+// suffix of the database keys the infos are stored under
+const infoSuffix = ".info"
+
 func infoKey(name string) []byte {
-	return []byte(fmt.Sprintf("%s.info", name))
+	return []byte(name + infoSuffix)
 }
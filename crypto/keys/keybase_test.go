@@ -2,6 +2,9 @@ package keys
 
 import (
 	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
 	"testing"
 
 	"github.com/tepleton/tepleton-sdk/crypto/keys/hd"
@@ -10,6 +13,9 @@ import (
 	"github.com/tepleton/tepleton/crypto"
 
 	dbm "github.com/tepleton/tmlibs/db"
+	"github.com/tepleton/tmlibs/log"
+
+	"github.com/tepleton/tepleton-sdk/crypto/keys/remote"
 )
 
 // TestKeyManagement makes sure we can manipulate these keys well
@@ -28,17 +34,17 @@ func TestKeyManagement(t *testing.T) {
 	require.Nil(t, err)
 	assert.Empty(t, l)
 
-	_, _, err = cstore.CreateMnemonic(n1, English, p1, Ed25519)
-	require.Error(t, err, "ed25519 keys are currently not supported by keybase")
+	_, _, err = cstore.CreateMnemonic(n1, English, p1, "", SigningAlgo("sr25519"))
+	require.Equal(t, ErrUnsupportedSigningAlgo, err)
 
 	// create some keys
 	_, err = cstore.Get(n1)
 	require.Error(t, err)
-	i, _, err := cstore.CreateMnemonic(n1, English, p1, algo)
+	i, _, err := cstore.CreateMnemonic(n1, English, p1, "", algo)
 
 	require.NoError(t, err)
 	require.Equal(t, n1, i.GetName())
-	_, _, err = cstore.CreateMnemonic(n2, English, p2, algo)
+	_, _, err = cstore.CreateMnemonic(n2, English, p2, "", algo)
 	require.NoError(t, err)
 
 	// we can get these keys
@@ -101,10 +107,10 @@ func TestSignVerify(t *testing.T) {
 	p1, p2, p3 := "1234", "foobar", "foobar"
 
 	// create two users and get their info
-	i1, _, err := cstore.CreateMnemonic(n1, English, p1, algo)
+	i1, _, err := cstore.CreateMnemonic(n1, English, p1, "", algo)
 	require.Nil(t, err)
 
-	i2, _, err := cstore.CreateMnemonic(n2, English, p2, algo)
+	i2, _, err := cstore.CreateMnemonic(n2, English, p2, "", algo)
 	require.Nil(t, err)
 
 	// Import a public key
@@ -182,7 +188,7 @@ func TestExportImport(t *testing.T) {
 		db,
 	)
 
-	info, _, err := cstore.CreateMnemonic("john", English, "secretcpw", Secp256k1)
+	info, _, err := cstore.CreateMnemonic("john", English, "secretcpw", "", Secp256k1)
 	require.NoError(t, err)
 	require.Equal(t, info.GetName(), "john")
 
@@ -215,7 +221,7 @@ func TestExportImportPubKey(t *testing.T) {
 
 	// CreateMnemonic a private-public key pair and ensure consistency
 	notPasswd := "n9y25ah7"
-	info, _, err := cstore.CreateMnemonic("john", English, notPasswd, Secp256k1)
+	info, _, err := cstore.CreateMnemonic("john", English, notPasswd, "", Secp256k1)
 	require.Nil(t, err)
 	require.NotEqual(t, info, "")
 	require.Equal(t, info.GetName(), "john")
@@ -260,7 +266,7 @@ func TestAdvancedKeyManagement(t *testing.T) {
 	p1, p2 := "1234", "foobar"
 
 	// make sure key works with initial password
-	_, _, err := cstore.CreateMnemonic(n1, English, p1, algo)
+	_, _, err := cstore.CreateMnemonic(n1, English, p1, "", algo)
 	require.Nil(t, err, "%+v", err)
 	assertPassword(t, cstore, n1, p1, p2)
 
@@ -309,7 +315,7 @@ func TestSeedPhrase(t *testing.T) {
 	p1, p2 := "1234", "foobar"
 
 	// make sure key works with initial password
-	info, mnemonic, err := cstore.CreateMnemonic(n1, English, p1, algo)
+	info, mnemonic, err := cstore.CreateMnemonic(n1, English, p1, "", algo)
 	require.Nil(t, err, "%+v", err)
 	require.Equal(t, n1, info.GetName())
 	assert.NotEmpty(t, mnemonic)
@@ -322,13 +328,277 @@ func TestSeedPhrase(t *testing.T) {
 
 	// let us re-create it from the mnemonic-phrase
 	params := *hd.NewFundraiserParams(0, 0)
-	newInfo, err := cstore.Derive(n2, mnemonic, p2, params)
+	newInfo, err := cstore.Derive(n2, mnemonic, p2, "", params, algo)
 	require.NoError(t, err)
 	require.Equal(t, n2, newInfo.GetName())
 	require.Equal(t, info.GetPubKey().Address(), newInfo.GetPubKey().Address())
 	require.Equal(t, info.GetPubKey(), newInfo.GetPubKey())
 }
 
+// TestSeedPhraseLanguages verifies restoring from mnemonics in every
+// language, with a BIP 39 passphrase
+func TestSeedPhraseLanguages(t *testing.T) {
+	cstore := NewInMemory()
+	params := *hd.NewFundraiserParams(0, 0)
+
+	for language := English; language <= Italian; language++ {
+		name := fmt.Sprintf("key-%d", language)
+		info, mnemonic, err := cstore.CreateMnemonic(name, language, "1234", "25th word", Secp256k1)
+		require.NoError(t, err, "%d", language)
+
+		// the passphrase is needed to recover the same key
+		recovered, err := cstore.Derive(name+"-recovered", mnemonic, "1234", "25th word", params, Secp256k1)
+		require.NoError(t, err, "%d", language)
+		require.Equal(t, info.GetPubKey(), recovered.GetPubKey())
+		other, err := cstore.Derive(name+"-other", mnemonic, "1234", "", params, Secp256k1)
+		require.NoError(t, err, "%d", language)
+		require.NotEqual(t, info.GetPubKey(), other.GetPubKey())
+	}
+
+	_, _, err := cstore.CreateMnemonic("unknown", Language(0), "1234", "", Secp256k1)
+	require.Equal(t, ErrUnsupportedLanguage, err)
+}
+
+// TestSigningAlgos creates, signs with, exports and imports keys of
+// every signing algo
+func TestSigningAlgos(t *testing.T) {
+	cstore := NewInMemory()
+	params := *hd.NewFundraiserParams(0, 0)
+	msg := []byte("sign me")
+
+	for _, algo := range []SigningAlgo{Secp256k1, Ed25519, Secp256r1} {
+		name := string(algo)
+		info, mnemonic, err := cstore.CreateMnemonic(name, English, "1234", "", algo)
+		require.NoError(t, err, name)
+
+		sig, pub, err := cstore.Sign(name, "1234", msg)
+		require.NoError(t, err, name)
+		require.Equal(t, info.GetPubKey(), pub)
+		require.True(t, pub.VerifyBytes(msg, sig), name)
+
+		// the same key is derived again, a key for another algo differs
+		derived, err := cstore.Derive(name+"-derived", mnemonic, "1234", "", params, algo)
+		require.NoError(t, err, name)
+		require.Equal(t, info.GetPubKey(), derived.GetPubKey())
+		for _, other := range []SigningAlgo{Secp256k1, Ed25519, Secp256r1} {
+			if other == algo {
+				continue
+			}
+			otherInfo, err := cstore.Derive(name+"-"+string(other), mnemonic, "1234", "", params, other)
+			require.NoError(t, err, name)
+			require.NotEqual(t, info.GetPubKey().Address(), otherInfo.GetPubKey().Address())
+		}
+
+		// export and import the key, it still signs
+		armor, err := cstore.Export(name)
+		require.NoError(t, err, name)
+		err = cstore.Import(name+"-imported", armor)
+		require.NoError(t, err, name)
+		sig, pub, err = cstore.Sign(name+"-imported", "1234", msg)
+		require.NoError(t, err, name)
+		require.Equal(t, info.GetPubKey(), pub)
+		require.True(t, pub.VerifyBytes(msg, sig), name)
+
+		// and the public key alone
+		armor, err = cstore.ExportPubKey(name)
+		require.NoError(t, err, name)
+		err = cstore.ImportPubKey(name+"-pub", armor)
+		require.NoError(t, err, name)
+		pubInfo, err := cstore.Get(name + "-pub")
+		require.NoError(t, err, name)
+		require.Equal(t, info.GetPubKey(), pubInfo.GetPubKey())
+	}
+
+	_, err := cstore.CreateLedger("ledger", nil, Ed25519)
+	require.Equal(t, ErrUnsupportedSigningAlgo, err)
+}
+
+// TestFileStorage makes sure keys survive in a file keyring and can be
+// migrated from a database
+func TestFileStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keyring")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	db := dbm.NewMemDB()
+	cstore := New(db)
+	_, _, err = cstore.CreateMnemonic("b", English, "1234", "", Secp256k1)
+	require.NoError(t, err)
+	_, _, err = cstore.CreateMnemonic("a", English, "1234", "", Secp256k1)
+	require.NoError(t, err)
+
+	// migrate into the file keyring
+	storage := NewFileStorage(dir)
+	migrated, err := Migrate(NewDBStorage(db), storage)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, migrated)
+	migrated, err = Migrate(NewDBStorage(db), storage)
+	require.NoError(t, err)
+	assert.Empty(t, migrated)
+
+	// a fresh keybase on the same directory sees the keys
+	fstore := NewWithStorage(NewFileStorage(dir))
+	keyS, err := fstore.List()
+	require.NoError(t, err)
+	require.Equal(t, 2, len(keyS))
+	require.Equal(t, "a", keyS[0].GetName())
+	_, _, err = fstore.Sign("b", "1234", []byte("msg"))
+	require.NoError(t, err)
+	_, _, err = fstore.Sign("b", "wrong", []byte("msg"))
+	require.Error(t, err)
+}
+
+// TestTestKeybase makes sure the test keybase never needs a passphrase
+func TestTestKeybase(t *testing.T) {
+	cstore := NewTestKeybase(NewMemStorage())
+
+	info, _, err := cstore.CreateMnemonic("ci", English, "", "", Secp256k1)
+	require.NoError(t, err)
+	assert.Equal(t, "local", info.GetType())
+
+	_, pub, err := cstore.Sign("ci", "", []byte("msg"))
+	require.NoError(t, err)
+	assert.Equal(t, info.GetPubKey(), pub)
+
+	require.NoError(t, cstore.Delete("ci", ""))
+	_, err = cstore.Get("ci")
+	require.Error(t, err)
+}
+
+// signerKeybase serves the keys of a keybase to remote signer clients
+type signerKeybase struct {
+	kb     Keybase
+	passwd string
+}
+
+func (s signerKeybase) PubKey(name string) (crypto.PubKey, error) {
+	info, err := s.kb.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return info.GetPubKey(), nil
+}
+
+func (s signerKeybase) Sign(name string, msg []byte) (crypto.Signature, crypto.PubKey, error) {
+	return s.kb.Sign(name, s.passwd, msg)
+}
+
+func TestRemoteKey(t *testing.T) {
+	signerKb := NewInMemory()
+	key, _, err := signerKb.CreateMnemonic("key", English, "signer", "", Secp256k1)
+	require.NoError(t, err)
+
+	signerID := crypto.GenPrivKeyEd25519()
+	clientID := crypto.GenPrivKeyEd25519()
+	server := remote.NewServer(signerID, signerKeybase{signerKb, "signer"}, nil, log.NewNopLogger()).
+		WithAllowedClients(clientID.PubKey())
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	go server.Serve(ln)
+	addr := "tcp://" + ln.Addr().String()
+
+	cstore := NewInMemory()
+	_, err = cstore.CreateRemote("remote", addr, "missing", signerID.PubKey(), clientID, "client")
+	require.Error(t, err)
+	_, err = cstore.CreateRemote("remote", addr, "key", signerID.PubKey(), crypto.GenPrivKeyEd25519(), "client")
+	require.Error(t, err)
+	info, err := cstore.CreateRemote("remote", addr, "key", signerID.PubKey(), clientID, "client")
+	require.NoError(t, err)
+	assert.Equal(t, "remote", info.GetType())
+	assert.Equal(t, key.GetPubKey(), info.GetPubKey())
+
+	msg := []byte("msg")
+	_, _, err = cstore.Sign("remote", "wrong", msg)
+	require.Error(t, err)
+	sig, pub, err := cstore.Sign("remote", "client", msg)
+	require.NoError(t, err)
+	assert.Equal(t, key.GetPubKey(), pub)
+	assert.True(t, pub.VerifyBytes(msg, sig))
+
+	_, err = cstore.ExportPrivKey("remote", "client")
+	require.Error(t, err)
+	require.Error(t, cstore.Delete("remote", "client"))
+	require.NoError(t, cstore.Delete("remote", "yes"))
+}
+
+func TestExportImportPrivKey(t *testing.T) {
+	cstore := NewInMemory()
+	priv := crypto.GenPrivKeyEd25519()
+	info, err := cstore.ImportPrivKey("imported", priv, "passwd")
+	require.NoError(t, err)
+	assert.Equal(t, priv.PubKey(), info.GetPubKey())
+	_, err = cstore.ImportPrivKey("imported", priv, "passwd")
+	require.Error(t, err)
+
+	_, err = cstore.ExportPrivKey("imported", "wrong")
+	require.Error(t, err)
+	exported, err := cstore.ExportPrivKey("imported", "passwd")
+	require.NoError(t, err)
+	assert.Equal(t, priv, exported)
+}
+
+func TestKeyMetadata(t *testing.T) {
+	cstore := NewInMemory()
+	_, err := cstore.SetMetadata("john", map[string]string{"note": "cold"})
+	require.Error(t, err)
+
+	_, _, err = cstore.CreateMnemonic("john", English, "secretcpw", "", Secp256k1)
+	require.NoError(t, err)
+	john, err := cstore.Get("john")
+	require.NoError(t, err)
+	assert.Empty(t, john.GetMetadata())
+
+	metadata := map[string]string{"note": "cold storage", "tags": "ops,mainnet"}
+	info, err := cstore.SetMetadata("john", map[string]string{"note": "cold storage", "tags": "ops,mainnet", "empty": ""})
+	require.NoError(t, err)
+	assert.Equal(t, metadata, info.GetMetadata())
+	john, err = cstore.Get("john")
+	require.NoError(t, err)
+	assert.Equal(t, metadata, john.GetMetadata())
+
+	// the metadata survives updating the passphrase
+	require.NoError(t, cstore.Update("john", "secretcpw", "newpass"))
+	john, err = cstore.Get("john")
+	require.NoError(t, err)
+	assert.Equal(t, metadata, john.GetMetadata())
+
+	// and exporting and importing the key
+	armor, err := cstore.Export("john")
+	require.NoError(t, err)
+	require.NoError(t, cstore.Import("john2", armor))
+	john2, err := cstore.Get("john2")
+	require.NoError(t, err)
+	assert.Equal(t, metadata, john2.GetMetadata())
+
+	armor, err = cstore.ExportPrivKeyArmor("john", "newpass", "exportpass")
+	require.NoError(t, err)
+	other := NewInMemory()
+	require.NoError(t, other.ImportPrivKeyArmor("john", armor, "exportpass"))
+	imported, err := other.Get("john")
+	require.NoError(t, err)
+	assert.Equal(t, john.GetPubKey(), imported.GetPubKey())
+	assert.Equal(t, metadata, imported.GetMetadata())
+
+	// keys of other types have metadata too
+	_, err = cstore.CreateOffline("offline", john.GetPubKey())
+	require.NoError(t, err)
+	_, err = cstore.SetMetadata("offline", map[string]string{"note": "watch only"})
+	require.NoError(t, err)
+	offline, err := cstore.Get("offline")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"note": "watch only"}, offline.GetMetadata())
+
+	// the metadata is deleted with the key, a new key of the same name
+	// doesn't inherit it
+	require.NoError(t, cstore.Delete("john", "newpass"))
+	_, _, err = cstore.CreateMnemonic("john", English, "secretcpw", "", Secp256k1)
+	require.NoError(t, err)
+	john, err = cstore.Get("john")
+	require.NoError(t, err)
+	assert.Empty(t, john.GetMetadata())
+}
+
 func ExampleNew() {
 	// Select the encryption and storage for your cryptostore
 	cstore := New(
@@ -338,7 +608,7 @@ func ExampleNew() {
 	sec := Secp256k1
 
 	// Add keys and see they return in alphabetical order
-	bob, _, err := cstore.CreateMnemonic("Bob", English, "friend", sec)
+	bob, _, err := cstore.CreateMnemonic("Bob", English, "friend", "", sec)
 	if err != nil {
 		// this should never happen
 		fmt.Println(err)
@@ -346,8 +616,8 @@ func ExampleNew() {
 		// return info here just like in List
 		fmt.Println(bob.GetName())
 	}
-	cstore.CreateMnemonic("Alice", English, "secret", sec)
-	cstore.CreateMnemonic("Carl", English, "mitm", sec)
+	cstore.CreateMnemonic("Alice", English, "secret", "", sec)
+	cstore.CreateMnemonic("Carl", English, "mitm", "", sec)
 	info, _ := cstore.List()
 	for _, i := range info {
 		fmt.Println(i.GetName())
@@ -7,6 +7,9 @@ const (
 	// Secp256k1 uses the Bitcoin secp256k1 ECDSA parameters.
 	Secp256k1 = SigningAlgo("secp256k1")
 	// Ed25519 represents the Ed25519 signature system.
-	// It is currently not supported for end-user keys (wallets/ledgers).
+	// Ledgers don't support it, keys are derived as specified by SLIP-0010.
 	Ed25519 = SigningAlgo("ed25519")
+	// Secp256r1 uses the ECDSA parameters of the NIST P-256 curve.
+	// Ledgers don't support it, keys are derived as specified by SLIP-0010.
+	Secp256r1 = SigningAlgo("secp256r1")
 )
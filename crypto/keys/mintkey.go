@@ -1,12 +1,21 @@
 package keys
 
 import (
+	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	cmn "github.com/tepleton/tmlibs/common"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
 
+	ccrypto "github.com/tepleton/tepleton-sdk/crypto"
 	"github.com/tepleton/tepleton-sdk/crypto/keys/bcrypt"
+	"github.com/tepleton/tepleton-sdk/crypto/xchacha20poly1305"
 	"github.com/tepleton/tepleton/crypto"
 )
 
@@ -31,6 +40,113 @@ const (
 // TODO: Consider increasing default
 var BcryptSecurityParameter = 12
 
+// privKeyArmorVersion is the version of the encrypted private key format.
+// Version 1 keys are encrypted with XChaCha20-Poly1305 under a key derived
+// with Argon2id or scrypt. All headers of the armor are authenticated.
+// Legacy keys, encrypted with a bcrypt derived key, have no version.
+const privKeyArmorVersion = "1"
+
+// KDFs private keys can be encrypted with
+const (
+	KDFArgon2id = "argon2id"
+	KDFScrypt   = "scrypt"
+)
+
+// KDFParams are the parameters of the KDF deriving the encryption key of a
+// private key from its passphrase. They are stored in the headers of the
+// encrypted key, so changing them doesn't affect stored keys.
+type KDFParams struct {
+	KDF string
+
+	// Argon2id time and memory, in KiB, costs and parallelism
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+
+	// scrypt cost and block size parameters and parallelism
+	N int
+	R int
+	P int
+}
+
+// Limits of the KDF params decrypting a private key, so that a tampered key
+// can't make the decryption exhaust the memory or run for hours
+const (
+	maxArgon2Time    = 64
+	maxArgon2Memory  = 4 * 1024 * 1024 // KiB, 4 GiB
+	maxArgon2Threads = 255
+	maxScryptN       = 1 << 22
+	maxScryptR       = 32
+	maxScryptP       = 16
+)
+
+// DefaultKDFParams are the Argon2id parameters recommended for interactive use
+var DefaultKDFParams = KDFParams{KDF: KDFArgon2id, Time: 1, Memory: 64 * 1024, Threads: 4}
+
+// EncryptionKDFParams are the KDF parameters new private keys are encrypted
+// with. Like BcryptSecurityParameter it's a var so tests can lower them.
+var EncryptionKDFParams = DefaultKDFParams
+
+// String encodes the parameters for the kdf.params header
+func (p KDFParams) String() string {
+	if p.KDF == KDFScrypt {
+		return fmt.Sprintf("n=%d,r=%d,p=%d", p.N, p.R, p.P)
+	}
+	return fmt.Sprintf("t=%d,m=%d,p=%d", p.Time, p.Memory, p.Threads)
+}
+
+// parseKDFParams decodes the kdf and kdf.params headers
+func parseKDFParams(kdf, params string) (p KDFParams, err error) {
+	p.KDF = kdf
+	values := make(map[string]uint64)
+	for _, param := range strings.Split(params, ",") {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) != 2 {
+			return p, fmt.Errorf("Invalid KDF params: %q", params)
+		}
+		values[kv[0]], err = strconv.ParseUint(kv[1], 10, 32)
+		if err != nil {
+			return p, fmt.Errorf("Invalid KDF params: %q", params)
+		}
+	}
+	switch kdf {
+	case KDFArgon2id:
+		if !inRange(values["t"], maxArgon2Time) || !inRange(values["m"], maxArgon2Memory) ||
+			!inRange(values["p"], maxArgon2Threads) {
+			return p, fmt.Errorf("Invalid KDF params: %q", params)
+		}
+		p.Time, p.Memory, p.Threads = uint32(values["t"]), uint32(values["m"]), uint8(values["p"])
+	case KDFScrypt:
+		// the cost parameter must be a power of two
+		n := values["n"]
+		if n < 2 || n&(n-1) != 0 || n > maxScryptN ||
+			!inRange(values["r"], maxScryptR) || !inRange(values["p"], maxScryptP) {
+			return p, fmt.Errorf("Invalid KDF params: %q", params)
+		}
+		p.N, p.R, p.P = int(values["n"]), int(values["r"]), int(values["p"])
+	default:
+		return p, fmt.Errorf("Unrecognized KDF type: %v", kdf)
+	}
+	return p, nil
+}
+
+// inRange checks a KDF param is set and below max
+func inRange(value, max uint64) bool {
+	return value != 0 && value <= max
+}
+
+// deriveKey derives the encryption key of passphrase
+func (p KDFParams) deriveKey(passphrase string, salt []byte) ([]byte, error) {
+	switch p.KDF {
+	case KDFArgon2id:
+		return argon2.IDKey([]byte(passphrase), salt, p.Time, p.Memory, p.Threads, xchacha20poly1305.KeySize), nil
+	case KDFScrypt:
+		return scrypt.Key([]byte(passphrase), salt, p.N, p.R, p.P, xchacha20poly1305.KeySize)
+	default:
+		return nil, fmt.Errorf("Unrecognized KDF type: %v", p.KDF)
+	}
+}
+
 func armorInfoBytes(bz []byte) string {
 	return armorBytes(bz, blockTypeKeyInfo)
 }
@@ -71,27 +187,153 @@ func unarmorBytes(armorStr, blockType string) (bz []byte, err error) {
 	return
 }
 
-func encryptArmorPrivKey(privKey crypto.PrivKey, passphrase string) string {
-	saltBytes, encBytes := encryptPrivKey(privKey, passphrase)
+// encryptArmorPrivKey encrypts privKey with passphrase in the current format.
+// path is the derivation path of the key, if any, and metadata the metadata
+// of the key to carry along in an authenticated header, if any.
+func encryptArmorPrivKey(privKey crypto.PrivKey, passphrase, path string, metadata []MetadataField) string {
+	params := EncryptionKDFParams
+	salt := crypto.CRandBytes(16)
+	nonce := crypto.CRandBytes(xchacha20poly1305.NonceSize)
 	header := map[string]string{
-		"kdf":  "bcrypt",
-		"salt": fmt.Sprintf("%X", saltBytes),
+		"version":    privKeyArmorVersion,
+		"kdf":        params.KDF,
+		"kdf.params": params.String(),
+		"salt":       fmt.Sprintf("%X", salt),
+		"nonce":      fmt.Sprintf("%X", nonce),
+		"type":       keyType(privKey),
+	}
+	if path != "" {
+		header["path"] = path
 	}
-	armorStr := crypto.EncodeArmor(blockTypePrivKey, header, encBytes)
-	return armorStr
+	if len(metadata) > 0 {
+		bz, err := json.Marshal(metadata)
+		if err != nil {
+			cmn.Exit("Error encoding the key metadata: " + err.Error())
+		}
+		header["metadata"] = string(bz)
+	}
+	key, err := params.deriveKey(passphrase, salt)
+	if err != nil {
+		cmn.Exit("Error deriving key from passphrase: " + err.Error())
+	}
+	aead, err := xchacha20poly1305.New(key)
+	if err != nil {
+		cmn.Exit("Error creating cipher: " + err.Error())
+	}
+	encBytes := aead.Seal(nil, nonce, privKey.Bytes(), headerBytes(header))
+	return crypto.EncodeArmor(blockTypePrivKey, header, encBytes)
 }
 
 func unarmorDecryptPrivKey(armorStr string, passphrase string) (crypto.PrivKey, error) {
-	var privKey crypto.PrivKey
+	privKey, _, err := decryptArmorPrivKey(armorStr, passphrase)
+	return privKey, err
+}
+
+// decryptArmorPrivKey decrypts a private key in the current or in the legacy
+// format, and returns its derivation path if it's known.
+func decryptArmorPrivKey(armorStr string, passphrase string) (privKey crypto.PrivKey, path string, err error) {
 	blockType, header, encBytes, err := crypto.DecodeArmor(armorStr)
 	if err != nil {
-		return privKey, err
+		return privKey, "", err
 	}
 	if blockType != blockTypePrivKey {
-		return privKey, fmt.Errorf("Unrecognized armor type: %v", blockType)
+		return privKey, "", fmt.Errorf("Unrecognized armor type: %v", blockType)
+	}
+	switch header["version"] {
+	case "":
+		privKey, err = unarmorDecryptLegacyPrivKey(header, encBytes, passphrase)
+		return privKey, "", err
+	case privKeyArmorVersion:
+	default:
+		return privKey, "", fmt.Errorf("Unrecognized version: %v", header["version"])
+	}
+
+	params, err := parseKDFParams(header["kdf"], header["kdf.params"])
+	if err != nil {
+		return privKey, "", err
+	}
+	salt, err := hex.DecodeString(header["salt"])
+	if err != nil || len(salt) == 0 {
+		return privKey, "", fmt.Errorf("Invalid salt: %q", header["salt"])
+	}
+	nonce, err := hex.DecodeString(header["nonce"])
+	if err != nil || len(nonce) != xchacha20poly1305.NonceSize {
+		return privKey, "", fmt.Errorf("Invalid nonce: %q", header["nonce"])
+	}
+	key, err := params.deriveKey(passphrase, salt)
+	if err != nil {
+		return privKey, "", err
+	}
+	aead, err := xchacha20poly1305.New(key)
+	if err != nil {
+		return privKey, "", err
+	}
+	privKeyBytes, err := aead.Open(nil, nonce, encBytes, headerBytes(header))
+	if err != nil {
+		return privKey, "", fmt.Errorf("Ciphertext decryption failed")
+	}
+	err = cdc.UnmarshalBinaryBare(privKeyBytes, &privKey)
+	if err != nil {
+		return privKey, "", err
+	}
+	if keyType(privKey) != header["type"] {
+		return nil, "", fmt.Errorf("Key type %v doesn't match the header %v", keyType(privKey), header["type"])
 	}
+	return privKey, header["path"], nil
+}
+
+// armorMetadata returns the metadata carried by an encrypted private key.
+// Only call it once decryptArmorPrivKey authenticated the headers. Keys in
+// the legacy format carry no metadata.
+func armorMetadata(armorStr string) (metadata []MetadataField, err error) {
+	_, header, _, err := crypto.DecodeArmor(armorStr)
+	if err != nil {
+		return nil, err
+	}
+	if header["version"] != privKeyArmorVersion || header["metadata"] == "" {
+		return nil, nil
+	}
+	err = json.Unmarshal([]byte(header["metadata"]), &metadata)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid metadata: %v", err)
+	}
+	return metadata, nil
+}
+
+// headerBytes encodes the headers of an encrypted private key, sorted by
+// name, to authenticate them
+func headerBytes(header map[string]string) []byte {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var buf bytes.Buffer
+	for _, name := range names {
+		fmt.Fprintf(&buf, "%s: %s\n", name, header[name])
+	}
+	return buf.Bytes()
+}
+
+// keyType names the type of privKey in the headers of encrypted keys
+func keyType(privKey crypto.PrivKey) string {
+	switch privKey.(type) {
+	case crypto.PrivKeySecp256k1:
+		return string(Secp256k1)
+	case crypto.PrivKeyEd25519:
+		return string(Ed25519)
+	case ccrypto.PrivKeySecp256r1:
+		return string(Secp256r1)
+	default:
+		return fmt.Sprintf("%T", privKey)
+	}
+}
+
+// unarmorDecryptLegacyPrivKey decrypts a private key in the legacy format
+func unarmorDecryptLegacyPrivKey(header map[string]string, encBytes []byte, passphrase string) (crypto.PrivKey, error) {
+	var privKey crypto.PrivKey
 	if header["kdf"] != "bcrypt" {
-		return privKey, fmt.Errorf("Unrecognized KDF type: %v", header["KDF"])
+		return privKey, fmt.Errorf("Unrecognized KDF type: %v", header["kdf"])
 	}
 	if header["salt"] == "" {
 		return privKey, fmt.Errorf("Missing salt bytes")
@@ -125,6 +367,7 @@ func decryptPrivKey(saltBytes []byte, encBytes []byte, passphrase string) (privK
 	if err != nil {
 		return privKey, err
 	}
-	privKey, err = crypto.PrivKeyFromBytes(privKeyBytes)
+	// the keybase codec also knows the key types of the sdk
+	err = cdc.UnmarshalBinaryBare(privKeyBytes, &privKey)
 	return privKey, err
 }
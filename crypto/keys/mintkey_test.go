@@ -0,0 +1,211 @@
+package keys
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tepleton/tepleton/crypto"
+
+	"github.com/tepleton/tepleton-sdk/crypto/keys/hd"
+)
+
+// encryptLegacyArmorPrivKey encrypts privKey in the legacy format, to test
+// that legacy keys can still be decrypted
+func encryptLegacyArmorPrivKey(privKey crypto.PrivKey, passphrase string) string {
+	saltBytes, encBytes := encryptPrivKey(privKey, passphrase)
+	header := map[string]string{
+		"kdf":  "bcrypt",
+		"salt": fmt.Sprintf("%X", saltBytes),
+	}
+	return crypto.EncodeArmor(blockTypePrivKey, header, encBytes)
+}
+
+func TestEncryptArmorPrivKey(t *testing.T) {
+	defer func(params KDFParams) { EncryptionKDFParams = params }(EncryptionKDFParams)
+
+	cases := []KDFParams{
+		DefaultKDFParams,
+		{KDF: KDFArgon2id, Time: 2, Memory: 1024, Threads: 1},
+		{KDF: KDFScrypt, N: 1 << 12, R: 8, P: 1},
+	}
+	for i, params := range cases {
+		EncryptionKDFParams = params
+		priv := crypto.GenPrivKeyEd25519()
+		armor := encryptArmorPrivKey(priv, "passphrase", "44'/118'/0'/0'/0'", nil)
+
+		blockType, header, _, err := crypto.DecodeArmor(armor)
+		require.NoError(t, err)
+		assert.Equal(t, blockTypePrivKey, blockType)
+		assert.Equal(t, privKeyArmorVersion, header["version"], "%d", i)
+		assert.Equal(t, params.KDF, header["kdf"], "%d", i)
+		assert.Equal(t, params.String(), header["kdf.params"], "%d", i)
+		assert.Equal(t, string(Ed25519), header["type"], "%d", i)
+
+		decrypted, path, err := decryptArmorPrivKey(armor, "passphrase")
+		require.NoError(t, err, "%d", i)
+		assert.Equal(t, priv, decrypted, "%d", i)
+		assert.Equal(t, "44'/118'/0'/0'/0'", path, "%d", i)
+
+		_, _, err = decryptArmorPrivKey(armor, "wrong")
+		assert.Error(t, err, "%d", i)
+	}
+}
+
+func TestArmorPrivKeyHeadersAuthenticated(t *testing.T) {
+	priv := crypto.GenPrivKeySecp256k1()
+	armor := encryptArmorPrivKey(priv, "passphrase", "44'/118'/0'/0/0", nil)
+	blockType, header, encBytes, err := crypto.DecodeArmor(armor)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name, value string
+	}{
+		{"path", "44'/118'/0'/0/1"},
+		{"type", string(Ed25519)},
+		{"kdf.params", "t=1,m=65536,p=2"},
+		{"comment", "added"},
+		{"metadata", `[{"name":"note","value":"added"}]`},
+	}
+	for _, tc := range cases {
+		tampered := make(map[string]string)
+		for name, value := range header {
+			tampered[name] = value
+		}
+		tampered[tc.name] = tc.value
+		_, _, err := decryptArmorPrivKey(crypto.EncodeArmor(blockType, tampered, encBytes), "passphrase")
+		assert.Error(t, err, tc.name)
+	}
+
+	_, err = parseKDFParams(KDFArgon2id, "t=1,m=0,p=1")
+	assert.Error(t, err)
+	_, err = parseKDFParams("pbkdf2", "c=1000")
+	assert.Error(t, err)
+}
+
+func TestParseKDFParams(t *testing.T) {
+	cases := []struct {
+		kdf, params string
+		valid       bool
+	}{
+		{KDFArgon2id, DefaultKDFParams.String(), true},
+		{KDFArgon2id, "t=64,m=4194304,p=255", true},
+		{KDFArgon2id, "t=65,m=65536,p=4", false},
+		{KDFArgon2id, "t=1,m=4194305,p=4", false},
+		{KDFArgon2id, "t=1,m=65536,p=256", false},
+		{KDFArgon2id, "t=1,m=65536", false},
+		{KDFScrypt, "n=4096,r=8,p=1", true},
+		{KDFScrypt, "n=4194304,r=32,p=16", true},
+		{KDFScrypt, "n=4095,r=8,p=1", false},
+		{KDFScrypt, "n=1,r=8,p=1", false},
+		{KDFScrypt, "n=8388608,r=8,p=1", false},
+		{KDFScrypt, "n=4096,r=33,p=1", false},
+		{KDFScrypt, "n=4096,r=8,p=17", false},
+		{KDFScrypt, "n=4096,r=8", false},
+	}
+	for _, tc := range cases {
+		_, err := parseKDFParams(tc.kdf, tc.params)
+		assert.Equal(t, tc.valid, err == nil, "%s %s", tc.kdf, tc.params)
+	}
+}
+
+func TestLegacyPrivKeyArmor(t *testing.T) {
+	priv := crypto.GenPrivKeySecp256k1()
+	legacy := encryptLegacyArmorPrivKey(priv, "passphrase")
+	decrypted, path, err := decryptArmorPrivKey(legacy, "passphrase")
+	require.NoError(t, err)
+	assert.Equal(t, priv, decrypted)
+	assert.Equal(t, "", path)
+
+	// legacy keys can be imported, and are stored in the current format
+	cstore := NewInMemory()
+	require.Error(t, cstore.ImportPrivKeyArmor("imported", legacy, "wrong"))
+	require.NoError(t, cstore.ImportPrivKeyArmor("imported", legacy, "passphrase"))
+	info, err := cstore.Get("imported")
+	require.NoError(t, err)
+	assert.Equal(t, priv.PubKey(), info.GetPubKey())
+	assertArmorVersion(t, info, privKeyArmorVersion)
+
+	// stored legacy keys are upgraded by updating them
+	kb := dbKeybase{storage: NewMemStorage()}
+	kb.writeInfo(newLocalInfo("legacy", priv.PubKey(), legacy, nil), "legacy")
+	_, pub, err := kb.Sign("legacy", "passphrase", []byte("msg"))
+	require.NoError(t, err)
+	assert.Equal(t, priv.PubKey(), pub)
+	require.NoError(t, kb.Update("legacy", "passphrase", "passphrase"))
+	info, err = kb.Get("legacy")
+	require.NoError(t, err)
+	assertArmorVersion(t, info, privKeyArmorVersion)
+	_, _, err = kb.Sign("legacy", "passphrase", []byte("msg"))
+	require.NoError(t, err)
+}
+
+func TestExportImportPrivKeyArmor(t *testing.T) {
+	cstore := NewInMemory()
+	mnemonic := "equip will roof matter pink blind book anxiety banner elbow sun young"
+	info, err := cstore.Derive("john", mnemonic, "passphrase", "", *hd.NewFundraiserParams(0, 0), Ed25519)
+	require.NoError(t, err)
+
+	_, err = cstore.ExportPrivKeyArmor("john", "wrong", "export")
+	require.Error(t, err)
+	armor, err := cstore.ExportPrivKeyArmor("john", "passphrase", "export")
+	require.NoError(t, err)
+	_, header, _, err := crypto.DecodeArmor(armor)
+	require.NoError(t, err)
+	assert.Equal(t, string(Ed25519), header["type"])
+	assert.Equal(t, "44'/118'/0'/0'/0'", header["path"])
+
+	other := NewInMemory()
+	require.NoError(t, other.ImportPrivKeyArmor("john", armor, "export"))
+	imported, err := other.Get("john")
+	require.NoError(t, err)
+	assert.Equal(t, info.GetPubKey(), imported.GetPubKey())
+	require.Error(t, other.ImportPrivKeyArmor("john", armor, "export"))
+}
+
+func assertArmorVersion(t *testing.T, info Info, version string) {
+	linfo, ok := info.(localInfo)
+	require.True(t, ok)
+	_, header, _, err := crypto.DecodeArmor(linfo.PrivKeyArmor)
+	require.NoError(t, err)
+	assert.Equal(t, version, header["version"])
+}
+
+// TestLegacyKeystore reads and migrates a keystore written by the go-crypto
+// keybase
+func TestLegacyKeystore(t *testing.T) {
+	priv := crypto.GenPrivKeySecp256k1()
+	legacy := NewMemStorage()
+	legacy.Set("old", cdc.MustMarshalBinaryBare(legacyInfo{
+		Name:         "old",
+		PubKey:       priv.PubKey(),
+		PrivKeyArmor: encryptLegacyArmorPrivKey(priv, "passphrase"),
+	}))
+
+	// the keybase reads the keys in place
+	kb := NewWithStorage(legacy)
+	info, err := kb.Get("old")
+	require.NoError(t, err)
+	assert.Equal(t, "old", info.GetName())
+	assert.Equal(t, "local", info.GetType())
+	_, pub, err := kb.Sign("old", "passphrase", []byte("msg"))
+	require.NoError(t, err)
+	assert.Equal(t, priv.PubKey(), pub)
+
+	// migrating rewrites them in the current format
+	to := NewMemStorage()
+	migrated, err := Migrate(legacy, to)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"old"}, migrated)
+	var migratedInfo Info
+	require.NoError(t, cdc.UnmarshalBinary(to.Get("old"), &migratedInfo))
+	assert.Equal(t, priv.PubKey(), migratedInfo.GetPubKey())
+	_, _, err = NewWithStorage(to).Sign("old", "passphrase", []byte("msg"))
+	require.NoError(t, err)
+
+	// keys that can't be decoded fail the migration
+	legacy.Set("broken", []byte("garbage"))
+	_, err = Migrate(legacy, NewMemStorage())
+	assert.Error(t, err)
+}
@@ -0,0 +1,203 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	tcrypto "github.com/tepleton/tepleton/crypto"
+	"github.com/tepleton/tepleton/types"
+)
+
+// Client calls a remote signer. It reconnects once if a call fails on a
+// broken connection, so it can be kept open for a long time.
+type Client struct {
+	addr         string
+	identity     tcrypto.PrivKey
+	signerPubKey tcrypto.PubKey
+
+	mtx  sync.Mutex
+	conn net.Conn
+}
+
+// Dial connects to the signer at addr, tcp://host:port or unix:///path,
+// authenticating as identity, an ed25519 key. If signerPubKey isn't nil,
+// the signer must authenticate with it.
+func Dial(addr string, identity tcrypto.PrivKey, signerPubKey tcrypto.PubKey) (*Client, error) {
+	conn, err := dial(addr, identity, signerPubKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		addr:         addr,
+		identity:     identity,
+		signerPubKey: signerPubKey,
+		conn:         conn,
+	}, nil
+}
+
+// Close closes the connection to the signer
+func (c *Client) Close() error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// PubKey returns the public key of the key keyName of the signer
+func (c *Client) PubKey(keyName string) (tcrypto.PubKey, error) {
+	res, err := c.call(PubKeyRequest{KeyName: keyName})
+	if err != nil {
+		return nil, err
+	}
+	pkRes, ok := res.(PubKeyResponse)
+	if !ok {
+		return nil, unexpectedResponse(res)
+	}
+	return pkRes.PubKey, nil
+}
+
+// Sign signs msg with the key keyName of the signer
+func (c *Client) Sign(keyName string, msg []byte) (tcrypto.Signature, tcrypto.PubKey, error) {
+	res, err := c.call(SignBytesRequest{KeyName: keyName, Bytes: msg})
+	if err != nil {
+		return nil, nil, err
+	}
+	sigRes, ok := res.(SignBytesResponse)
+	if !ok {
+		return nil, nil, unexpectedResponse(res)
+	}
+	return sigRes.Signature, sigRes.PubKey, nil
+}
+
+// call sends req and returns the response, redialing and sending it again
+// if the connection is broken
+func (c *Client) call(req Msg) (Msg, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	res, err := c.roundTrip(req)
+	if err != nil {
+		// redial and try once more, the signer answers a request it
+		// already signed with the same signature
+		if c.conn != nil {
+			c.conn.Close()
+		}
+		c.conn, err = dial(c.addr, c.identity, c.signerPubKey)
+		if err != nil {
+			return nil, err
+		}
+		res, err = c.roundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if errRes, ok := res.(ErrorResponse); ok {
+		return nil, errors.New(errRes.Error)
+	}
+	return res, nil
+}
+
+func (c *Client) roundTrip(req Msg) (Msg, error) {
+	if c.conn == nil {
+		return nil, errors.New("connection to the signer is closed")
+	}
+	err := c.conn.SetDeadline(time.Now().Add(callTimeout))
+	if err != nil {
+		return nil, err
+	}
+	err = writeMsg(c.conn, req)
+	if err != nil {
+		return nil, err
+	}
+	return readMsg(c.conn)
+}
+
+func unexpectedResponse(res Msg) error {
+	return fmt.Errorf("unexpected response from the signer: %T", res)
+}
+
+var _ types.PrivValidator = (*PrivValidator)(nil)
+
+// PrivValidator signs votes, proposals and heartbeats of a node with the
+// validator key of a remote signer.
+type PrivValidator struct {
+	client *Client
+	pubKey tcrypto.PubKey
+}
+
+// NewPrivValidator returns the PrivValidator of the validator key of the
+// signer client is connected to.
+func NewPrivValidator(client *Client) (*PrivValidator, error) {
+	res, err := client.call(ValidatorPubKeyRequest{})
+	if err != nil {
+		return nil, err
+	}
+	pkRes, ok := res.(PubKeyResponse)
+	if !ok {
+		return nil, unexpectedResponse(res)
+	}
+	return &PrivValidator{client: client, pubKey: pkRes.PubKey}, nil
+}
+
+// GetAddress implements PrivValidator
+func (pv *PrivValidator) GetAddress() types.Address {
+	return pv.pubKey.Address()
+}
+
+// GetPubKey implements PrivValidator
+func (pv *PrivValidator) GetPubKey() tcrypto.PubKey {
+	return pv.pubKey
+}
+
+// SignVote implements PrivValidator
+func (pv *PrivValidator) SignVote(chainID string, vote *types.Vote) error {
+	res, err := pv.client.call(SignVoteRequest{ChainID: chainID, Vote: vote})
+	if err != nil {
+		return err
+	}
+	voteRes, ok := res.(SignVoteResponse)
+	if !ok || voteRes.Vote == nil {
+		return unexpectedResponse(res)
+	}
+	*vote = *voteRes.Vote
+	return nil
+}
+
+// SignProposal implements PrivValidator
+func (pv *PrivValidator) SignProposal(chainID string, proposal *types.Proposal) error {
+	res, err := pv.client.call(SignProposalRequest{ChainID: chainID, Proposal: proposal})
+	if err != nil {
+		return err
+	}
+	proposalRes, ok := res.(SignProposalResponse)
+	if !ok || proposalRes.Proposal == nil {
+		return unexpectedResponse(res)
+	}
+	*proposal = *proposalRes.Proposal
+	return nil
+}
+
+// SignHeartbeat implements PrivValidator
+func (pv *PrivValidator) SignHeartbeat(chainID string, heartbeat *types.Heartbeat) error {
+	res, err := pv.client.call(SignHeartbeatRequest{ChainID: chainID, Heartbeat: heartbeat})
+	if err != nil {
+		return err
+	}
+	heartbeatRes, ok := res.(SignHeartbeatResponse)
+	if !ok || heartbeatRes.Heartbeat == nil {
+		return unexpectedResponse(res)
+	}
+	*heartbeat = *heartbeatRes.Heartbeat
+	return nil
+}
+
+func (pv *PrivValidator) String() string {
+	return fmt.Sprintf("RemotePrivValidator{%v %s}", pv.GetAddress(), pv.client.addr)
+}
@@ -0,0 +1,74 @@
+// Package remote implements a protocol to sign with keys held by a remote
+// signer, so keys don't have to live on the hosts of nodes or clients.
+//
+// Clients connect to the signer over TCP or a Unix socket. Both ends
+// authenticate with an ed25519 identity key and the connection is
+// encrypted, see the SecretConnection of tepleton/p2p/conn. The signer
+// signs arbitrary bytes with its keys, for the Keybase, and votes,
+// proposals and heartbeats with its validator key, for the node.
+package remote
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	tcrypto "github.com/tepleton/tepleton/crypto"
+	p2pconn "github.com/tepleton/tepleton/p2p/conn"
+	cmn "github.com/tepleton/tmlibs/common"
+)
+
+const (
+	// maxMsgSize bounds the size of the messages read from a connection
+	maxMsgSize = 1 << 20
+
+	dialTimeout = 3 * time.Second
+	callTimeout = 10 * time.Second
+)
+
+// dial opens an authenticated encrypted connection to addr, tcp://host:port
+// or unix:///path. If signerPubKey isn't nil, the signer must authenticate
+// with it.
+func dial(addr string, identity tcrypto.PrivKey, signerPubKey tcrypto.PubKey) (net.Conn, error) {
+	protocol, address := cmn.ProtocolAndAddress(addr)
+	conn, err := net.DialTimeout(protocol, address, dialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	sc, err := secretConn(conn, identity)
+	if err != nil {
+		return nil, err
+	}
+	if signerPubKey != nil && !sc.RemotePubKey().Equals(signerPubKey) {
+		sc.Close()
+		return nil, fmt.Errorf("signer at %s authenticated with unexpected key %X", addr, sc.RemotePubKey().Bytes())
+	}
+	return sc, nil
+}
+
+// secretConn upgrades conn to an authenticated encrypted connection,
+// closing it if the handshake fails
+func secretConn(conn net.Conn, identity tcrypto.PrivKey) (*p2pconn.SecretConnection, error) {
+	err := conn.SetDeadline(time.Now().Add(dialTimeout))
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	sc, err := p2pconn.MakeSecretConnection(conn, identity)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return sc, conn.SetDeadline(time.Time{})
+}
+
+func readMsg(r io.Reader) (msg Msg, err error) {
+	_, err = cdc.UnmarshalBinaryReader(r, &msg, maxMsgSize)
+	return
+}
+
+func writeMsg(w io.Writer, msg Msg) error {
+	_, err := cdc.MarshalBinaryWriter(w, msg)
+	return err
+}
@@ -0,0 +1,236 @@
+package remote
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	tcrypto "github.com/tepleton/tepleton/crypto"
+	"github.com/tepleton/tepleton/types"
+	cmn "github.com/tepleton/tmlibs/common"
+)
+
+// steps of a round, in the order they are signed in
+const (
+	stepNone      int8 = 0
+	stepPropose   int8 = 1
+	stepPrevote   int8 = 2
+	stepPrecommit int8 = 3
+)
+
+func voteToStep(vote *types.Vote) (int8, error) {
+	switch vote.Type {
+	case types.VoteTypePrevote:
+		return stepPrevote, nil
+	case types.VoteTypePrecommit:
+		return stepPrecommit, nil
+	default:
+		return stepNone, fmt.Errorf("unknown vote type %v", vote.Type)
+	}
+}
+
+// SignState is the last height, round and step signed by a GuardedPV, with
+// what was signed
+type SignState struct {
+	Height    int64             `json:"height"`
+	Round     int               `json:"round"`
+	Step      int8              `json:"step"`
+	Signature tcrypto.Signature `json:"signature,omitempty"`
+	SignBytes cmn.HexBytes      `json:"sign_bytes,omitempty"`
+}
+
+var _ types.PrivValidator = (*GuardedPV)(nil)
+
+// GuardedPV signs with a validator key, refusing to sign for a height,
+// round and step older than the last one signed, or to sign anything else
+// than what it signed already for the last one. The last one is saved
+// before a signature is returned, so the guard survives restarts.
+type GuardedPV struct {
+	privKey   tcrypto.PrivKey
+	stateFile string
+	state     SignState
+}
+
+// NewGuardedPV returns a GuardedPV signing with privKey, loading the last
+// height, round and step signed from stateFile if it exists
+func NewGuardedPV(privKey tcrypto.PrivKey, stateFile string) (*GuardedPV, error) {
+	pv := &GuardedPV{privKey: privKey, stateFile: stateFile}
+	bz, err := ioutil.ReadFile(stateFile)
+	if os.IsNotExist(err) {
+		return pv, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = cdc.UnmarshalJSON(bz, &pv.state)
+	if err != nil {
+		return nil, fmt.Errorf("reading sign state %s: %v", stateFile, err)
+	}
+	return pv, nil
+}
+
+// State returns the last height, round and step signed
+func (pv *GuardedPV) State() SignState {
+	return pv.state
+}
+
+// GetAddress implements PrivValidator
+func (pv *GuardedPV) GetAddress() types.Address {
+	return pv.privKey.PubKey().Address()
+}
+
+// GetPubKey implements PrivValidator
+func (pv *GuardedPV) GetPubKey() tcrypto.PubKey {
+	return pv.privKey.PubKey()
+}
+
+// SignVote implements PrivValidator
+func (pv *GuardedPV) SignVote(chainID string, vote *types.Vote) error {
+	step, err := voteToStep(vote)
+	if err != nil {
+		return err
+	}
+	sig, timestamp, err := pv.sign(vote.Height, vote.Round, step, vote.SignBytes(chainID))
+	if err != nil {
+		return fmt.Errorf("error signing vote: %v", err)
+	}
+	if !timestamp.IsZero() {
+		vote.Timestamp = timestamp
+	}
+	vote.Signature = sig
+	return nil
+}
+
+// SignProposal implements PrivValidator
+func (pv *GuardedPV) SignProposal(chainID string, proposal *types.Proposal) error {
+	sig, timestamp, err := pv.sign(proposal.Height, proposal.Round, stepPropose, proposal.SignBytes(chainID))
+	if err != nil {
+		return fmt.Errorf("error signing proposal: %v", err)
+	}
+	if !timestamp.IsZero() {
+		proposal.Timestamp = timestamp
+	}
+	proposal.Signature = sig
+	return nil
+}
+
+// SignHeartbeat implements PrivValidator. Heartbeats can't be used to
+// double sign so they aren't guarded.
+func (pv *GuardedPV) SignHeartbeat(chainID string, heartbeat *types.Heartbeat) error {
+	sig, err := pv.privKey.Sign(heartbeat.SignBytes(chainID))
+	if err != nil {
+		return err
+	}
+	heartbeat.Signature = sig
+	return nil
+}
+
+// sign signs signBytes for height, round and step. If they were signed
+// already, it returns the last signature, with the timestamp signed if
+// only the timestamps differ.
+func (pv *GuardedPV) sign(height int64, round int, step int8, signBytes []byte) (sig tcrypto.Signature, timestamp time.Time, err error) {
+	same, err := pv.checkHRS(height, round, step)
+	if err != nil {
+		return
+	}
+	if same {
+		if bytes.Equal(signBytes, pv.state.SignBytes) {
+			return pv.state.Signature, timestamp, nil
+		}
+		lastTimestamp, ok := onlyDifferByTimestamp(pv.state.SignBytes, signBytes)
+		if !ok {
+			return nil, timestamp, errors.New("conflicting data")
+		}
+		return pv.state.Signature, lastTimestamp, nil
+	}
+
+	sig, err = pv.privKey.Sign(signBytes)
+	if err != nil {
+		return
+	}
+	err = pv.save(SignState{
+		Height:    height,
+		Round:     round,
+		Step:      step,
+		Signature: sig,
+		SignBytes: signBytes,
+	})
+	if err != nil {
+		return nil, timestamp, err
+	}
+	return sig, timestamp, nil
+}
+
+// checkHRS returns an error if height, round and step are older than the
+// last ones signed, and whether they are the last ones signed
+func (pv *GuardedPV) checkHRS(height int64, round int, step int8) (bool, error) {
+	last := pv.state
+	if last.Height > height {
+		return false, fmt.Errorf("height regression, got %v, last height %v", height, last.Height)
+	}
+	if last.Height < height {
+		return false, nil
+	}
+	if last.Round > round {
+		return false, fmt.Errorf("round regression at height %v, got %v, last round %v", height, round, last.Round)
+	}
+	if last.Round < round {
+		return false, nil
+	}
+	if last.Step > step {
+		return false, fmt.Errorf("step regression at height %v round %v, got %v, last step %v", height, round, step, last.Step)
+	}
+	if last.Step < step {
+		return false, nil
+	}
+	if last.SignBytes == nil {
+		return false, errors.New("no sign bytes saved for the last height, round and step")
+	}
+	return true, nil
+}
+
+func (pv *GuardedPV) save(state SignState) error {
+	bz, err := cdc.MarshalJSONIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = cmn.WriteFileAtomic(pv.stateFile, bz, 0600)
+	if err != nil {
+		return err
+	}
+	pv.state = state
+	return nil
+}
+
+// onlyDifferByTimestamp returns the timestamp of lastSignBytes if the JSON
+// sign bytes lastSignBytes and newSignBytes only differ by their timestamp
+func onlyDifferByTimestamp(lastSignBytes, newSignBytes []byte) (time.Time, bool) {
+	var last, next map[string]json.RawMessage
+	if json.Unmarshal(lastSignBytes, &last) != nil || json.Unmarshal(newSignBytes, &next) != nil {
+		return time.Time{}, false
+	}
+	var lastTimestamp string
+	if json.Unmarshal(last["timestamp"], &lastTimestamp) != nil {
+		return time.Time{}, false
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, lastTimestamp)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	delete(last, "timestamp")
+	delete(next, "timestamp")
+	lastBz, err := json.Marshal(last)
+	if err != nil {
+		return time.Time{}, false
+	}
+	nextBz, err := json.Marshal(next)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return timestamp, bytes.Equal(lastBz, nextBz)
+}
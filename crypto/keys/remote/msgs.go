@@ -0,0 +1,73 @@
+package remote
+
+import (
+	tcrypto "github.com/tepleton/tepleton/crypto"
+	"github.com/tepleton/tepleton/types"
+)
+
+// Msg is a request or a response of the remote signer protocol.
+// Every request is answered by its response or by an ErrorResponse.
+type Msg interface{}
+
+// PubKeyRequest asks for the public key of a key of the signer
+type PubKeyRequest struct {
+	KeyName string `json:"key_name"`
+}
+
+// PubKeyResponse returns the public key of a key, or of the validator key
+type PubKeyResponse struct {
+	PubKey tcrypto.PubKey `json:"pub_key"`
+}
+
+// SignBytesRequest asks to sign arbitrary bytes with a key of the signer
+type SignBytesRequest struct {
+	KeyName string `json:"key_name"`
+	Bytes   []byte `json:"bytes"`
+}
+
+// SignBytesResponse returns the signature, and the public key to check it with
+type SignBytesResponse struct {
+	Signature tcrypto.Signature `json:"signature"`
+	PubKey    tcrypto.PubKey    `json:"pub_key"`
+}
+
+// ValidatorPubKeyRequest asks for the public key of the validator key
+type ValidatorPubKeyRequest struct{}
+
+// SignVoteRequest asks to sign a vote with the validator key
+type SignVoteRequest struct {
+	ChainID string      `json:"chain_id"`
+	Vote    *types.Vote `json:"vote"`
+}
+
+// SignVoteResponse returns the signed vote
+type SignVoteResponse struct {
+	Vote *types.Vote `json:"vote"`
+}
+
+// SignProposalRequest asks to sign a proposal with the validator key
+type SignProposalRequest struct {
+	ChainID  string          `json:"chain_id"`
+	Proposal *types.Proposal `json:"proposal"`
+}
+
+// SignProposalResponse returns the signed proposal
+type SignProposalResponse struct {
+	Proposal *types.Proposal `json:"proposal"`
+}
+
+// SignHeartbeatRequest asks to sign a heartbeat with the validator key
+type SignHeartbeatRequest struct {
+	ChainID   string           `json:"chain_id"`
+	Heartbeat *types.Heartbeat `json:"heartbeat"`
+}
+
+// SignHeartbeatResponse returns the signed heartbeat
+type SignHeartbeatResponse struct {
+	Heartbeat *types.Heartbeat `json:"heartbeat"`
+}
+
+// ErrorResponse reports why the signer refused or failed a request
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
@@ -0,0 +1,191 @@
+package remote
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcrypto "github.com/tepleton/tepleton/crypto"
+	"github.com/tepleton/tepleton/types"
+	"github.com/tepleton/tmlibs/log"
+)
+
+type memSigner map[string]tcrypto.PrivKey
+
+func (s memSigner) PubKey(keyName string) (tcrypto.PubKey, error) {
+	priv, ok := s[keyName]
+	if !ok {
+		return nil, fmt.Errorf("no key %s", keyName)
+	}
+	return priv.PubKey(), nil
+}
+
+func (s memSigner) Sign(keyName string, msg []byte) (tcrypto.Signature, tcrypto.PubKey, error) {
+	priv, ok := s[keyName]
+	if !ok {
+		return nil, nil, fmt.Errorf("no key %s", keyName)
+	}
+	sig, err := priv.Sign(msg)
+	return sig, priv.PubKey(), err
+}
+
+func tempDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "remote_signer")
+	require.Nil(t, err)
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+// startServer serves server on a random local port and returns its address
+func startServer(t *testing.T, server *Server) (string, func()) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	go server.Serve(ln)
+	return "tcp://" + ln.Addr().String(), func() { ln.Close() }
+}
+
+func newVote(height int64, round int, typ byte, blockHash string) *types.Vote {
+	return &types.Vote{
+		Height:    height,
+		Round:     round,
+		Type:      typ,
+		Timestamp: time.Unix(1530000000, 0).UTC(),
+		BlockID:   types.BlockID{Hash: []byte(blockHash)},
+	}
+}
+
+func TestRemoteSigner(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+
+	key := tcrypto.GenPrivKeySecp256k1()
+	valKey := tcrypto.GenPrivKeyEd25519()
+	pv, err := NewGuardedPV(valKey, filepath.Join(dir, "sign_state.json"))
+	require.Nil(t, err)
+
+	serverID := tcrypto.GenPrivKeyEd25519()
+	clientID := tcrypto.GenPrivKeyEd25519()
+	server := NewServer(serverID, memSigner{"key": key}, pv, log.NewNopLogger()).
+		WithAllowedClients(clientID.PubKey())
+	addr, stop := startServer(t, server)
+	defer stop()
+
+	client, err := Dial(addr, clientID, serverID.PubKey())
+	require.Nil(t, err)
+	defer client.Close()
+
+	// sign bytes with a key of the signer
+	pub, err := client.PubKey("key")
+	require.Nil(t, err)
+	assert.Equal(t, key.PubKey(), pub)
+	msg := []byte("sign me")
+	sig, pub, err := client.Sign("key", msg)
+	require.Nil(t, err)
+	assert.Equal(t, key.PubKey(), pub)
+	assert.True(t, pub.VerifyBytes(msg, sig))
+	_, err = client.PubKey("missing")
+	require.NotNil(t, err)
+
+	// sign votes with the validator key
+	remotePV, err := NewPrivValidator(client)
+	require.Nil(t, err)
+	assert.Equal(t, valKey.PubKey(), remotePV.GetPubKey())
+	vote := newVote(1, 0, types.VoteTypePrevote, "block")
+	require.Nil(t, remotePV.SignVote("chain", vote))
+	assert.True(t, valKey.PubKey().VerifyBytes(vote.SignBytes("chain"), vote.Signature))
+
+	// the signer refuses to double sign
+	err = remotePV.SignVote("chain", newVote(1, 0, types.VoteTypePrevote, "other block"))
+	require.NotNil(t, err)
+
+	// a client with another identity is disconnected after the handshake
+	other, err := Dial(addr, tcrypto.GenPrivKeyEd25519(), serverID.PubKey())
+	require.Nil(t, err)
+	defer other.Close()
+	_, err = other.PubKey("key")
+	require.NotNil(t, err)
+
+	// a signer with another identity is refused
+	_, err = Dial(addr, clientID, tcrypto.GenPrivKeyEd25519().PubKey())
+	require.NotNil(t, err)
+
+	// a signer without allowed clients serves none
+	closed := NewServer(serverID, memSigner{"key": key}, nil, log.NewNopLogger())
+	closedAddr, stopClosed := startServer(t, closed)
+	defer stopClosed()
+	client, err = Dial(closedAddr, clientID, serverID.PubKey())
+	require.Nil(t, err)
+	defer client.Close()
+	_, err = client.PubKey("key")
+	require.NotNil(t, err)
+}
+
+func TestGuardedPV(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	stateFile := filepath.Join(dir, "sign_state.json")
+
+	valKey := tcrypto.GenPrivKeyEd25519()
+	pv, err := NewGuardedPV(valKey, stateFile)
+	require.Nil(t, err)
+
+	vote := newVote(2, 1, types.VoteTypePrevote, "block")
+	require.Nil(t, pv.SignVote("chain", vote))
+	assert.Equal(t, SignState{
+		Height:    2,
+		Round:     1,
+		Step:      stepPrevote,
+		Signature: vote.Signature,
+		SignBytes: vote.SignBytes("chain"),
+	}, pv.State())
+
+	// signing the same vote again returns the same signature
+	again := newVote(2, 1, types.VoteTypePrevote, "block")
+	require.Nil(t, pv.SignVote("chain", again))
+	assert.Equal(t, vote.Signature, again.Signature)
+
+	// a vote only differing by its timestamp gets the signed timestamp
+	later := newVote(2, 1, types.VoteTypePrevote, "block")
+	later.Timestamp = later.Timestamp.Add(time.Second)
+	require.Nil(t, pv.SignVote("chain", later))
+	assert.Equal(t, vote.Signature, later.Signature)
+	assert.True(t, vote.Timestamp.Equal(later.Timestamp))
+
+	cases := []struct {
+		vote  *types.Vote
+		valid bool
+	}{
+		{newVote(2, 1, types.VoteTypePrevote, "other block"), false},
+		{newVote(1, 5, types.VoteTypePrecommit, "block"), false},
+		{newVote(2, 0, types.VoteTypePrecommit, "block"), false},
+		{newVote(2, 1, types.VoteTypePrecommit, "block"), true},
+		{newVote(2, 1, types.VoteTypePrevote, "block"), false},
+		{newVote(3, 0, types.VoteTypePrevote, "other block"), true},
+	}
+	for i, tc := range cases {
+		err := pv.SignVote("chain", tc.vote)
+		if tc.valid {
+			assert.Nil(t, err, "%d", i)
+		} else {
+			assert.NotNil(t, err, "%d", i)
+		}
+	}
+
+	// proposals come before the votes of a round
+	proposal := &types.Proposal{Height: 3, Round: 0, Timestamp: time.Unix(1530000000, 0).UTC()}
+	require.NotNil(t, pv.SignProposal("chain", proposal))
+	proposal.Round = 1
+	require.Nil(t, pv.SignProposal("chain", proposal))
+	assert.True(t, valKey.PubKey().VerifyBytes(proposal.SignBytes("chain"), proposal.Signature))
+
+	// the guard survives restarts
+	restarted, err := NewGuardedPV(valKey, stateFile)
+	require.Nil(t, err)
+	assert.Equal(t, pv.State(), restarted.State())
+	require.NotNil(t, restarted.SignVote("chain", newVote(3, 0, types.VoteTypePrecommit, "block")))
+}
@@ -0,0 +1,180 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	tcrypto "github.com/tepleton/tepleton/crypto"
+	"github.com/tepleton/tepleton/types"
+	cmn "github.com/tepleton/tmlibs/common"
+	"github.com/tepleton/tmlibs/log"
+)
+
+// Signer signs bytes with named keys, like a Keybase unlocked by the signer
+type Signer interface {
+	PubKey(keyName string) (tcrypto.PubKey, error)
+	Sign(keyName string, msg []byte) (tcrypto.Signature, tcrypto.PubKey, error)
+}
+
+// Server serves the requests of the clients of a remote signer. Keys are
+// signed with by the Signer, votes, proposals and heartbeats by the
+// PrivValidator, either may be nil if the server doesn't sign them.
+type Server struct {
+	identity tcrypto.PrivKey
+	signer   Signer
+	privVal  types.PrivValidator
+	allowed  []tcrypto.PubKey
+	logger   log.Logger
+
+	// votes are signed one at a time
+	pvMtx sync.Mutex
+}
+
+// NewServer creates a server authenticating as identity, an ed25519 key
+func NewServer(identity tcrypto.PrivKey, signer Signer, privVal types.PrivValidator, logger log.Logger) *Server {
+	return &Server{
+		identity: identity,
+		signer:   signer,
+		privVal:  privVal,
+		logger:   logger,
+	}
+}
+
+// WithAllowedClients sets the clients served, those authenticating with
+// one of pubKeys. Without, no client is served.
+func (s *Server) WithAllowedClients(pubKeys ...tcrypto.PubKey) *Server {
+	s.allowed = pubKeys
+	return s
+}
+
+// ListenAndServe listens on addr, tcp://host:port or unix:///path, and
+// serves the clients connecting
+func (s *Server) ListenAndServe(addr string) error {
+	protocol, address := cmn.ProtocolAndAddress(addr)
+	ln, err := net.Listen(protocol, address)
+	if err != nil {
+		return err
+	}
+	s.logger.Info("Remote signer listening", "addr", addr, "identity", fmt.Sprintf("%X", s.identity.PubKey().Bytes()))
+	return s.Serve(ln)
+}
+
+// Serve serves the clients connecting to ln, until ln is closed
+func (s *Server) Serve(ln net.Listener) error {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return err
+		}
+		go s.serveConn(conn)
+	}
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	sc, err := secretConn(conn, s.identity)
+	if err != nil {
+		s.logger.Error("Handshake with client failed", "remote", conn.RemoteAddr(), "err", err)
+		return
+	}
+	defer sc.Close()
+	client := sc.RemotePubKey()
+	if !s.isAllowed(client) {
+		s.logger.Error("Refused client", "remote", conn.RemoteAddr(), "identity", fmt.Sprintf("%X", client.Bytes()))
+		return
+	}
+
+	for {
+		req, err := readMsg(sc)
+		if err != nil {
+			return
+		}
+		err = writeMsg(sc, s.handle(req))
+		if err != nil {
+			s.logger.Error("Writing response failed", "remote", conn.RemoteAddr(), "err", err)
+			return
+		}
+	}
+}
+
+func (s *Server) isAllowed(pubKey tcrypto.PubKey) bool {
+	for _, allowed := range s.allowed {
+		if allowed.Equals(pubKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// handle answers a request with its response or an ErrorResponse
+func (s *Server) handle(req Msg) Msg {
+	res, err := s.handleRequest(req)
+	if err != nil {
+		s.logger.Error("Request failed", "request", fmt.Sprintf("%T", req), "err", err)
+		return ErrorResponse{Error: err.Error()}
+	}
+	return res
+}
+
+func (s *Server) handleRequest(req Msg) (Msg, error) {
+	switch req := req.(type) {
+	case PubKeyRequest, SignBytesRequest:
+		if s.signer == nil {
+			return nil, errors.New("the signer holds no keys")
+		}
+	case ValidatorPubKeyRequest, SignVoteRequest, SignProposalRequest, SignHeartbeatRequest:
+		if s.privVal == nil {
+			return nil, errors.New("the signer holds no validator key")
+		}
+		s.pvMtx.Lock()
+		defer s.pvMtx.Unlock()
+	default:
+		return nil, fmt.Errorf("unknown request %T", req)
+	}
+
+	switch req := req.(type) {
+	case PubKeyRequest:
+		pubKey, err := s.signer.PubKey(req.KeyName)
+		if err != nil {
+			return nil, err
+		}
+		return PubKeyResponse{PubKey: pubKey}, nil
+	case SignBytesRequest:
+		sig, pubKey, err := s.signer.Sign(req.KeyName, req.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return SignBytesResponse{Signature: sig, PubKey: pubKey}, nil
+	case ValidatorPubKeyRequest:
+		return PubKeyResponse{PubKey: s.privVal.GetPubKey()}, nil
+	case SignVoteRequest:
+		if req.Vote == nil {
+			return nil, errors.New("no vote to sign")
+		}
+		err := s.privVal.SignVote(req.ChainID, req.Vote)
+		if err != nil {
+			return nil, err
+		}
+		return SignVoteResponse{Vote: req.Vote}, nil
+	case SignProposalRequest:
+		if req.Proposal == nil {
+			return nil, errors.New("no proposal to sign")
+		}
+		err := s.privVal.SignProposal(req.ChainID, req.Proposal)
+		if err != nil {
+			return nil, err
+		}
+		return SignProposalResponse{Proposal: req.Proposal}, nil
+	default: // SignHeartbeatRequest
+		heartbeatReq := req.(SignHeartbeatRequest)
+		if heartbeatReq.Heartbeat == nil {
+			return nil, errors.New("no heartbeat to sign")
+		}
+		err := s.privVal.SignHeartbeat(heartbeatReq.ChainID, heartbeatReq.Heartbeat)
+		if err != nil {
+			return nil, err
+		}
+		return SignHeartbeatResponse{Heartbeat: heartbeatReq.Heartbeat}, nil
+	}
+}
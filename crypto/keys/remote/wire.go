@@ -0,0 +1,29 @@
+package remote
+
+import (
+	amino "github.com/tepleton/go-amino"
+	tcrypto "github.com/tepleton/tepleton/crypto"
+
+	ccrypto "github.com/tepleton/tepleton-sdk/crypto"
+)
+
+var cdc = amino.NewCodec()
+
+func init() {
+	tcrypto.RegisterAmino(cdc)
+	ccrypto.RegisterAmino(cdc)
+
+	cdc.RegisterInterface((*Msg)(nil), nil)
+	cdc.RegisterConcrete(PubKeyRequest{}, "tepleton-sdk/remote/PubKeyRequest", nil)
+	cdc.RegisterConcrete(PubKeyResponse{}, "tepleton-sdk/remote/PubKeyResponse", nil)
+	cdc.RegisterConcrete(SignBytesRequest{}, "tepleton-sdk/remote/SignBytesRequest", nil)
+	cdc.RegisterConcrete(SignBytesResponse{}, "tepleton-sdk/remote/SignBytesResponse", nil)
+	cdc.RegisterConcrete(ValidatorPubKeyRequest{}, "tepleton-sdk/remote/ValidatorPubKeyRequest", nil)
+	cdc.RegisterConcrete(SignVoteRequest{}, "tepleton-sdk/remote/SignVoteRequest", nil)
+	cdc.RegisterConcrete(SignVoteResponse{}, "tepleton-sdk/remote/SignVoteResponse", nil)
+	cdc.RegisterConcrete(SignProposalRequest{}, "tepleton-sdk/remote/SignProposalRequest", nil)
+	cdc.RegisterConcrete(SignProposalResponse{}, "tepleton-sdk/remote/SignProposalResponse", nil)
+	cdc.RegisterConcrete(SignHeartbeatRequest{}, "tepleton-sdk/remote/SignHeartbeatRequest", nil)
+	cdc.RegisterConcrete(SignHeartbeatResponse{}, "tepleton-sdk/remote/SignHeartbeatResponse", nil)
+	cdc.RegisterConcrete(ErrorResponse{}, "tepleton-sdk/remote/ErrorResponse", nil)
+}
@@ -1,10 +1,94 @@
 package keys
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	dbm "github.com/tepleton/tmlibs/db"
+)
+
 // Storage has many implementation, based on security and sharing requirements
 // like disk-backed, mem-backed, vault, db, etc.
+// It holds the encoded Info of every key by name, private keys are only
+// ever stored encrypted inside of the Info.
 type Storage interface {
-	Put(name string, key []byte, info Info) error
-	Get(name string) ([]byte, Info, error)
-	List() (Infos, error)
-	Delete(name string) error
+	// Get returns nil if there is no key stored under name
+	Get(name string) []byte
+	Set(name string, info []byte)
+	Delete(name string)
+	// Names returns the names of all stored keys in alphabetical order
+	Names() []string
+}
+
+var _ Storage = dbStorage{}
+
+// dbStorage stores the keys in a database, as the keybase always did
+type dbStorage struct {
+	db dbm.DB
+}
+
+// NewDBStorage returns a Storage keeping the keys in db
+func NewDBStorage(db dbm.DB) Storage {
+	return dbStorage{db: db}
+}
+
+// NewFileStorage returns a Storage keeping every key in its own file in
+// dir, readable by the owner only
+func NewFileStorage(dir string) Storage {
+	return dbStorage{db: dbm.NewFSDB(dir)}
+}
+
+// NewMemStorage returns a Storage keeping the keys in memory only
+func NewMemStorage() Storage {
+	return dbStorage{db: dbm.NewMemDB()}
+}
+
+func (s dbStorage) Get(name string) []byte {
+	return s.db.Get(infoKey(name))
+}
+
+func (s dbStorage) Set(name string, info []byte) {
+	s.db.SetSync(infoKey(name), info)
+}
+
+func (s dbStorage) Delete(name string) {
+	s.db.DeleteSync(infoKey(name))
+}
+
+func (s dbStorage) Names() []string {
+	var names []string
+	iter := s.db.Iterator(nil, nil)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		key := string(iter.Key())
+		if strings.HasSuffix(key, infoSuffix) {
+			names = append(names, strings.TrimSuffix(key, infoSuffix))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Migrate copies every key of from that is not yet in to, and returns
+// the names of the copied keys. Keys stored by the go-crypto keybase are
+// rewritten in the current format. Keys stay encrypted with their
+// passphrase. Nothing is copied if any key can't be decoded.
+func Migrate(from, to Storage) (migrated []string, err error) {
+	var infos []Info
+	for _, name := range from.Names() {
+		if to.Get(name) != nil {
+			continue
+		}
+		info, err := readInfo(from.Get(name))
+		if err != nil {
+			return nil, fmt.Errorf("can't decode key %s: %v", name, err)
+		}
+		infos = append(infos, info)
+		migrated = append(migrated, name)
+	}
+	for i, info := range infos {
+		to.Set(migrated[i], writeInfo(info))
+	}
+	return migrated, nil
 }
@@ -0,0 +1,81 @@
+package keys
+
+import (
+	tcrypto "github.com/tepleton/tepleton/crypto"
+
+	"github.com/tepleton/tepleton-sdk/crypto/keys/hd"
+)
+
+// TestPassphrase is the passphrase every key of a test keybase is
+// encrypted with, whatever passphrase the caller passes.
+const TestPassphrase = "test"
+
+var _ Keybase = testKeybase{}
+
+// testKeybase ignores all passphrases, so that CI and scripts never have
+// to provide one. Never use it for keys that hold real funds.
+type testKeybase struct {
+	Keybase
+}
+
+// NewTestKeybase creates a keybase on storage that encrypts all keys with
+// TestPassphrase and never asks for a passphrase.
+func NewTestKeybase(storage Storage) Keybase {
+	return testKeybase{NewWithStorage(storage)}
+}
+
+func (kb testKeybase) CreateMnemonic(name string, language Language, _, bip39Passphrase string, algo SigningAlgo) (Info, string, error) {
+	return kb.Keybase.CreateMnemonic(name, language, TestPassphrase, bip39Passphrase, algo)
+}
+
+func (kb testKeybase) CreateKey(name, mnemonic, _ string) (Info, error) {
+	return kb.Keybase.CreateKey(name, mnemonic, TestPassphrase)
+}
+
+func (kb testKeybase) CreateFundraiserKey(name, mnemonic, _ string) (Info, error) {
+	return kb.Keybase.CreateFundraiserKey(name, mnemonic, TestPassphrase)
+}
+
+func (kb testKeybase) Derive(name, mnemonic, _, bip39Passphrase string, params hd.BIP44Params, algo SigningAlgo) (Info, error) {
+	return kb.Keybase.Derive(name, mnemonic, TestPassphrase, bip39Passphrase, params, algo)
+}
+
+func (kb testKeybase) Sign(name, _ string, msg []byte) (tcrypto.Signature, tcrypto.PubKey, error) {
+	return kb.Keybase.Sign(name, TestPassphrase, msg)
+}
+
+func (kb testKeybase) CreateRemote(name, addr, keyName string, signerPubKey tcrypto.PubKey, identity tcrypto.PrivKey, _ string) (Info, error) {
+	return kb.Keybase.CreateRemote(name, addr, keyName, signerPubKey, identity, TestPassphrase)
+}
+
+func (kb testKeybase) ImportPrivKey(name string, priv tcrypto.PrivKey, _ string) (Info, error) {
+	return kb.Keybase.ImportPrivKey(name, priv, TestPassphrase)
+}
+
+func (kb testKeybase) ExportPrivKey(name, _ string) (tcrypto.PrivKey, error) {
+	return kb.Keybase.ExportPrivKey(name, TestPassphrase)
+}
+
+func (kb testKeybase) ImportPrivKeyArmor(name, armor, _ string) error {
+	return kb.Keybase.ImportPrivKeyArmor(name, armor, TestPassphrase)
+}
+
+func (kb testKeybase) ExportPrivKeyArmor(name, _, _ string) (string, error) {
+	return kb.Keybase.ExportPrivKeyArmor(name, TestPassphrase, TestPassphrase)
+}
+
+// Delete still requires 'yes' for offline and Ledger keys.
+func (kb testKeybase) Delete(name, passphrase string) error {
+	info, err := kb.Get(name)
+	if err != nil {
+		return err
+	}
+	if _, ok := info.(localInfo); ok {
+		passphrase = TestPassphrase
+	}
+	return kb.Keybase.Delete(name, passphrase)
+}
+
+func (kb testKeybase) Update(name, _, _ string) error {
+	return kb.Keybase.Update(name, TestPassphrase, TestPassphrase)
+}
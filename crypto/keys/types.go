@@ -1,6 +1,9 @@
 package keys
 
 import (
+	"errors"
+	"sort"
+
 	ccrypto "github.com/tepleton/tepleton-sdk/crypto"
 	"github.com/tepleton/tepleton/crypto"
 
@@ -19,19 +22,22 @@ type Keybase interface {
 	Sign(name, passphrase string, msg []byte) (crypto.Signature, crypto.PubKey, error)
 
 	// CreateMnemonic creates a new mnemonic, and derives a hierarchical deterministic
-	// key from that.
-	CreateMnemonic(name string, language Language, passwd string, algo SigningAlgo) (info Info, seed string, err error)
+	// key from that and the optional BIP 39 passphrase.
+	CreateMnemonic(name string, language Language, passwd, bip39Passphrase string, algo SigningAlgo) (info Info, seed string, err error)
 	// CreateKey takes a mnemonic and derives, a password. This method is temporary
 	CreateKey(name, mnemonic, passwd string) (info Info, err error)
 	// CreateFundraiserKey takes a mnemonic and derives, a password
 	CreateFundraiserKey(name, mnemonic, passwd string) (info Info, err error)
-	// Derive derives a key from the passed mnemonic using a BIP44 path.
-	Derive(name, mnemonic, passwd string, params hd.BIP44Params) (Info, error)
+	// Derive derives a key for algo from the passed mnemonic and optional BIP 39 passphrase using a BIP44 path.
+	Derive(name, mnemonic, passwd, bip39Passphrase string, params hd.BIP44Params, algo SigningAlgo) (Info, error)
 	// Create, store, and return a new Ledger key reference
 	CreateLedger(name string, path ccrypto.DerivationPath, algo SigningAlgo) (info Info, err error)
 
 	// Create, store, and return a new offline key reference
 	CreateOffline(name string, pubkey crypto.PubKey) (info Info, err error)
+	// CreateRemote stores a reference to the key keyName of the remote signer at addr,
+	// connecting as identity, which is stored encrypted with passwd
+	CreateRemote(name, addr, keyName string, signerPubKey crypto.PubKey, identity crypto.PrivKey, passwd string) (info Info, err error)
 
 	// The following operations will *only* work on locally-stored keys
 	Update(name, oldpass, newpass string) error
@@ -39,6 +45,13 @@ type Keybase interface {
 	ImportPubKey(name string, armor string) (err error)
 	Export(name string) (armor string, err error)
 	ExportPubKey(name string) (armor string, err error)
+	ImportPrivKey(name string, priv crypto.PrivKey, passphrase string) (info Info, err error)
+	ExportPrivKey(name, passphrase string) (priv crypto.PrivKey, err error)
+	ImportPrivKeyArmor(name, armor, passphrase string) error
+	ExportPrivKeyArmor(name, decryptPassphrase, encryptPassphrase string) (armor string, err error)
+
+	// SetMetadata replaces the metadata of a stored key of any type
+	SetMetadata(name string, metadata map[string]string) (info Info, err error)
 }
 
 // Info is the publicly exposed information about a keypair
@@ -49,24 +62,57 @@ type Info interface {
 	GetName() string
 	// Public key
 	GetPubKey() crypto.PubKey
+	// Metadata of the key, like notes or tags
+	GetMetadata() map[string]string
+}
+
+// MetadataField is a field of the metadata of a key. The metadata is
+// stored as fields sorted by name as amino doesn't encode maps.
+type MetadataField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// metadataFields sorts metadata into fields, dropping the empty values
+func metadataFields(metadata map[string]string) []MetadataField {
+	var fields []MetadataField
+	for name, value := range metadata {
+		if value != "" {
+			fields = append(fields, MetadataField{name, value})
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+	return fields
+}
+
+// metadataMap returns fields as a map, never nil
+func metadataMap(fields []MetadataField) map[string]string {
+	metadata := make(map[string]string, len(fields))
+	for _, field := range fields {
+		metadata[field.Name] = field.Value
+	}
+	return metadata
 }
 
 var _ Info = &localInfo{}
 var _ Info = &ledgerInfo{}
 var _ Info = &offlineInfo{}
+var _ Info = &remoteInfo{}
 
 // localInfo is the public information about a locally stored key
 type localInfo struct {
-	Name         string        `json:"name"`
-	PubKey       crypto.PubKey `json:"pubkey"`
-	PrivKeyArmor string        `json:"privkey.armor"`
+	Name         string          `json:"name"`
+	PubKey       crypto.PubKey   `json:"pubkey"`
+	PrivKeyArmor string          `json:"privkey.armor"`
+	Metadata     []MetadataField `json:"metadata"`
 }
 
-func newLocalInfo(name string, pub crypto.PubKey, privArmor string) Info {
+func newLocalInfo(name string, pub crypto.PubKey, privArmor string, metadata []MetadataField) Info {
 	return &localInfo{
 		Name:         name,
 		PubKey:       pub,
 		PrivKeyArmor: privArmor,
+		Metadata:     metadata,
 	}
 }
 
@@ -82,11 +128,16 @@ func (i localInfo) GetPubKey() crypto.PubKey {
 	return i.PubKey
 }
 
+func (i localInfo) GetMetadata() map[string]string {
+	return metadataMap(i.Metadata)
+}
+
 // ledgerInfo is the public information about a Ledger key
 type ledgerInfo struct {
-	Name   string                 `json:"name"`
-	PubKey crypto.PubKey          `json:"pubkey"`
-	Path   ccrypto.DerivationPath `json:"path"`
+	Name     string                 `json:"name"`
+	PubKey   crypto.PubKey          `json:"pubkey"`
+	Path     ccrypto.DerivationPath `json:"path"`
+	Metadata []MetadataField        `json:"metadata"`
 }
 
 func newLedgerInfo(name string, pub crypto.PubKey, path ccrypto.DerivationPath) Info {
@@ -109,10 +160,15 @@ func (i ledgerInfo) GetPubKey() crypto.PubKey {
 	return i.PubKey
 }
 
+func (i ledgerInfo) GetMetadata() map[string]string {
+	return metadataMap(i.Metadata)
+}
+
 // offlineInfo is the public information about an offline key
 type offlineInfo struct {
-	Name   string        `json:"name"`
-	PubKey crypto.PubKey `json:"pubkey"`
+	Name     string          `json:"name"`
+	PubKey   crypto.PubKey   `json:"pubkey"`
+	Metadata []MetadataField `json:"metadata"`
 }
 
 func newOfflineInfo(name string, pub crypto.PubKey) Info {
@@ -134,13 +190,88 @@ func (i offlineInfo) GetPubKey() crypto.PubKey {
 	return i.PubKey
 }
 
+func (i offlineInfo) GetMetadata() map[string]string {
+	return metadataMap(i.Metadata)
+}
+
+// remoteInfo is the public information about a key held by a remote signer
+type remoteInfo struct {
+	Name          string          `json:"name"`
+	PubKey        crypto.PubKey   `json:"pubkey"`
+	Addr          string          `json:"addr"`
+	KeyName       string          `json:"key_name"`
+	SignerPubKey  crypto.PubKey   `json:"signer_pubkey"`
+	IdentityArmor string          `json:"identity.armor"`
+	Metadata      []MetadataField `json:"metadata"`
+}
+
+func newRemoteInfo(name string, pub crypto.PubKey, addr, keyName string, signerPub crypto.PubKey, identityArmor string) Info {
+	return &remoteInfo{
+		Name:          name,
+		PubKey:        pub,
+		Addr:          addr,
+		KeyName:       keyName,
+		SignerPubKey:  signerPub,
+		IdentityArmor: identityArmor,
+	}
+}
+
+func (i remoteInfo) GetType() string {
+	return "remote"
+}
+
+func (i remoteInfo) GetName() string {
+	return i.Name
+}
+
+func (i remoteInfo) GetPubKey() crypto.PubKey {
+	return i.PubKey
+}
+
+func (i remoteInfo) GetMetadata() map[string]string {
+	return metadataMap(i.Metadata)
+}
+
 // encoding info
 func writeInfo(i Info) []byte {
 	return cdc.MustMarshalBinary(i)
 }
 
-// decoding info
+// decoding info, in the current format or in the format of the go-crypto
+// keybase
 func readInfo(bz []byte) (info Info, err error) {
 	err = cdc.UnmarshalBinary(bz, &info)
-	return
+	if err == nil {
+		return info, nil
+	}
+	if legacy, legacyErr := readLegacyInfo(bz); legacyErr == nil {
+		return legacy, nil
+	}
+	return nil, err
+}
+
+// legacyInfo is the info of the keys stored by the go-crypto keybase,
+// which the SDK used before crypto/keys. It was encoded bare, and all keys
+// were local keys with a private key in the legacy format.
+type legacyInfo struct {
+	Name         string        `json:"name"`
+	PubKey       crypto.PubKey `json:"pubkey"`
+	PrivKeyArmor string        `json:"privkey.armor"`
+}
+
+// readLegacyInfo decodes the info of a key stored by the go-crypto keybase
+func readLegacyInfo(bz []byte) (Info, error) {
+	var legacy legacyInfo
+	err := cdc.UnmarshalBinaryBare(bz, &legacy)
+	if err != nil {
+		return nil, err
+	}
+	if legacy.PubKey == nil || legacy.PrivKeyArmor == "" {
+		return nil, errors.New("incomplete go-crypto key info")
+	}
+	return localInfo{
+		Name:         legacy.Name,
+		PubKey:       legacy.PubKey,
+		PrivKeyArmor: legacy.PrivKeyArmor,
+	}, nil
 }
@@ -11,9 +11,9 @@ var cdc = amino.NewCodec()
 func init() {
 	tcrypto.RegisterAmino(cdc)
 	cdc.RegisterInterface((*Info)(nil), nil)
-	cdc.RegisterConcrete(ccrypto.PrivKeyLedgerSecp256k1{},
-		"tepleton/PrivKeyLedgerSecp256k1", nil)
+	ccrypto.RegisterAmino(cdc)
 	cdc.RegisterConcrete(localInfo{}, "crypto/keys/localInfo", nil)
 	cdc.RegisterConcrete(ledgerInfo{}, "crypto/keys/ledgerInfo", nil)
 	cdc.RegisterConcrete(offlineInfo{}, "crypto/keys/offlineInfo", nil)
+	cdc.RegisterConcrete(remoteInfo{}, "crypto/keys/remoteInfo", nil)
 }
@@ -0,0 +1,203 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"math/big"
+
+	"golang.org/x/crypto/ripemd160"
+
+	tcrypto "github.com/tepleton/tepleton/crypto"
+)
+
+var (
+	_ tcrypto.PrivKey   = PrivKeySecp256r1{}
+	_ tcrypto.PubKey    = PubKeySecp256r1{}
+	_ tcrypto.Signature = SignatureSecp256r1{}
+)
+
+// p256HalfOrder is half the order of the curve, signatures must have an s
+// below it so they can't be altered into another valid signature
+var p256HalfOrder = new(big.Int).Rsh(elliptic.P256().Params().N, 1)
+
+// PrivKeySecp256r1 is an ECDSA private key on the NIST P-256 curve,
+// the big endian scalar.
+type PrivKeySecp256r1 [32]byte
+
+// GenPrivKeySecp256r1 generates a new random secp256r1 private key
+func GenPrivKeySecp256r1() PrivKeySecp256r1 {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	var privKey PrivKeySecp256r1
+	fillBytes(key.D, privKey[:])
+	return privKey
+}
+
+// Bytes fulfils PrivKey Interface
+func (privKey PrivKeySecp256r1) Bytes() []byte {
+	return cdc.MustMarshalBinaryBare(privKey)
+}
+
+// Sign signs the SHA256 of msg. The signature is r || s, each 32 bytes,
+// with s in the lower half of the curve order.
+func (privKey PrivKeySecp256r1) Sign(msg []byte) (tcrypto.Signature, error) {
+	hash := sha256.Sum256(msg)
+	r, s, err := ecdsa.Sign(rand.Reader, privKey.ecdsa(), hash[:])
+	if err != nil {
+		return nil, err
+	}
+	if s.Cmp(p256HalfOrder) > 0 {
+		s.Sub(elliptic.P256().Params().N, s)
+	}
+	sig := make([]byte, 64)
+	fillBytes(r, sig[:32])
+	fillBytes(s, sig[32:])
+	return SignatureSecp256r1(sig), nil
+}
+
+// PubKey returns the compressed public key
+func (privKey PrivKeySecp256r1) PubKey() tcrypto.PubKey {
+	key := privKey.ecdsa()
+	var pubKey PubKeySecp256r1
+	pubKey[0] = byte(2 + key.Y.Bit(0))
+	fillBytes(key.X, pubKey[1:])
+	return pubKey
+}
+
+// Equals fulfils PrivKey Interface, in constant time
+func (privKey PrivKeySecp256r1) Equals(other tcrypto.PrivKey) bool {
+	if otherSecp, ok := other.(PrivKeySecp256r1); ok {
+		return subtle.ConstantTimeCompare(privKey[:], otherSecp[:]) == 1
+	}
+	return false
+}
+
+func (privKey PrivKeySecp256r1) ecdsa() *ecdsa.PrivateKey {
+	curve := elliptic.P256()
+	key := &ecdsa.PrivateKey{D: new(big.Int).SetBytes(privKey[:])}
+	key.Curve = curve
+	key.X, key.Y = curve.ScalarBaseMult(privKey[:])
+	return key
+}
+
+// PubKeySecp256r1 is a compressed secp256r1 public key, the x coordinate
+// prefixed with 0x02 or 0x03 depending on the y coordinate.
+type PubKeySecp256r1 [33]byte
+
+// Address is RIPEMD160(SHA256(pubkey)), as for secp256k1 keys
+func (pubKey PubKeySecp256r1) Address() tcrypto.Address {
+	sha := sha256.Sum256(pubKey[:])
+	hasherRIPEMD160 := ripemd160.New()
+	hasherRIPEMD160.Write(sha[:]) // does not error
+	return tcrypto.Address(hasherRIPEMD160.Sum(nil))
+}
+
+// Bytes fulfils PubKey Interface
+func (pubKey PubKeySecp256r1) Bytes() []byte {
+	return cdc.MustMarshalBinaryBare(pubKey)
+}
+
+// VerifyBytes verifies a signature made by PrivKeySecp256r1.Sign, and
+// refuses signatures with a high s.
+func (pubKey PubKeySecp256r1) VerifyBytes(msg []byte, sig tcrypto.Signature) bool {
+	sigSecp, ok := sig.(SignatureSecp256r1)
+	if !ok || len(sigSecp) != 64 {
+		return false
+	}
+	curve := elliptic.P256()
+	x, y := unmarshalCompressed(pubKey)
+	if x == nil {
+		return false
+	}
+	r := new(big.Int).SetBytes(sigSecp[:32])
+	s := new(big.Int).SetBytes(sigSecp[32:])
+	if s.Cmp(p256HalfOrder) > 0 {
+		return false
+	}
+	hash := sha256.Sum256(msg)
+	return ecdsa.Verify(&ecdsa.PublicKey{Curve: curve, X: x, Y: y}, hash[:], r, s)
+}
+
+func (pubKey PubKeySecp256r1) String() string {
+	return fmt.Sprintf("PubKeySecp256r1{%X}", pubKey[:])
+}
+
+// Equals fulfils PubKey Interface
+func (pubKey PubKeySecp256r1) Equals(other tcrypto.PubKey) bool {
+	if otherSecp, ok := other.(PubKeySecp256r1); ok {
+		return bytes.Equal(pubKey[:], otherSecp[:])
+	}
+	return false
+}
+
+// SignatureSecp256r1 is a secp256r1 signature, r || s.
+type SignatureSecp256r1 []byte
+
+// Bytes fulfils Signature Interface
+func (sig SignatureSecp256r1) Bytes() []byte {
+	return cdc.MustMarshalBinaryBare(sig)
+}
+
+// IsZero fulfils Signature Interface
+func (sig SignatureSecp256r1) IsZero() bool { return len(sig) == 0 }
+
+func (sig SignatureSecp256r1) String() string {
+	if len(sig) < 6 {
+		return fmt.Sprintf("/%X/", []byte(sig))
+	}
+	return fmt.Sprintf("/%X.../", []byte(sig[:6]))
+}
+
+// Equals fulfils Signature Interface
+func (sig SignatureSecp256r1) Equals(other tcrypto.Signature) bool {
+	if otherSecp, ok := other.(SignatureSecp256r1); ok {
+		return subtle.ConstantTimeCompare(sig[:], otherSecp[:]) == 1
+	}
+	return false
+}
+
+//-------------------------------------
+
+// unmarshalCompressed returns the point of a compressed public key, nil if
+// it isn't on the curve
+func unmarshalCompressed(pubKey PubKeySecp256r1) (x, y *big.Int) {
+	if pubKey[0] != 2 && pubKey[0] != 3 {
+		return nil, nil
+	}
+	params := elliptic.P256().Params()
+	x = new(big.Int).SetBytes(pubKey[1:])
+	if x.Cmp(params.P) >= 0 {
+		return nil, nil
+	}
+	// y² = x³ - 3x + b
+	y = new(big.Int).Mul(x, x)
+	y.Mul(y, x)
+	threeX := new(big.Int).Lsh(x, 1)
+	threeX.Add(threeX, x)
+	y.Sub(y, threeX)
+	y.Add(y, params.B)
+	y.Mod(y, params.P)
+	if y.ModSqrt(y, params.P) == nil {
+		return nil, nil
+	}
+	if byte(y.Bit(0)) != pubKey[0]&1 {
+		y.Sub(params.P, y)
+	}
+	return x, y
+}
+
+// fillBytes sets buf to the big endian bytes of n, zero padded
+func fillBytes(n *big.Int, buf []byte) {
+	for i := range buf {
+		buf[i] = 0
+	}
+	bz := n.Bytes()
+	copy(buf[len(buf)-len(bz):], bz)
+}
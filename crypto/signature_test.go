@@ -44,3 +44,25 @@ func TestSignAndValidateSecp256k1(t *testing.T) {
 
 	assert.False(t, pubKey.VerifyBytes(msg, sig))
 }
+
+func TestSignAndValidateSecp256r1(t *testing.T) {
+	privKey := GenPrivKeySecp256r1()
+	pubKey := privKey.PubKey()
+
+	msg := CRandBytes(128)
+	sig, err := privKey.Sign(msg)
+	require.Nil(t, err)
+
+	assert.True(t, pubKey.VerifyBytes(msg, sig))
+
+	// Mutate the signature, just one bit.
+	sigR1 := sig.(SignatureSecp256r1)
+	sigR1[3] ^= byte(0x01)
+	sig = sigR1
+
+	assert.False(t, pubKey.VerifyBytes(msg, sig))
+
+	// short signatures are printed whole
+	assert.Equal(t, "/0102/", SignatureSecp256r1{1, 2}.String())
+	assert.Equal(t, "//", SignatureSecp256r1{}.String())
+}
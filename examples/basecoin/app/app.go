@@ -82,6 +82,10 @@ func NewBasecoinApp(logger log.Logger, db dbm.DB) *BasecoinApp {
 		AddRoute("ibc", ibc.NewHandler(app.ibcMapper, app.coinKeeper)).
 		AddRoute("stake", stake.NewHandler(app.stakeKeeper))
 
+	// Register the invariants checked at the end of blocks.
+	bank.RegisterInvariants(app, app.accountMapper)
+	stake.RegisterInvariants(app, app.stakeKeeper)
+
 	// Initialize BaseApp.
 	app.SetInitChainer(app.initChainer)
 	app.SetBeginBlocker(app.BeginBlocker)
@@ -151,14 +155,26 @@ func (app *BasecoinApp) initChainer(ctx sdk.Context, req wrsp.RequestInitChain)
 	}
 
 	// load the initial stake information
-	stake.InitGenesis(ctx, app.stakeKeeper, genesisState.StakeData)
+	err = stake.InitGenesis(ctx, app.stakeKeeper, genesisState.StakeData)
+	if err != nil {
+		panic(err) // TODO https://github.com/tepleton/tepleton-sdk/issues/468
+		// return sdk.ErrGenesisParse("").TraceCause(err, "")
+	}
 
 	return wrsp.ResponseInitChain{}
 }
 
+// load a particular height, to export the state at it
+func (app *BasecoinApp) LoadHeight(height int64) error {
+	return app.LoadVersion(height, app.keyMain)
+}
+
 // Custom logic for state export
-func (app *BasecoinApp) ExportAppStateAndValidators() (appState json.RawMessage, validators []tmtypes.GenesisValidator, err error) {
-	ctx := app.NewContext(true, wrsp.Header{})
+func (app *BasecoinApp) ExportAppStateAndValidators(forZeroHeight bool) (appState json.RawMessage, validators []tmtypes.GenesisValidator, err error) {
+	ctx := app.NewContext(true, wrsp.Header{Height: app.LastBlockHeight()})
+	if forZeroHeight {
+		stake.PrepForZeroHeightGenesis(ctx, app.stakeKeeper)
+	}
 
 	// iterate to get the accounts
 	accounts := []*types.GenesisAccount{}
@@ -40,7 +40,13 @@ func newApp(logger log.Logger, db dbm.DB) wrsp.Application {
 	return app.NewBasecoinApp(logger, db)
 }
 
-func exportAppStateAndTMValidators(logger log.Logger, db dbm.DB) (json.RawMessage, []tmtypes.GenesisValidator, error) {
+func exportAppStateAndTMValidators(logger log.Logger, db dbm.DB, height int64, forZeroHeight bool) (json.RawMessage, []tmtypes.GenesisValidator, error) {
 	bapp := app.NewBasecoinApp(logger, db)
-	return bapp.ExportAppStateAndValidators()
+	if height != -1 {
+		err := bapp.LoadHeight(height)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return bapp.ExportAppStateAndValidators(forZeroHeight)
 }
@@ -154,9 +154,15 @@ func (app *DemocoinApp) initChainerFn(coolKeeper cool.Keeper, powKeeper pow.Keep
 	}
 }
 
-// Custom logic for state export
-func (app *DemocoinApp) ExportAppStateAndValidators() (appState json.RawMessage, validators []tmtypes.GenesisValidator, err error) {
-	ctx := app.NewContext(true, wrsp.Header{})
+// load a particular height, to export the state at it
+func (app *DemocoinApp) LoadHeight(height int64) error {
+	return app.LoadVersion(height, app.capKeyMainStore)
+}
+
+// Custom logic for state export. None of the democoin modules keep height
+// dependent state, so forZeroHeight needs no preparation.
+func (app *DemocoinApp) ExportAppStateAndValidators(forZeroHeight bool) (appState json.RawMessage, validators []tmtypes.GenesisValidator, err error) {
+	ctx := app.NewContext(true, wrsp.Header{Height: app.LastBlockHeight()})
 
 	// iterate to get the accounts
 	accounts := []*types.GenesisAccount{}
@@ -47,9 +47,15 @@ func newApp(logger log.Logger, db dbm.DB) wrsp.Application {
 	return app.NewDemocoinApp(logger, db)
 }
 
-func exportAppStateAndTMValidators(logger log.Logger, db dbm.DB) (json.RawMessage, []tmtypes.GenesisValidator, error) {
+func exportAppStateAndTMValidators(logger log.Logger, db dbm.DB, height int64, forZeroHeight bool) (json.RawMessage, []tmtypes.GenesisValidator, error) {
 	dapp := app.NewDemocoinApp(logger, db)
-	return dapp.ExportAppStateAndValidators()
+	if height != -1 {
+		err := dapp.LoadHeight(height)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return dapp.ExportAppStateAndValidators(forZeroHeight)
 }
 
 func main() {
@@ -2,20 +2,31 @@ package server
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
+	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
 
 	wrsp "github.com/tepleton/wrsp/types"
 	tmtypes "github.com/tepleton/tepleton/types"
 	dbm "github.com/tepleton/tmlibs/db"
 	"github.com/tepleton/tmlibs/log"
+
+	"github.com/tepleton/tepleton-sdk/baseapp"
+	"github.com/tepleton/tepleton-sdk/store/streaming"
 )
 
 // AppCreator lets us lazily initialize app, using home dir
 // and other flags (?) to start
 type AppCreator func(string, log.Logger) (wrsp.Application, error)
 
-// AppExporter dumps all app state to JSON-serializable structure and returns the current validator set
-type AppExporter func(home string, log log.Logger) (json.RawMessage, []tmtypes.GenesisValidator, error)
+// AppExporter dumps all app state to JSON-serializable structure and returns the current validator set.
+// A height of -1 exports the latest height, forZeroHeight prepares the state
+// to start a new chain from it at height zero.
+type AppExporter func(home string, log log.Logger, height int64, forZeroHeight bool) (json.RawMessage, []tmtypes.GenesisValidator, error)
 
 // ConstructAppCreator returns an application generation function
 func ConstructAppCreator(appFn func(log.Logger, dbm.DB) wrsp.Application, name string) AppCreator {
@@ -26,18 +37,108 @@ func ConstructAppCreator(appFn func(log.Logger, dbm.DB) wrsp.Application, name s
 			return nil, err
 		}
 		app := appFn(logger, db)
+		err = enableStreaming(app)
+		if err != nil {
+			return nil, err
+		}
+		err = enableStoreTracing(app)
+		if err != nil {
+			return nil, err
+		}
+		err = enableInvariantChecks(app)
+		if err != nil {
+			return nil, err
+		}
 		return app, nil
 	}
 }
 
+// invariantApp is an app with invariants, such as any app built on the
+// BaseApp
+type invariantApp interface {
+	SetInvariantCheckPeriod(period int64)
+	CheckInvariants() error
+}
+
+// enableInvariantChecks checks the invariants of app at the end of blocks
+// if configured with --inv-check-period
+func enableInvariantChecks(app wrsp.Application) error {
+	period := viper.GetInt64(flagInvCheckPeriod)
+	if period == 0 {
+		return nil
+	}
+	iApp, ok := app.(invariantApp)
+	if !ok {
+		return fmt.Errorf("the app doesn't support --%s", flagInvCheckPeriod)
+	}
+	if period < 0 {
+		return fmt.Errorf("--%s can't be negative", flagInvCheckPeriod)
+	}
+	iApp.SetInvariantCheckPeriod(period)
+	return nil
+}
+
+// tracingApp is an app that can trace its store operations, such as any
+// app built on the BaseApp
+type tracingApp interface {
+	SetCommitMultiStoreTracer(w io.Writer)
+}
+
+// enableStoreTracing traces the store operations of app to a file if
+// configured with --trace-store
+func enableStoreTracing(app wrsp.Application) error {
+	path := viper.GetString(flagTraceStore)
+	if path == "" {
+		return nil
+	}
+	tApp, ok := app.(tracingApp)
+	if !ok {
+		return fmt.Errorf("the app doesn't support --%s", flagTraceStore)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	tApp.SetCommitMultiStoreTracer(file)
+	return nil
+}
+
+// streamingApp is an app that can stream its state changes, such as any
+// app built on the BaseApp
+type streamingApp interface {
+	SetStreamingService(s baseapp.StreamingService, storeNames ...string) error
+}
+
+// enableStreaming streams the state changes of app to a file if
+// configured with --streaming-dir
+func enableStreaming(app wrsp.Application) error {
+	dir := viper.GetString(flagStreamingDir)
+	if dir == "" {
+		return nil
+	}
+	sApp, ok := app.(streamingApp)
+	if !ok {
+		return fmt.Errorf("the app doesn't support --%s", flagStreamingDir)
+	}
+	var storeNames []string
+	if stores := viper.GetString(flagStreamingStores); stores != "" {
+		storeNames = strings.Split(stores, ",")
+	}
+	service, err := streaming.NewFileService(dir)
+	if err != nil {
+		return err
+	}
+	return sApp.SetStreamingService(service, storeNames...)
+}
+
 // ConstructAppExporter returns an application export function
-func ConstructAppExporter(appFn func(log.Logger, dbm.DB) (json.RawMessage, []tmtypes.GenesisValidator, error), name string) AppExporter {
-	return func(rootDir string, logger log.Logger) (json.RawMessage, []tmtypes.GenesisValidator, error) {
+func ConstructAppExporter(appFn func(log.Logger, dbm.DB, int64, bool) (json.RawMessage, []tmtypes.GenesisValidator, error), name string) AppExporter {
+	return func(rootDir string, logger log.Logger, height int64, forZeroHeight bool) (json.RawMessage, []tmtypes.GenesisValidator, error) {
 		dataDir := filepath.Join(rootDir, "data")
 		db, err := dbm.NewGoLevelDB(name, dataDir)
 		if err != nil {
 			return nil, nil, err
 		}
-		return appFn(logger, db)
+		return appFn(logger, db, height, forZeroHeight)
 	}
 }
@@ -11,14 +11,21 @@ import (
 	tmtypes "github.com/tepleton/tepleton/types"
 )
 
+const (
+	flagHeight        = "height"
+	flagForZeroHeight = "for-zero-height"
+)
+
 // ExportCmd dumps app state to JSON
 func ExportCmd(ctx *Context, cdc *wire.Codec, appExporter AppExporter) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "export",
 		Short: "Export state to JSON",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			home := viper.GetString("home")
-			appState, validators, err := appExporter(home, ctx.Logger)
+			height := viper.GetInt64(flagHeight)
+			forZeroHeight := viper.GetBool(flagForZeroHeight)
+			appState, validators, err := appExporter(home, ctx.Logger, height, forZeroHeight)
 			if err != nil {
 				return errors.Errorf("Error exporting state: %v\n", err)
 			}
@@ -36,4 +43,7 @@ func ExportCmd(ctx *Context, cdc *wire.Codec, appExporter AppExporter) *cobra.Co
 			return nil
 		},
 	}
+	cmd.Flags().Int64(flagHeight, -1, "Export state from a particular height (-1 means latest height)")
+	cmd.Flags().Bool(flagForZeroHeight, false, "Export state to start a new chain at height zero (resets heights and settles pending unbondings)")
+	return cmd
 }
@@ -0,0 +1,169 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	tmtypes "github.com/tepleton/tepleton/types"
+
+	"github.com/tepleton/tepleton-sdk/wire"
+)
+
+const (
+	flagGenesisTime = "genesis-time"
+)
+
+// MigrationCallback converts the genesis state of a module from the format
+// of the previous app version to the format of the version it's registered for
+type MigrationCallback func(cdc *wire.Codec, moduleState json.RawMessage) (json.RawMessage, error)
+
+// MigrationMap holds the migration callbacks of the modules by app version,
+// then by the key of the module in the app state
+type MigrationMap map[string]map[string]MigrationCallback
+
+// Register adds the migration of module to version
+func (m MigrationMap) Register(version, module string, callback MigrationCallback) {
+	if _, ok := m[version]; !ok {
+		m[version] = make(map[string]MigrationCallback)
+	}
+	m[version][module] = callback
+}
+
+// Versions returns the versions migrations are registered for, sorted by
+// their numeric components, so v0.9 comes before v0.10
+func (m MigrationMap) Versions() []string {
+	versions := make([]string, 0, len(m))
+	for version := range m {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versionLess(versions[i], versions[j])
+	})
+	return versions
+}
+
+// versionLess compares versions of the form v1.2.3 component by component.
+// Components which aren't numbers are compared as strings.
+func versionLess(a, b string) bool {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] == bs[i] {
+			continue
+		}
+		an, aErr := strconv.ParseUint(as[i], 10, 64)
+		bn, bErr := strconv.ParseUint(bs[i], 10, 64)
+		if aErr == nil && bErr == nil {
+			return an < bn
+		}
+		return as[i] < bs[i]
+	}
+	return len(as) < len(bs)
+}
+
+// Migrate converts appState to the format of version. The state of modules
+// without a migration for version is kept as is.
+func (m MigrationMap) Migrate(cdc *wire.Codec, version string, appState json.RawMessage) (json.RawMessage, error) {
+	callbacks, ok := m[version]
+	if !ok {
+		return nil, fmt.Errorf("unknown version %s, known versions are %v", version, m.Versions())
+	}
+	var modules map[string]json.RawMessage
+	err := cdc.UnmarshalJSON(appState, &modules)
+	if err != nil {
+		return nil, err
+	}
+	for module, callback := range callbacks {
+		moduleState, ok := modules[module]
+		if !ok {
+			continue
+		}
+		migrated, err := callback(cdc, moduleState)
+		if err != nil {
+			return nil, fmt.Errorf("failed to migrate %s: %v", module, err)
+		}
+		modules[module] = migrated
+	}
+	bz, err := wire.MarshalJSONIndent(cdc, modules)
+	return json.RawMessage(bz), err
+}
+
+// ValidateGenesisCmd checks a genesis file, by default the one of the node
+func ValidateGenesisCmd(ctx *Context, cdc *wire.Codec, appInit AppInit) *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate-genesis [genesis-file]",
+		Short: "Validate the genesis file, by default [--home]/config/genesis.json",
+		Args:  cobra.RangeArgs(0, 1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			genFile := ctx.Config.GenesisFile()
+			if len(args) == 1 {
+				genFile = args[0]
+			}
+
+			// also validates the tepleton part of the genesis
+			doc, err := tmtypes.GenesisDocFromFile(genFile)
+			if err != nil {
+				return fmt.Errorf("error loading genesis file %s: %v", genFile, err)
+			}
+			if appInit.ValidateAppState == nil {
+				return fmt.Errorf("the app doesn't support genesis validation")
+			}
+			err = appInit.ValidateAppState(cdc, doc.AppStateJSON)
+			if err != nil {
+				return fmt.Errorf("error validating genesis file %s: %v", genFile, err)
+			}
+			fmt.Printf("File at %s is a valid genesis file\n", genFile)
+			return nil
+		},
+	}
+}
+
+// MigrateGenesisCmd converts a genesis file to the format of a newer app
+// version and prints it
+func MigrateGenesisCmd(ctx *Context, cdc *wire.Codec, appInit AppInit) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate [target-version] [genesis-file]",
+		Short: "Migrate a genesis file to the format of the target version",
+		Long: fmt.Sprintf(`Migrate the genesis file from the format of the previous version to
+the format of the target version, and print it. Known versions: %v`, appInit.Migrations.Versions()),
+		Args: cobra.ExactArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			version, genFile := args[0], args[1]
+			doc, err := tmtypes.GenesisDocFromFile(genFile)
+			if err != nil {
+				return fmt.Errorf("error loading genesis file %s: %v", genFile, err)
+			}
+			doc.AppStateJSON, err = appInit.Migrations.Migrate(cdc, version, doc.AppStateJSON)
+			if err != nil {
+				return err
+			}
+
+			if chainID := viper.GetString(flagChainID); chainID != "" {
+				doc.ChainID = chainID
+			}
+			if genTime := viper.GetString(flagGenesisTime); genTime != "" {
+				doc.GenesisTime, err = time.Parse(time.RFC3339, genTime)
+				if err != nil {
+					return err
+				}
+			}
+
+			encoded, err := wire.MarshalJSONIndent(cdc, doc)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(encoded))
+			return nil
+		},
+	}
+	cmd.Flags().String(flagChainID, "", "override the chain-id of the genesis file")
+	cmd.Flags().String(flagGenesisTime, "", "override the genesis time of the genesis file (RFC3339)")
+	return cmd
+}
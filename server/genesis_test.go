@@ -0,0 +1,46 @@
+package server
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tepleton/tepleton-sdk/wire"
+)
+
+func TestMigrationMap(t *testing.T) {
+	cdc := wire.NewCodec()
+	migrations := make(MigrationMap)
+	migrations.Register("v2", "bank", func(cdc *wire.Codec, state json.RawMessage) (json.RawMessage, error) {
+		return json.RawMessage(`{"send_enabled":true}`), nil
+	})
+	migrations.Register("v1", "bank", func(cdc *wire.Codec, state json.RawMessage) (json.RawMessage, error) {
+		return state, nil
+	})
+	migrations.Register("v10", "bank", func(cdc *wire.Codec, state json.RawMessage) (json.RawMessage, error) {
+		return state, nil
+	})
+	require.Equal(t, []string{"v1", "v2", "v10"}, migrations.Versions())
+
+	appState := json.RawMessage(`{"bank":{},"stake":{"bonds":[]}}`)
+	migrated, err := migrations.Migrate(cdc, "v2", appState)
+	require.Nil(t, err)
+	var modules map[string]json.RawMessage
+	err = json.Unmarshal(migrated, &modules)
+	require.Nil(t, err)
+	require.JSONEq(t, `{"send_enabled":true}`, string(modules["bank"]))
+	require.JSONEq(t, `{"bonds":[]}`, string(modules["stake"]))
+
+	_, err = migrations.Migrate(cdc, "v3", appState)
+	require.NotNil(t, err)
+}
+
+func TestVersionLess(t *testing.T) {
+	versions := []string{"v0.20", "v0.9", "v0.20.1", "v1.0", "v0.19"}
+	sort.Slice(versions, func(i, j int) bool {
+		return versionLess(versions[i], versions[j])
+	})
+	require.Equal(t, []string{"v0.9", "v0.19", "v0.20", "v0.20.1", "v1.0"}, versions)
+}
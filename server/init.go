@@ -4,10 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"os"
 	"path"
 	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -15,17 +15,15 @@ import (
 	"github.com/spf13/viper"
 
 	crypto "github.com/tepleton/go-crypto"
-	"github.com/tepleton/go-crypto/keys"
-	"github.com/tepleton/go-crypto/keys/words"
 	cfg "github.com/tepleton/tepleton/config"
 	"github.com/tepleton/tepleton/p2p"
 	tmtypes "github.com/tepleton/tepleton/types"
 	pvm "github.com/tepleton/tepleton/privval"
 	tmcli "github.com/tepleton/tmlibs/cli"
 	cmn "github.com/tepleton/tmlibs/common"
-	dbm "github.com/tepleton/tmlibs/db"
 
 	clkeys "github.com/tepleton/tepleton-sdk/client/keys"
+	"github.com/tepleton/tepleton-sdk/crypto/keys"
 	sdk "github.com/tepleton/tepleton-sdk/types"
 	"github.com/tepleton/tepleton-sdk/wire"
 )
@@ -43,6 +41,7 @@ var (
 	flagGenTxs    = "gen-txs"
 	flagIP        = "ip"
 	flagChainID   = "chain-id"
+	flagGenTxDir  = "gentx-dir"
 )
 
 // get cmd to initialize all files for tepleton and application
@@ -114,6 +113,7 @@ func GenTxCmd(ctx *Context, cdc *wire.Codec, appInit AppInit) *cobra.Command {
 		},
 	}
 	cmd.Flags().String(flagIP, "", "external facing IP to use if left blank IP will be retrieved from this machine")
+	cmd.Flags().String(flagChainID, "", "chain-id of the network, genesis transactions are signed for it")
 	cmd.Flags().AddFlagSet(appInit.FlagsAppGenTx)
 	return cmd
 }
@@ -137,6 +137,7 @@ func InitCmd(ctx *Context, cdc *wire.Codec, appInit AppInit) *cobra.Command {
 			chainID := viper.GetString(flagChainID)
 			if chainID == "" {
 				chainID = cmn.Fmt("test-chain-%v", cmn.RandStr(6))
+				viper.Set(flagChainID, chainID)
 			}
 
 			genFile := config.GenesisFile()
@@ -204,6 +205,142 @@ func InitCmd(ctx *Context, cdc *wire.Codec, appInit AppInit) *cobra.Command {
 	cmd.Flags().AddFlagSet(appInit.FlagsAppGenState)
 	cmd.Flags().AddFlagSet(appInit.FlagsAppGenTx) // need to add this flagset for when no GenTx's provided
 	cmd.AddCommand(GenTxCmd(ctx, cdc, appInit))
+	cmd.AddCommand(AddGenesisAccountCmd(ctx, cdc, appInit))
+	cmd.AddCommand(CollectGenTxsCmd(ctx, cdc, appInit))
+	return cmd
+}
+
+// AddGenesisAccountCmd adds an account to the genesis file. The coordinator
+// of a multi-party network funds the account of every participant with it,
+// before collecting their genesis transactions, which bond coins of it.
+func AddGenesisAccountCmd(ctx *Context, cdc *wire.Codec, appInit AppInit) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add-genesis-account [address] [coins]",
+		Short: "Add an account holding coins to the genesis file",
+		Long: `Add an account holding coins to the genesis file.
+
+To create a multi-party network, the coordinator adds the account of every
+participant with the coins it bonds, and shares the genesis file. Each
+participant signs its validator with the key of its account:
+
+    init gen-tx --name=<key name> --chain-id=<chain-id>
+
+and sends the gentx file to the coordinator, who applies them all with
+collect-gentxs and shares the final genesis file.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if appInit.AddGenesisAccount == nil {
+				return errors.New("the app doesn't support adding genesis accounts")
+			}
+			addr, err := sdk.GetAccAddressBech32(args[0])
+			if err != nil {
+				return err
+			}
+			coins, err := sdk.ParseCoins(args[1])
+			if err != nil {
+				return err
+			}
+
+			genFile := ctx.Config.GenesisFile()
+			doc, err := tmtypes.GenesisDocFromFile(genFile)
+			if err != nil {
+				return err
+			}
+			doc.AppStateJSON, err = appInit.AddGenesisAccount(cdc, doc.AppStateJSON, addr, coins)
+			if err != nil {
+				return err
+			}
+			out, err := wire.MarshalJSONIndent(cdc, doc)
+			if err != nil {
+				return err
+			}
+			return ioutil.WriteFile(genFile, out, 0600)
+		},
+	}
+	return cmd
+}
+
+// CollectGenTxsCmd creates the genesis of a multi-party network: the
+// signed genesis transactions of all participants are checked against the
+// accounts of the existing genesis file, and the genesis validators and
+// persistent peers are set from them.
+func CollectGenTxsCmd(ctx *Context, cdc *wire.Codec, appInit AppInit) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "collect-gentxs",
+		Short: "Verify the signed genesis transactions of [--gentx-dir] and add them to the genesis file",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if appInit.CollectGenTxs == nil {
+				return errors.New("the app doesn't support collecting signed genesis transactions")
+			}
+
+			config := ctx.Config
+			genFile := config.GenesisFile()
+			doc, err := tmtypes.GenesisDocFromFile(genFile)
+			if err != nil {
+				return err
+			}
+
+			genTxsDir := viper.GetString(flagGenTxDir)
+			if genTxsDir == "" {
+				genTxsDir = filepath.Join(viper.GetString(tmcli.HomeFlag), "config", "gentx")
+			}
+			genTxs, err := readGenTxs(genTxsDir, cdc)
+			if err != nil {
+				return err
+			}
+			if len(genTxs) == 0 {
+				return fmt.Errorf("no genesis transactions in %s", genTxsDir)
+			}
+			appGenTxs := make([]json.RawMessage, len(genTxs))
+			for i, genTx := range genTxs {
+				appGenTxs[i] = genTx.AppGenTx
+			}
+
+			// the validators are taken from the verified transactions
+			// rather than from the unsigned part of the files, and join
+			// those the genesis already has
+			var validators []tmtypes.GenesisValidator
+			doc.AppStateJSON, validators, err = appInit.CollectGenTxs(cdc, doc.ChainID, doc.AppStateJSON, appGenTxs)
+			if err != nil {
+				return err
+			}
+			doc.Validators = append(doc.Validators, validators...)
+			err = doc.ValidateAndComplete()
+			if err != nil {
+				return err
+			}
+			out, err := wire.MarshalJSONIndent(cdc, doc)
+			if err != nil {
+				return err
+			}
+			err = ioutil.WriteFile(genFile, out, 0600)
+			if err != nil {
+				return err
+			}
+
+			persistentPeers := genTxsPersistentPeers(genTxs)
+			config.P2P.PersistentPeers = persistentPeers
+			configFilePath := filepath.Join(viper.GetString(tmcli.HomeFlag), "config", "config.toml")
+			cfg.WriteConfigFile(configFilePath, config)
+
+			// print out some key information
+			toPrint := struct {
+				ChainID         string `json:"chain_id"`
+				PersistentPeers string `json:"persistent_peers"`
+			}{
+				doc.ChainID,
+				persistentPeers,
+			}
+			out, err = wire.MarshalJSONIndent(cdc, toPrint)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(out))
+			return nil
+		},
+	}
+	cmd.Flags().String(flagGenTxDir, "", "directory of the genesis transactions, defaults to [--home]/config/gentx/")
 	return cmd
 }
 
@@ -211,30 +348,46 @@ func InitCmd(ctx *Context, cdc *wire.Codec, appInit AppInit) *cobra.Command {
 func processGenTxs(genTxsDir string, cdc *wire.Codec, appInit AppInit) (
 	validators []tmtypes.GenesisValidator, appGenTxs []json.RawMessage, persistentPeers string, err error) {
 
-	var fos []os.FileInfo
-	fos, err = ioutil.ReadDir(genTxsDir)
+	var genTxs []GenesisTx
+	genTxs, err = readGenTxs(genTxsDir, cdc)
 	if err != nil {
 		return
 	}
+	for _, genTx := range genTxs {
+		validators = append(validators, genTx.Validator)
+		appGenTxs = append(appGenTxs, genTx.AppGenTx)
+	}
+	persistentPeers = genTxsPersistentPeers(genTxs)
+	return
+}
+
+// read all genesis transactions of a directory, sorted by node ID
+func readGenTxs(genTxsDir string, cdc *wire.Codec) ([]GenesisTx, error) {
+	fos, err := ioutil.ReadDir(genTxsDir)
+	if err != nil {
+		return nil, err
+	}
 
 	genTxs := make(map[string]GenesisTx)
 	var nodeIDs []string
 	for _, fo := range fos {
 		filename := path.Join(genTxsDir, fo.Name())
-		if !fo.IsDir() && (path.Ext(filename) != ".json") {
+		if fo.IsDir() || path.Ext(filename) != ".json" {
 			continue
 		}
 
 		// get the genTx
-		var bz []byte
-		bz, err = ioutil.ReadFile(filename)
+		bz, err := ioutil.ReadFile(filename)
 		if err != nil {
-			return
+			return nil, err
 		}
 		var genTx GenesisTx
 		err = cdc.UnmarshalJSON(bz, &genTx)
 		if err != nil {
-			return
+			return nil, fmt.Errorf("failed to decode %s: %v", filename, err)
+		}
+		if _, ok := genTxs[genTx.NodeID]; ok {
+			return nil, fmt.Errorf("duplicate genesis transaction of node %s in %s", genTx.NodeID, filename)
 		}
 
 		genTxs[genTx.NodeID] = genTx
@@ -242,23 +395,20 @@ func processGenTxs(genTxsDir string, cdc *wire.Codec, appInit AppInit) (
 	}
 
 	sort.Strings(nodeIDs)
-
-	for _, nodeID := range nodeIDs {
-		genTx := genTxs[nodeID]
-
-		// combine some stuff
-		validators = append(validators, genTx.Validator)
-		appGenTxs = append(appGenTxs, genTx.AppGenTx)
-
-		// Add a persistent peer
-		comma := ","
-		if len(persistentPeers) == 0 {
-			comma = ""
-		}
-		persistentPeers += fmt.Sprintf("%s%s@%s:46656", comma, genTx.NodeID, genTx.IP)
+	sorted := make([]GenesisTx, len(nodeIDs))
+	for i, nodeID := range nodeIDs {
+		sorted[i] = genTxs[nodeID]
 	}
+	return sorted, nil
+}
 
-	return
+// the persistent peers of the nodes of genesis transactions
+func genTxsPersistentPeers(genTxs []GenesisTx) string {
+	peers := make([]string, len(genTxs))
+	for i, genTx := range genTxs {
+		peers[i] = fmt.Sprintf("%s@%s:46656", genTx.NodeID, genTx.IP)
+	}
+	return strings.Join(peers, ",")
 }
 
 //________________________________________________________________________________________
@@ -321,6 +471,23 @@ type AppInit struct {
 	// AppGenState creates the core parameters initialization. It takes in a
 	// pubkey meant to represent the pubkey of the validator of this machine.
 	AppGenState func(cdc *wire.Codec, appGenTxs []json.RawMessage) (appState json.RawMessage, err error)
+
+	// ValidateAppState checks the app state of a genesis file with the
+	// genesis validation of every module
+	ValidateAppState func(cdc *wire.Codec, appState json.RawMessage) error
+
+	// Migrations convert the app state of a genesis file between app versions
+	Migrations MigrationMap
+
+	// CollectGenTxs verifies the signed genesis transactions of all
+	// participants against the accounts of appState, and returns the app
+	// state and genesis validators with the transactions applied
+	CollectGenTxs func(cdc *wire.Codec, chainID string, appState json.RawMessage, appGenTxs []json.RawMessage) (
+		json.RawMessage, []tmtypes.GenesisValidator, error)
+
+	// AddGenesisAccount returns appState with a new account holding coins
+	AddGenesisAccount func(cdc *wire.Codec, appState json.RawMessage, addr sdk.Address, coins sdk.Coins) (
+		json.RawMessage, error)
 }
 
 //_____________________________________________________________________
@@ -404,21 +571,14 @@ func SimpleAppGenState(cdc *wire.Codec, appGenTxs []json.RawMessage) (appState j
 func GenerateCoinKey() (sdk.Address, string, error) {
 
 	// construct an in-memory key store
-	codec, err := words.LoadCodec("english")
-	if err != nil {
-		return nil, "", err
-	}
-	keybase := keys.New(
-		dbm.NewMemDB(),
-		codec,
-	)
+	keybase := keys.NewInMemory()
 
 	// generate a private key, with recovery phrase
-	info, secret, err := keybase.Create("name", "pass", keys.AlgoEd25519)
+	info, secret, err := keybase.CreateMnemonic("name", keys.English, "pass", "", keys.Ed25519)
 	if err != nil {
 		return nil, "", err
 	}
-	addr := info.PubKey.Address()
+	addr := sdk.Address(info.GetPubKey().Address())
 	return addr, secret, nil
 }
 
@@ -441,10 +601,10 @@ func GenerateSaveCoinKey(clientRoot, keyName, keyPass string, overwrite bool) (s
 	}
 
 	// generate a private key, with recovery phrase
-	info, secret, err := keybase.Create(keyName, keyPass, keys.AlgoEd25519)
+	info, secret, err := keybase.CreateMnemonic(keyName, keys.English, keyPass, "", keys.Ed25519)
 	if err != nil {
 		return nil, "", err
 	}
-	addr := info.PubKey.Address()
+	addr := sdk.Address(info.GetPubKey().Address())
 	return addr, secret, nil
 }
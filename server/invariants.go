@@ -0,0 +1,63 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	tmtypes "github.com/tepleton/tepleton/types"
+	wrsp "github.com/tepleton/wrsp/types"
+)
+
+// CheckInvariantsCmd checks the invariants of the app against the live
+// state of the node, or against the state of a genesis file, such as an
+// exported one
+func CheckInvariantsCmd(ctx *Context, appCreator AppCreator) *cobra.Command {
+	return &cobra.Command{
+		Use:   "check-invariants [genesis-file]",
+		Short: "Check the invariants of the app against the latest state, or against a genesis file",
+		Args:  cobra.RangeArgs(0, 1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			var app wrsp.Application
+			var err error
+			if len(args) == 0 {
+				app, err = appCreator(viper.GetString("home"), ctx.Logger)
+				if err != nil {
+					return err
+				}
+			} else {
+				// load the genesis into a scratch node
+				doc, err := tmtypes.GenesisDocFromFile(args[0])
+				if err != nil {
+					return err
+				}
+				home, err := ioutil.TempDir("", "check-invariants")
+				if err != nil {
+					return err
+				}
+				defer os.RemoveAll(home)
+				app, err = appCreator(home, ctx.Logger)
+				if err != nil {
+					return err
+				}
+				app.InitChain(wrsp.RequestInitChain{AppStateBytes: doc.AppStateJSON})
+				app.Commit()
+			}
+
+			iApp, ok := app.(invariantApp)
+			if !ok {
+				return errors.New("the app has no invariants")
+			}
+			err = iApp.CheckInvariants()
+			if err != nil {
+				return err
+			}
+			fmt.Println("All invariants hold")
+			return nil
+		},
+	}
+}
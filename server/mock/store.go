@@ -1,6 +1,8 @@
 package mock
 
 import (
+	"io"
+
 	dbm "github.com/tepleton/tmlibs/db"
 
 	sdk "github.com/tepleton/tepleton-sdk/types"
@@ -46,6 +48,38 @@ func (ms multiStore) LoadVersion(ver int64) error {
 	panic("not implemented")
 }
 
+func (ms multiStore) LoadLatestVersionAndUpgrade(upgrades *sdk.StoreUpgrades) error {
+	panic("not implemented")
+}
+
+func (ms multiStore) LoadVersionAndUpgrade(ver int64, upgrades *sdk.StoreUpgrades) error {
+	panic("not implemented")
+}
+
+func (ms multiStore) AddListeners(key sdk.StoreKey, listeners []sdk.WriteListener) {
+	panic("not implemented")
+}
+
+func (ms multiStore) ListeningEnabled(key sdk.StoreKey) bool {
+	return false
+}
+
+func (ms multiStore) CacheMultiStoreWithListeners() sdk.CacheMultiStore {
+	panic("not implemented")
+}
+
+func (ms multiStore) SetTracer(w io.Writer) {
+	panic("not implemented")
+}
+
+func (ms multiStore) SetTracingContext(tc sdk.TraceContext) {
+	panic("not implemented")
+}
+
+func (ms multiStore) TracingEnabled() bool {
+	return false
+}
+
 func (ms multiStore) GetKVStore(key sdk.StoreKey) sdk.KVStore {
 	return ms.kv[key]
 }
@@ -1,6 +1,9 @@
 package server
 
 import (
+	"encoding/hex"
+	"fmt"
+
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -8,15 +11,26 @@ import (
 	"github.com/tepleton/wrsp/server"
 
 	tcmd "github.com/tepleton/tepleton/cmd/tepleton/commands"
+	"github.com/tepleton/tepleton/crypto"
 	"github.com/tepleton/tepleton/node"
+	"github.com/tepleton/tepleton/p2p"
 	"github.com/tepleton/tepleton/proxy"
 	pvm "github.com/tepleton/tepleton/privval"
+	"github.com/tepleton/tepleton/types"
 	cmn "github.com/tepleton/tmlibs/common"
+
+	"github.com/tepleton/tepleton-sdk/crypto/keys/remote"
 )
 
 const (
-	flagWithTendermint = "with-tepleton"
-	flagAddress        = "address"
+	flagWithTendermint  = "with-tepleton"
+	flagAddress         = "address"
+	flagStreamingDir    = "streaming-dir"
+	flagStreamingStores = "streaming-stores"
+	flagTraceStore      = "trace-store"
+	flagInvCheckPeriod  = "inv-check-period"
+	flagRemoteSigner    = "remote-signer"
+	flagRemoteSignerKey = "remote-signer-pubkey"
 )
 
 // StartCmd runs the service passed in, either
@@ -38,6 +52,12 @@ func StartCmd(ctx *Context, appCreator AppCreator) *cobra.Command {
 	// basic flags for wrsp app
 	cmd.Flags().Bool(flagWithTendermint, true, "run wrsp app embedded in-process with tepleton")
 	cmd.Flags().String(flagAddress, "tcp://0.0.0.0:46658", "Listen address")
+	cmd.Flags().String(flagStreamingDir, "", "Append the state changes of every block to a file in this directory")
+	cmd.Flags().String(flagStreamingStores, "", "Comma separated names of the stores to stream, all stores if empty")
+	cmd.Flags().String(flagTraceStore, "", "Append a trace of all store operations of delivered blocks to this file")
+	cmd.Flags().Int64(flagInvCheckPeriod, 0, "Check the invariants of the app every this many blocks, halting on violations (0 disables the checks)")
+	cmd.Flags().String(flagRemoteSigner, "", "Sign votes with the validator key of the remote signer at this address, tcp://host:port or unix:///path")
+	cmd.Flags().String(flagRemoteSignerKey, "", "Hex identity the remote signer must authenticate with, required with --remote-signer")
 
 	// AddNodeFlags adds support for all tepleton-specific command line options
 	tcmd.AddNodeFlags(cmd)
@@ -76,9 +96,14 @@ func startInProcess(ctx *Context, appCreator AppCreator) error {
 		return err
 	}
 
+	privVal, err := loadPrivValidator(ctx)
+	if err != nil {
+		return err
+	}
+
 	// Create & start tepleton node
 	n, err := node.NewNode(cfg,
-		pvm.LoadOrGenFilePV(cfg.PrivValidatorFile()),
+		privVal,
 		proxy.NewLocalClientCreator(app),
 		node.DefaultGenesisDocProviderFunc(cfg),
 		node.DefaultDBProvider,
@@ -96,3 +121,40 @@ func startInProcess(ctx *Context, appCreator AppCreator) error {
 	n.RunForever()
 	return nil
 }
+
+// loadPrivValidator returns the validator of the node, signing with the
+// remote signer if one is configured, else with priv_validator.json
+func loadPrivValidator(ctx *Context) (types.PrivValidator, error) {
+	cfg := ctx.Config
+	addr := viper.GetString(flagRemoteSigner)
+	if addr == "" {
+		return pvm.LoadOrGenFilePV(cfg.PrivValidatorFile()), nil
+	}
+
+	// the signer must be authenticated, else anyone could sign the votes
+	h := viper.GetString(flagRemoteSignerKey)
+	if h == "" {
+		return nil, errors.Errorf("--%s is required with --%s", flagRemoteSignerKey, flagRemoteSigner)
+	}
+	bz, err := hex.DecodeString(h)
+	if err != nil {
+		return nil, errors.Errorf("invalid remote signer identity: %v", err)
+	}
+	signerPubKey, err := crypto.PubKeyFromBytes(bz)
+	if err != nil {
+		return nil, errors.Errorf("invalid remote signer identity: %v", err)
+	}
+
+	// the node authenticates to the signer with its p2p key
+	nodeKey, err := p2p.LoadOrGenNodeKey(cfg.NodeKeyFile())
+	if err != nil {
+		return nil, err
+	}
+	ctx.Logger.Info("Connecting to the remote signer", "addr", addr,
+		"identity", fmt.Sprintf("%X", nodeKey.PubKey().Bytes()))
+	client, err := remote.Dial(addr, nodeKey.PrivKey, signerPubKey)
+	if err != nil {
+		return nil, err
+	}
+	return remote.NewPrivValidator(client)
+}
@@ -85,11 +85,14 @@ func AddCommands(
 
 	rootCmd.AddCommand(
 		InitCmd(ctx, cdc, appInit),
+		ValidateGenesisCmd(ctx, cdc, appInit),
+		MigrateGenesisCmd(ctx, cdc, appInit),
 		StartCmd(ctx, appCreator),
 		UnsafeResetAllCmd(ctx),
 		client.LineBreak,
 		tepletonCmd,
 		ExportCmd(ctx, cdc, appExport),
+		CheckInvariantsCmd(ctx, appCreator),
 		client.LineBreak,
 		version.VersionCmd,
 	)
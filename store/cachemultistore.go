@@ -13,18 +13,28 @@ type cacheMultiStore struct {
 	db         CacheKVStore
 	stores     map[StoreKey]CacheWrap
 	keysByName map[string]StoreKey
+	tracer     *tracer // nil if not traced
 }
 
 var _ CacheMultiStore = cacheMultiStore{}
 
-func newCacheMultiStoreFromRMS(rms *rootMultiStore) cacheMultiStore {
+// If listening, the writes to stores with listeners are reported and the
+// operations on the stores are traced.
+func newCacheMultiStoreFromRMS(rms *rootMultiStore, listening bool) cacheMultiStore {
 	cms := cacheMultiStore{
 		db:         NewCacheKVStore(dbStoreAdapter{rms.db}),
 		stores:     make(map[StoreKey]CacheWrap, len(rms.stores)),
 		keysByName: rms.keysByName,
 	}
+	if listening {
+		cms.tracer = rms.getTracer()
+	}
 	for key, store := range rms.stores {
-		cms.stores[key] = store.CacheWrap()
+		cache := store.CacheWrap()
+		if listeners := rms.listeners[key]; listening && len(listeners) > 0 {
+			cache = newListenKVStore(cache.(CacheKVStore), key, listeners)
+		}
+		cms.stores[key] = cache
 	}
 	return cms
 }
@@ -33,6 +43,7 @@ func newCacheMultiStoreFromCMS(cms cacheMultiStore) cacheMultiStore {
 	cms2 := cacheMultiStore{
 		db:     NewCacheKVStore(cms.db),
 		stores: make(map[StoreKey]CacheWrap, len(cms.stores)),
+		tracer: cms.tracer,
 	}
 	for key, store := range cms.stores {
 		cms2.stores[key] = store.CacheWrap()
@@ -70,7 +81,11 @@ func (cms cacheMultiStore) GetStore(key StoreKey) Store {
 
 // Implements MultiStore.
 func (cms cacheMultiStore) GetKVStore(key StoreKey) KVStore {
-	return cms.stores[key].(KVStore)
+	store := cms.stores[key].(KVStore)
+	if cms.tracer != nil {
+		return newTraceKVStore(store, key, cms.tracer)
+	}
+	return store
 }
 
 // Implements MultiStore.
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"bytes"
 	"fmt"
 	"sync"
 
@@ -167,15 +168,40 @@ func (st *iavlStore) Query(req wrsp.RequestQuery) (res wrsp.ResponseQuery) {
 			_, res.Value = tree.GetVersioned(key, height)
 		}
 	case "/subspace":
+		// the whole subspace, as long as it isn't too large
 		subspace := req.Data
 		res.Key = subspace
-		var KVs []KVPair
-		iterator := sdk.KVStorePrefixIterator(st, subspace)
-		for ; iterator.Valid(); iterator.Next() {
-			KVs = append(KVs, KVPair{iterator.Key(), iterator.Value()})
+		page, err := st.querySubspace(SubspaceQuery{Subspace: subspace})
+		if err != nil {
+			return err.QueryResult()
 		}
-		iterator.Close()
-		res.Value = cdc.MustMarshalBinary(KVs)
+		if page.NextKey != nil {
+			msg := fmt.Sprintf("Subspace has more than %d keys, use /subspace-page", sdk.MaxSubspaceQueryLimit)
+			return sdk.ErrUnknownRequest(msg).QueryResult()
+		}
+		res.Value = cdc.MustMarshalBinary(page.KVs)
+	case "/subspace-page":
+		var query SubspaceQuery
+		if err := cdc.UnmarshalBinary(req.Data, &query); err != nil {
+			return sdk.ErrTxDecode(err.Error()).QueryResult()
+		}
+		res.Key = query.Subspace
+		var page SubspaceResult
+		var err sdk.Error
+		if req.Prove {
+			page, res.Proof, err = st.querySubspaceWithProof(query, height)
+		} else {
+			if req.Height == 0 {
+				// without a proof there is no need to wait for the next header
+				height = tree.Version64()
+				res.Height = height
+			}
+			page, err = st.querySubspaceAt(query, height)
+		}
+		if err != nil {
+			return err.QueryResult()
+		}
+		res.Value = cdc.MustMarshalBinary(page)
 	default:
 		msg := fmt.Sprintf("Unexpected Query path: %v", req.Path)
 		return sdk.ErrUnknownRequest(msg).QueryResult()
@@ -183,6 +209,110 @@ func (st *iavlStore) Query(req wrsp.RequestQuery) (res wrsp.ResponseQuery) {
 	return
 }
 
+// pageRange returns the limit and the iteration domain of a subspace query
+func pageRange(query SubspaceQuery) (limit int, start, end []byte, err sdk.Error) {
+	limit = query.Limit
+	if limit <= 0 || limit > sdk.MaxSubspaceQueryLimit {
+		limit = sdk.MaxSubspaceQueryLimit
+	}
+	start, end = query.Subspace, sdk.PrefixEndBytes(query.Subspace)
+	if len(query.Start) == 0 {
+		return
+	}
+	if !bytes.HasPrefix(query.Start, query.Subspace) {
+		msg := fmt.Sprintf("Start %X is not in subspace %X", query.Start, query.Subspace)
+		return 0, nil, nil, sdk.ErrUnknownRequest(msg)
+	}
+	if query.Reverse {
+		// end is exclusive, the page includes Start
+		end = append(cp(query.Start), 0)
+	} else {
+		start = query.Start
+	}
+	return
+}
+
+// querySubspace returns a page of the pairs of a subspace
+func (st *iavlStore) querySubspace(query SubspaceQuery) (page SubspaceResult, err sdk.Error) {
+	limit, start, end, err := pageRange(query)
+	if err != nil {
+		return
+	}
+	var iterator Iterator
+	if query.Reverse {
+		iterator = st.ReverseIterator(start, end)
+	} else {
+		iterator = st.Iterator(start, end)
+	}
+	defer iterator.Close()
+	page.KVs = []KVPair{}
+	for ; iterator.Valid(); iterator.Next() {
+		if len(page.KVs) == limit {
+			page.NextKey = iterator.Key()
+			break
+		}
+		page.KVs = append(page.KVs, KVPair{iterator.Key(), iterator.Value()})
+	}
+	return
+}
+
+// querySubspaceAt returns a page of the pairs of a subspace at height.
+// Only pages of the latest version can be iterated in reverse.
+func (st *iavlStore) querySubspaceAt(query SubspaceQuery, height int64) (page SubspaceResult, err sdk.Error) {
+	if height == st.tree.Version64() {
+		return st.querySubspace(query)
+	}
+	if !st.tree.VersionExists(height) {
+		err = sdk.ErrUnknownRequest(fmt.Sprintf("Version %d is not available", height))
+		return
+	}
+	if query.Reverse {
+		err = sdk.ErrUnknownRequest("Reverse subspace pages are only supported at the latest height")
+		return
+	}
+	page, _, err = st.queryVersionedRange(query, height)
+	return
+}
+
+// querySubspaceWithProof returns a page of the pairs of a subspace at
+// height, along with a range proof of the page.
+func (st *iavlStore) querySubspaceWithProof(query SubspaceQuery, height int64) (page SubspaceResult, proof []byte, err sdk.Error) {
+	if query.Reverse {
+		err = sdk.ErrUnknownRequest("Proofs of reverse subspace pages are not supported")
+		return
+	}
+	page, rangeProof, err := st.queryVersionedRange(query, height)
+	if err != nil {
+		return
+	}
+	proof = cdc.MustMarshalBinary(rangeProof)
+	return
+}
+
+// queryVersionedRange returns a page of the pairs of a subspace at height
+// in ascending order, along with the range proof of the page
+func (st *iavlStore) queryVersionedRange(query SubspaceQuery, height int64) (page SubspaceResult, rangeProof *iavl.KeyRangeProof, err sdk.Error) {
+	limit, start, end, err := pageRange(query)
+	if err != nil {
+		return
+	}
+	// one more pair than the limit gives the next key
+	keys, values, rangeProof, rerr := st.tree.GetVersionedRangeWithProof(start, end, limit+1, height)
+	if rerr != nil {
+		err = sdk.ErrInternal(rerr.Error())
+		return
+	}
+	page.KVs = []KVPair{}
+	for i, key := range keys {
+		if i == limit {
+			page.NextKey = key
+			break
+		}
+		page.KVs = append(page.KVs, KVPair{key, values[i]})
+	}
+	return
+}
+
 //----------------------------------------
 
 // Implements Iterator.
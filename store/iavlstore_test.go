@@ -1,6 +1,7 @@
 package store
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -346,3 +347,90 @@ func TestIAVLStoreQuery(t *testing.T) {
 	assert.Equal(t, uint32(sdk.CodeOK), qres.Code)
 	assert.Equal(t, v1, qres.Value)
 }
+
+func TestIAVLStoreQuerySubspacePage(t *testing.T) {
+	db := dbm.NewMemDB()
+	tree := iavl.NewVersionedTree(db, cacheSize)
+	iavlStore := newIAVLStore(tree, numHistory)
+
+	for _, k := range []string{"a", "key1", "key2", "key3", "z"} {
+		iavlStore.Set([]byte(k), []byte("v"+k))
+	}
+	cid := iavlStore.Commit()
+
+	queryPage := func(query SubspaceQuery, prove bool) (SubspaceResult, wrsp.ResponseQuery) {
+		req := wrsp.RequestQuery{Path: "/subspace-page", Data: cdc.MustMarshalBinary(query), Height: cid.Version, Prove: prove}
+		qres := iavlStore.Query(req)
+		var page SubspaceResult
+		if qres.Code == uint32(sdk.CodeOK) {
+			cdc.MustUnmarshalBinary(qres.Value, &page)
+		}
+		return page, qres
+	}
+	keys := func(page SubspaceResult) (keys []string) {
+		for _, kv := range page.KVs {
+			keys = append(keys, string(kv.Key))
+		}
+		return
+	}
+
+	// forward, page by page
+	page, qres := queryPage(SubspaceQuery{Subspace: []byte("key"), Limit: 2}, false)
+	assert.Equal(t, uint32(sdk.CodeOK), qres.Code)
+	assert.Equal(t, []string{"key1", "key2"}, keys(page))
+	assert.Equal(t, []byte("key3"), page.NextKey)
+	page, _ = queryPage(SubspaceQuery{Subspace: []byte("key"), Start: page.NextKey, Limit: 2}, false)
+	assert.Equal(t, []string{"key3"}, keys(page))
+	assert.Nil(t, page.NextKey)
+
+	// reverse
+	page, _ = queryPage(SubspaceQuery{Subspace: []byte("key"), Limit: 2, Reverse: true}, false)
+	assert.Equal(t, []string{"key3", "key2"}, keys(page))
+	assert.Equal(t, []byte("key1"), page.NextKey)
+	page, _ = queryPage(SubspaceQuery{Subspace: []byte("key"), Start: page.NextKey, Limit: 2, Reverse: true}, false)
+	assert.Equal(t, []string{"key1"}, keys(page))
+	assert.Nil(t, page.NextKey)
+
+	// start outside of the subspace
+	_, qres = queryPage(SubspaceQuery{Subspace: []byte("key"), Start: []byte("z")}, false)
+	assert.NotEqual(t, uint32(sdk.CodeOK), qres.Code)
+
+	// with a proof
+	page, qres = queryPage(SubspaceQuery{Subspace: []byte("key"), Limit: 2}, true)
+	assert.Equal(t, uint32(sdk.CodeOK), qres.Code)
+	assert.Equal(t, []string{"key1", "key2"}, keys(page))
+	assert.Equal(t, []byte("key3"), page.NextKey)
+	assert.NotEmpty(t, qres.Proof)
+	_, qres = queryPage(SubspaceQuery{Subspace: []byte("key"), Reverse: true}, true)
+	assert.NotEqual(t, uint32(sdk.CodeOK), qres.Code)
+
+	// earlier heights don't see later writes
+	iavlStore.Set([]byte("key0"), []byte("vkey0"))
+	iavlStore.Commit()
+	page, qres = queryPage(SubspaceQuery{Subspace: []byte("key"), Limit: 2}, false)
+	assert.Equal(t, uint32(sdk.CodeOK), qres.Code)
+	assert.Equal(t, cid.Version, qres.Height)
+	assert.Equal(t, []string{"key1", "key2"}, keys(page))
+	_, qres = queryPage(SubspaceQuery{Subspace: []byte("key"), Reverse: true}, false)
+	assert.NotEqual(t, uint32(sdk.CodeOK), qres.Code)
+	req := wrsp.RequestQuery{Path: "/subspace-page", Data: cdc.MustMarshalBinary(SubspaceQuery{Subspace: []byte("key"), Limit: 2})}
+	qres = iavlStore.Query(req)
+	assert.Equal(t, uint32(sdk.CodeOK), qres.Code)
+	assert.Equal(t, cid.Version+1, qres.Height)
+	cdc.MustUnmarshalBinary(qres.Value, &page)
+	assert.Equal(t, []string{"key0", "key1"}, keys(page))
+}
+
+func TestIAVLStoreQuerySubspaceBounded(t *testing.T) {
+	db := dbm.NewMemDB()
+	tree := iavl.NewVersionedTree(db, cacheSize)
+	iavlStore := newIAVLStore(tree, numHistory)
+	for i := 0; i <= sdk.MaxSubspaceQueryLimit; i++ {
+		iavlStore.Set([]byte(fmt.Sprintf("key%05d", i)), []byte("v"))
+	}
+	cid := iavlStore.Commit()
+
+	querySub := wrsp.RequestQuery{Path: "/subspace", Data: []byte("key"), Height: cid.Version}
+	qres := iavlStore.Query(querySub)
+	assert.NotEqual(t, uint32(sdk.CodeOK), qres.Code)
+}
@@ -0,0 +1,43 @@
+package store
+
+var _ CacheKVStore = (*listenKVStore)(nil)
+
+// listenKVStore wraps a CacheKVStore and reports every Set and Delete to
+// listeners, along with the key of the store. Writes of caches of the
+// listenKVStore are reported when the caches are written.
+type listenKVStore struct {
+	CacheKVStore
+	storeKey  StoreKey
+	listeners []WriteListener
+}
+
+func newListenKVStore(parent CacheKVStore, storeKey StoreKey, listeners []WriteListener) *listenKVStore {
+	return &listenKVStore{
+		CacheKVStore: parent,
+		storeKey:     storeKey,
+		listeners:    listeners,
+	}
+}
+
+// Implements KVStore.
+func (ls *listenKVStore) Set(key []byte, value []byte) {
+	ls.CacheKVStore.Set(key, value)
+	ls.onWrite(key, value, false)
+}
+
+// Implements KVStore.
+func (ls *listenKVStore) Delete(key []byte) {
+	ls.CacheKVStore.Delete(key)
+	ls.onWrite(key, nil, true)
+}
+
+// Implements KVStore.
+func (ls *listenKVStore) CacheWrap() CacheWrap {
+	return NewCacheKVStore(ls)
+}
+
+func (ls *listenKVStore) onWrite(key []byte, value []byte, delete bool) {
+	for _, l := range ls.listeners {
+		l.OnWrite(ls.storeKey, key, value, delete)
+	}
+}
@@ -0,0 +1,72 @@
+package store
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tepleton/tmlibs/db"
+
+	sdk "github.com/tepleton/tepleton-sdk/types"
+)
+
+type writeRecorder struct {
+	writes []string
+}
+
+func (wr *writeRecorder) OnWrite(storeKey StoreKey, key []byte, value []byte, delete bool) {
+	wr.writes = append(wr.writes, fmt.Sprintf("%s %s=%s %v", storeKey.Name(), key, value, delete))
+}
+
+func TestListenKVStore(t *testing.T) {
+	key := sdk.NewKVStoreKey("store")
+	recorder := &writeRecorder{}
+	parent := NewCacheKVStore(dbStoreAdapter{dbm.NewMemDB()})
+	ls := newListenKVStore(parent, key, []WriteListener{recorder})
+
+	ls.Set([]byte("a"), []byte("1"))
+	ls.Delete([]byte("b"))
+	require.Equal(t, []string{"store a=1 false", "store b= true"}, recorder.writes)
+	require.Equal(t, []byte("1"), parent.Get([]byte("a")))
+
+	// writes of caches are reported when written, in key order
+	recorder.writes = nil
+	cache := ls.CacheWrap().(CacheKVStore)
+	cache.Set([]byte("d"), []byte("4"))
+	cache.Set([]byte("c"), []byte("3"))
+	require.Empty(t, recorder.writes)
+	cache.Write()
+	require.Equal(t, []string{"store c=3 false", "store d=4 false"}, recorder.writes)
+
+	// writing to the parent isn't reported
+	recorder.writes = nil
+	ls.Write()
+	require.Empty(t, recorder.writes)
+}
+
+func TestMultiStoreListeners(t *testing.T) {
+	db := dbm.NewMemDB()
+	multi := newMultiStoreWithMounts(db)
+	require.Nil(t, multi.LoadLatestVersion())
+	key1, key2 := multi.keysByName["store1"], multi.keysByName["store2"]
+
+	recorder := &writeRecorder{}
+	multi.AddListeners(key1, []WriteListener{recorder})
+	require.True(t, multi.ListeningEnabled(key1))
+	require.False(t, multi.ListeningEnabled(key2))
+
+	// plain caches don't report writes
+	multi.CacheMultiStore().GetKVStore(key1).Set([]byte("a"), []byte("1"))
+	require.Empty(t, recorder.writes)
+
+	cms := multi.CacheMultiStoreWithListeners()
+	cms.GetKVStore(key1).Set([]byte("a"), []byte("1"))
+	cms.GetKVStore(key2).Set([]byte("a"), []byte("1"))
+	txCache := cms.CacheMultiStore()
+	txCache.GetKVStore(key1).Set([]byte("b"), []byte("2"))
+	require.Equal(t, []string{"store1 a=1 false"}, recorder.writes)
+	txCache.Write()
+	require.Equal(t, []string{"store1 a=1 false", "store1 b=2 false"}, recorder.writes)
+	cms.Write()
+	require.Len(t, recorder.writes, 2)
+}
@@ -2,7 +2,9 @@ package store
 
 import (
 	"fmt"
+	"io"
 	"strings"
+	"sync"
 
 	"golang.org/x/crypto/ripemd160"
 
@@ -28,6 +30,13 @@ type rootMultiStore struct {
 	storesParams map[StoreKey]storeParams
 	stores       map[StoreKey]CommitStore
 	keysByName   map[string]StoreKey
+	listeners    map[StoreKey][]WriteListener
+
+	traceMtx sync.RWMutex // guards tracer, which is set while caches are made
+	tracer   *tracer      // nil if not traced
+
+	// upgrades applied by the last load, recorded at the next commit
+	pendingUpgrades *sdk.StoreUpgrades
 }
 
 var _ CommitMultiStore = (*rootMultiStore)(nil)
@@ -40,6 +49,7 @@ func NewCommitMultiStore(db dbm.DB) *rootMultiStore {
 		storesParams: make(map[StoreKey]storeParams),
 		stores:       make(map[StoreKey]CommitStore),
 		keysByName:   make(map[string]StoreKey),
+		listeners:    make(map[StoreKey][]WriteListener),
 	}
 }
 
@@ -74,14 +84,67 @@ func (rs *rootMultiStore) GetCommitKVStore(key StoreKey) CommitKVStore {
 	return rs.stores[key].(CommitKVStore)
 }
 
+// Implements CommitMultiStore.
+func (rs *rootMultiStore) AddListeners(key StoreKey, listeners []WriteListener) {
+	rs.listeners[key] = append(rs.listeners[key], listeners...)
+}
+
+// Implements CommitMultiStore.
+func (rs *rootMultiStore) ListeningEnabled(key StoreKey) bool {
+	return len(rs.listeners[key]) > 0
+}
+
+// Implements CommitMultiStore.
+// Only caches created after the tracer is set are traced.
+func (rs *rootMultiStore) SetTracer(w io.Writer) {
+	rs.traceMtx.Lock()
+	defer rs.traceMtx.Unlock()
+	if w == nil {
+		rs.tracer = nil
+		return
+	}
+	rs.tracer = &tracer{w: w}
+}
+
+// Implements CommitMultiStore.
+func (rs *rootMultiStore) SetTracingContext(tc TraceContext) {
+	if t := rs.getTracer(); t != nil {
+		t.setContext(tc)
+	}
+}
+
+// Implements CommitMultiStore.
+func (rs *rootMultiStore) TracingEnabled() bool {
+	return rs.getTracer() != nil
+}
+
+// getTracer returns the current tracer, nil if not traced
+func (rs *rootMultiStore) getTracer() *tracer {
+	rs.traceMtx.RLock()
+	defer rs.traceMtx.RUnlock()
+	return rs.tracer
+}
+
 // Implements CommitMultiStore.
 func (rs *rootMultiStore) LoadLatestVersion() error {
-	ver := getLatestVersion(rs.db)
-	return rs.LoadVersion(ver)
+	return rs.LoadLatestVersionAndUpgrade(nil)
 }
 
 // Implements CommitMultiStore.
 func (rs *rootMultiStore) LoadVersion(ver int64) error {
+	return rs.LoadVersionAndUpgrade(ver, nil)
+}
+
+// Implements CommitMultiStore.
+func (rs *rootMultiStore) LoadLatestVersionAndUpgrade(upgrades *StoreUpgrades) error {
+	ver := getLatestVersion(rs.db)
+	return rs.LoadVersionAndUpgrade(ver, upgrades)
+}
+
+// Implements CommitMultiStore.
+// Mounted stores missing from the saved version must be added or renamed
+// by upgrades, saved stores that are no longer mounted must be deleted.
+func (rs *rootMultiStore) LoadVersionAndUpgrade(ver int64, upgrades *StoreUpgrades) error {
 
 	// Special logic for version 0
 	if ver == 0 {
@@ -105,11 +168,51 @@ func (rs *rootMultiStore) LoadVersion(ver int64) error {
 		return err
 	}
 
+	saved := make(map[string]CommitID, len(cInfo.StoreInfos))
+	for _, storeInfo := range cInfo.StoreInfos {
+		saved[storeInfo.Name] = storeInfo.Core.CommitID
+	}
+
+	// Check the upgrades before touching any data.
+	for _, storeInfo := range cInfo.StoreInfos {
+		name := storeInfo.Name
+		if _, ok := rs.keysByName[name]; ok {
+			continue
+		}
+		if !upgrades.IsDeleted(name) && !isRenamedAway(upgrades, name) {
+			return fmt.Errorf("Failed to load rootMultiStore: store %v is not mounted", name)
+		}
+	}
+	for key, storeParams := range rs.storesParams {
+		name := key.Name()
+		if _, ok := saved[name]; ok || storeParams.typ == sdk.StoreTypeTransient {
+			continue
+		}
+		if oldName := upgrades.RenamedFrom(name); oldName != "" {
+			if _, ok := saved[oldName]; !ok {
+				return fmt.Errorf("Failed to load rootMultiStore: renamed store %v doesn't exist", oldName)
+			}
+			continue
+		}
+		if !upgrades.IsAdded(name) {
+			return fmt.Errorf("Unused CommitStoreLoader: %v", key)
+		}
+	}
+
 	// Load each Store
 	var newStores = make(map[StoreKey]CommitStore)
-	for _, storeInfo := range cInfo.StoreInfos {
-		key, commitID := rs.nameToKey(storeInfo.Name), storeInfo.Core.CommitID
-		storeParams := rs.storesParams[key]
+	for key, storeParams := range rs.storesParams {
+		name := key.Name()
+		commitID, ok := saved[name]
+		if !ok {
+			if oldName := upgrades.RenamedFrom(name); oldName != "" {
+				commitID = saved[oldName]
+				if storeParams.db == nil {
+					moveStoreData(rs.db, oldName, name)
+				}
+			}
+			// added stores start empty, with a zero CommitID
+		}
 		store, err := rs.loadCommitStoreFromParams(commitID, storeParams)
 		if err != nil {
 			return fmt.Errorf("Failed to load rootMultiStore: %v", err)
@@ -117,19 +220,64 @@ func (rs *rootMultiStore) LoadVersion(ver int64) error {
 		newStores[key] = store
 	}
 
-	// If any CommitStoreLoaders were not used, return error.
-	for key := range rs.storesParams {
-		if _, ok := newStores[key]; !ok {
-			return fmt.Errorf("Unused CommitStoreLoader: %v", key)
+	// Prune deleted stores.
+	for _, storeInfo := range cInfo.StoreInfos {
+		name := storeInfo.Name
+		if _, ok := rs.keysByName[name]; !ok && upgrades.IsDeleted(name) {
+			deleteStoreData(rs.db, name)
 		}
 	}
 
 	// Success.
 	rs.lastCommitID = cInfo.CommitID()
 	rs.stores = newStores
+	rs.pendingUpgrades = nil
+	if !upgrades.IsEmpty() {
+		rs.pendingUpgrades = upgrades
+	}
 	return nil
 }
 
+// isRenamedAway is true if the store name is renamed by upgrades
+func isRenamedAway(upgrades *StoreUpgrades, name string) bool {
+	if upgrades == nil {
+		return false
+	}
+	for _, rename := range upgrades.Renamed {
+		if rename.OldKey == name {
+			return true
+		}
+	}
+	return false
+}
+
+// moveStoreData moves all data of the store oldName to the store newName
+func moveStoreData(db dbm.DB, oldName, newName string) {
+	oldPrefix, newPrefix := storePrefix(oldName), storePrefix(newName)
+	batch := db.NewBatch()
+	iter := db.Iterator(oldPrefix, sdk.PrefixEndBytes(oldPrefix))
+	for ; iter.Valid(); iter.Next() {
+		key := iter.Key()
+		newKey := append(append([]byte{}, newPrefix...), key[len(oldPrefix):]...)
+		batch.Set(newKey, iter.Value())
+		batch.Delete(key)
+	}
+	iter.Close()
+	batch.Write()
+}
+
+// deleteStoreData deletes all data of the store name
+func deleteStoreData(db dbm.DB, name string) {
+	prefix := storePrefix(name)
+	batch := db.NewBatch()
+	iter := db.Iterator(prefix, sdk.PrefixEndBytes(prefix))
+	for ; iter.Valid(); iter.Next() {
+		batch.Delete(iter.Key())
+	}
+	iter.Close()
+	batch.Write()
+}
+
 //----------------------------------------
 // +CommitStore
 
@@ -144,12 +292,14 @@ func (rs *rootMultiStore) Commit() CommitID {
 	// Commit stores.
 	version := rs.lastCommitID.Version + 1
 	commitInfo := commitStores(version, rs.stores)
+	commitInfo.Upgrades = rs.pendingUpgrades
 
 	// Need to update atomically.
 	batch := rs.db.NewBatch()
 	setCommitInfo(batch, version, commitInfo)
 	setLatestVersion(batch, version)
 	batch.Write()
+	rs.pendingUpgrades = nil
 
 	// Prepare for next version.
 	commitID := CommitID{
@@ -170,7 +320,12 @@ func (rs *rootMultiStore) CacheWrap() CacheWrap {
 
 // Implements MultiStore.
 func (rs *rootMultiStore) CacheMultiStore() CacheMultiStore {
-	return newCacheMultiStoreFromRMS(rs)
+	return newCacheMultiStoreFromRMS(rs, false)
+}
+
+// Implements CommitMultiStore.
+func (rs *rootMultiStore) CacheMultiStoreWithListeners() CacheMultiStore {
+	return newCacheMultiStoreFromRMS(rs, true)
 }
 
 // Implements MultiStore.
@@ -255,7 +410,7 @@ func (rs *rootMultiStore) loadCommitStoreFromParams(id CommitID, params storePar
 	if params.db != nil {
 		db = dbm.NewPrefixDB(params.db, []byte("s/_/"))
 	} else {
-		db = dbm.NewPrefixDB(rs.db, []byte("s/k:"+params.key.Name()+"/"))
+		db = dbm.NewPrefixDB(rs.db, storePrefix(params.key.Name()))
 	}
 	switch params.typ {
 	case sdk.StoreTypeMulti:
@@ -267,18 +422,18 @@ func (rs *rootMultiStore) loadCommitStoreFromParams(id CommitID, params storePar
 		return
 	case sdk.StoreTypeDB:
 		panic("dbm.DB is not a CommitStore")
+	case sdk.StoreTypeTransient:
+		// transient stores never persist anything, so need no db
+		store = newTransientStore()
+		return
 	default:
 		panic(fmt.Sprintf("unrecognized store type %v", params.typ))
 	}
 }
 
-func (rs *rootMultiStore) nameToKey(name string) StoreKey {
-	for key := range rs.storesParams {
-		if key.Name() == name {
-			return key
-		}
-	}
-	panic("Unknown name " + name)
+// storePrefix is the prefix of the data of the store name in the root db
+func storePrefix(name string) []byte {
+	return []byte("s/k:" + name + "/")
 }
 
 //----------------------------------------
@@ -301,6 +456,10 @@ type commitInfo struct {
 
 	// Store info for
 	StoreInfos []storeInfo
+
+	// Upgrades applied to the stores before this version, if any.
+	// Not part of the hash.
+	Upgrades *sdk.StoreUpgrades
 }
 
 // Hash returns the simple merkle root hash of the stores sorted by name.
@@ -377,6 +536,11 @@ func commitStores(version int64, storeMap map[StoreKey]CommitStore) commitInfo {
 		// Commit
 		commitID := store.Commit()
 
+		// Transient stores are reset, not part of the hash
+		if store.GetStoreType() == sdk.StoreTypeTransient {
+			continue
+		}
+
 		// Record CommitID
 		si := storeInfo{}
 		si.Name = key.Name()
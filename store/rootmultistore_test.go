@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	wrsp "github.com/tepleton/wrsp/types"
 	dbm "github.com/tepleton/tmlibs/db"
 	"github.com/tepleton/tmlibs/merkle"
@@ -200,3 +201,68 @@ func hashStores(stores map[StoreKey]CommitStore) []byte {
 	}
 	return merkle.SimpleHashFromMap(m)
 }
+
+func TestMultiStoreUpgrades(t *testing.T) {
+	db := dbm.NewMemDB()
+	k, v := []byte("wind"), []byte("blows")
+
+	// Stores with their own prefix in the root db, as mounted by MountStore.
+	multi := NewCommitMultiStore(db)
+	multi.MountStoreWithDB(sdk.NewKVStoreKey("store1"), sdk.StoreTypeIAVL, nil)
+	multi.MountStoreWithDB(sdk.NewKVStoreKey("store2"), sdk.StoreTypeIAVL, nil)
+	multi.MountStoreWithDB(sdk.NewKVStoreKey("store3"), sdk.StoreTypeIAVL, nil)
+	require.Nil(t, multi.LoadLatestVersion())
+	multi.getStoreByName("store2").(KVStore).Set(k, v)
+	multi.getStoreByName("store3").(KVStore).Set(k, v)
+	multi.Commit()
+
+	// Mounting a new store without upgrades fails.
+	upgraded := NewCommitMultiStore(db)
+	upgraded.MountStoreWithDB(sdk.NewKVStoreKey("store1"), sdk.StoreTypeIAVL, nil)
+	upgraded.MountStoreWithDB(sdk.NewKVStoreKey("renamed2"), sdk.StoreTypeIAVL, nil)
+	upgraded.MountStoreWithDB(sdk.NewKVStoreKey("store4"), sdk.StoreTypeIAVL, nil)
+	require.NotNil(t, upgraded.LoadLatestVersion())
+
+	// Saved stores that are no longer mounted must be deleted.
+	upgrades := &StoreUpgrades{
+		Added:   []string{"store4"},
+		Renamed: []StoreRename{{OldKey: "store2", NewKey: "renamed2"}},
+	}
+	require.NotNil(t, upgraded.LoadLatestVersionAndUpgrade(upgrades))
+
+	upgrades.Deleted = []string{"store3"}
+	require.Nil(t, upgraded.LoadLatestVersionAndUpgrade(upgrades))
+	assert.Equal(t, multi.LastCommitID(), upgraded.LastCommitID())
+
+	// The renamed store kept its data, the added store is empty and the
+	// deleted store is pruned.
+	assert.Equal(t, v, upgraded.getStoreByName("renamed2").(KVStore).Get(k))
+	assert.Nil(t, upgraded.getStoreByName("store4").(KVStore).Get(k))
+	assert.Nil(t, upgraded.getStoreByName("store3"))
+	for _, name := range []string{"store2", "store3"} {
+		prefix := storePrefix(name)
+		iter := db.Iterator(prefix, sdk.PrefixEndBytes(prefix))
+		assert.False(t, iter.Valid(), name)
+		iter.Close()
+	}
+
+	// The upgrades are recorded with the next version only.
+	cid := upgraded.Commit()
+	cInfo, err := getCommitInfo(db, cid.Version)
+	require.Nil(t, err)
+	assert.Equal(t, upgrades, cInfo.Upgrades)
+	assert.Equal(t, cid, cInfo.CommitID())
+	cid = upgraded.Commit()
+	cInfo, err = getCommitInfo(db, cid.Version)
+	require.Nil(t, err)
+	assert.Nil(t, cInfo.Upgrades)
+
+	// Reloading needs no upgrades anymore.
+	reloaded := NewCommitMultiStore(db)
+	reloaded.MountStoreWithDB(sdk.NewKVStoreKey("store1"), sdk.StoreTypeIAVL, nil)
+	reloaded.MountStoreWithDB(sdk.NewKVStoreKey("renamed2"), sdk.StoreTypeIAVL, nil)
+	reloaded.MountStoreWithDB(sdk.NewKVStoreKey("store4"), sdk.StoreTypeIAVL, nil)
+	require.Nil(t, reloaded.LoadLatestVersion())
+	assert.Equal(t, cid, reloaded.LastCommitID())
+	assert.Equal(t, v, reloaded.getStoreByName("renamed2").(KVStore).Get(k))
+}
@@ -0,0 +1,136 @@
+package streaming
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	tmtypes "github.com/tepleton/tepleton/types"
+	wrsp "github.com/tepleton/wrsp/types"
+
+	sdk "github.com/tepleton/tepleton-sdk/types"
+	"github.com/tepleton/tepleton-sdk/wire"
+)
+
+// FileName is the name of the file the FileService appends to
+const FileName = "state_changes.bin"
+
+// maxRecordSize bounds the size of a record read back, to fail on corrupt
+// files rather than allocating arbitrary amounts of memory
+const maxRecordSize = 1 << 30
+
+// StoreKVPair is a single write to a store, Value is nil for deletes
+type StoreKVPair struct {
+	StoreKey string `json:"store_key"`
+	Delete   bool   `json:"delete"`
+	Key      []byte `json:"key"`
+	Value    []byte `json:"value"`
+}
+
+// TxRecord marks the end of a transaction and holds its writes
+type TxRecord struct {
+	Hash    []byte        `json:"hash"`
+	Code    uint32        `json:"code"`
+	Changes []StoreKVPair `json:"changes"`
+}
+
+// BlockRecord holds all writes of a block in the order they were made
+type BlockRecord struct {
+	Height     int64         `json:"height"`
+	AppHash    []byte        `json:"app_hash"`
+	BeginBlock []StoreKVPair `json:"begin_block"`
+	Txs        []TxRecord    `json:"txs"`
+	EndBlock   []StoreKVPair `json:"end_block"`
+}
+
+// FileService appends one length prefixed BlockRecord per block to a file.
+// It implements baseapp.StreamingService.
+type FileService struct {
+	mtx     sync.Mutex
+	cdc     *wire.Codec
+	file    *os.File
+	block   BlockRecord
+	pending []StoreKVPair // writes since the last boundary
+}
+
+// NewFileService appends the records to FileName in dir
+func NewFileService(dir string) (*FileService, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(filepath.Join(dir, FileName), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileService{cdc: wire.NewCodec(), file: file}, nil
+}
+
+// OnWrite implements sdk.WriteListener
+func (fs *FileService) OnWrite(storeKey sdk.StoreKey, key []byte, value []byte, delete bool) {
+	fs.mtx.Lock()
+	defer fs.mtx.Unlock()
+	fs.pending = append(fs.pending, StoreKVPair{
+		StoreKey: storeKey.Name(),
+		Delete:   delete,
+		Key:      append([]byte{}, key...),
+		Value:    append([]byte(nil), value...),
+	})
+}
+
+// ListenBeginBlock starts the record of the block
+func (fs *FileService) ListenBeginBlock(req wrsp.RequestBeginBlock) {
+	fs.mtx.Lock()
+	defer fs.mtx.Unlock()
+	fs.block.Height = req.Header.Height
+	fs.block.BeginBlock = fs.flush()
+}
+
+// ListenDeliverTx adds the transaction to the record of the block
+func (fs *FileService) ListenDeliverTx(txBytes []byte, res wrsp.ResponseDeliverTx) {
+	fs.mtx.Lock()
+	defer fs.mtx.Unlock()
+	fs.block.Txs = append(fs.block.Txs, TxRecord{
+		Hash:    tmtypes.Tx(txBytes).Hash(),
+		Code:    res.Code,
+		Changes: fs.flush(),
+	})
+}
+
+// ListenCommit writes the record of the block and syncs the file, so that
+// the record of a committed block survives a crash
+func (fs *FileService) ListenCommit(commitID sdk.CommitID) error {
+	fs.mtx.Lock()
+	defer fs.mtx.Unlock()
+	fs.block.AppHash = commitID.Hash
+	fs.block.EndBlock = fs.flush()
+	bz, err := fs.cdc.MarshalBinary(fs.block)
+	fs.block = BlockRecord{}
+	if err != nil {
+		return err
+	}
+	_, err = fs.file.Write(bz)
+	if err != nil {
+		return err
+	}
+	return fs.file.Sync()
+}
+
+// Close closes the file
+func (fs *FileService) Close() error {
+	return fs.file.Close()
+}
+
+func (fs *FileService) flush() []StoreKVPair {
+	pending := fs.pending
+	fs.pending = nil
+	return pending
+}
+
+// ReadBlockRecord reads the next record written by a FileService from r,
+// it returns io.EOF if there are no more records
+func ReadBlockRecord(r io.Reader) (record BlockRecord, err error) {
+	_, err = wire.NewCodec().UnmarshalBinaryReader(r, &record, maxRecordSize)
+	return record, err
+}
@@ -0,0 +1,56 @@
+package streaming
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	tmtypes "github.com/tepleton/tepleton/types"
+	wrsp "github.com/tepleton/wrsp/types"
+
+	sdk "github.com/tepleton/tepleton-sdk/types"
+)
+
+func TestFileService(t *testing.T) {
+	dir, err := ioutil.TempDir("", "streaming")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	fs, err := NewFileService(dir)
+	require.Nil(t, err)
+	key := sdk.NewKVStoreKey("main")
+	txBytes := []byte("tx")
+
+	for height := int64(1); height <= 2; height++ {
+		fs.OnWrite(key, []byte("begin"), []byte("1"), false)
+		fs.ListenBeginBlock(wrsp.RequestBeginBlock{Header: wrsp.Header{Height: height}})
+		fs.OnWrite(key, []byte("tx"), nil, true)
+		fs.ListenDeliverTx(txBytes, wrsp.ResponseDeliverTx{Code: 1})
+		fs.OnWrite(key, []byte("end"), []byte("2"), false)
+		err = fs.ListenCommit(sdk.CommitID{Version: height, Hash: []byte("hash")})
+		require.Nil(t, err)
+	}
+	require.Nil(t, fs.Close())
+
+	file, err := os.Open(filepath.Join(dir, FileName))
+	require.Nil(t, err)
+	defer file.Close()
+	for height := int64(1); height <= 2; height++ {
+		record, err := ReadBlockRecord(file)
+		require.Nil(t, err)
+		require.Equal(t, height, record.Height)
+		require.Equal(t, []byte("hash"), record.AppHash)
+		require.Equal(t, []StoreKVPair{{StoreKey: "main", Key: []byte("begin"), Value: []byte("1")}}, record.BeginBlock)
+		require.Len(t, record.Txs, 1)
+		require.Equal(t, []byte(tmtypes.Tx(txBytes).Hash()), record.Txs[0].Hash)
+		require.Equal(t, uint32(1), record.Txs[0].Code)
+		require.Equal(t, "tx", string(record.Txs[0].Changes[0].Key))
+		require.True(t, record.Txs[0].Changes[0].Delete)
+		require.Equal(t, "end", string(record.EndBlock[0].Key))
+	}
+	_, err = ReadBlockRecord(file)
+	require.Equal(t, io.EOF, err)
+}
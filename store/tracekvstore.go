@@ -0,0 +1,198 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Operations written to a store trace
+const (
+	TraceRead      = "read"
+	TraceWrite     = "write"
+	TraceDelete    = "delete"
+	TraceIterKey   = "iterKey"
+	TraceIterValue = "iterValue"
+)
+
+// TraceOperation is a line of a store trace
+type TraceOperation struct {
+	Operation string       `json:"operation"`
+	Store     string       `json:"store"`
+	Key       []byte       `json:"key"`
+	Value     []byte       `json:"value"`
+	Metadata  TraceContext `json:"metadata"`
+}
+
+// tracer writes the trace of the stores of a rootMultiStore, it is shared
+// by all the caches of the root.
+type tracer struct {
+	mtx     sync.Mutex
+	w       io.Writer
+	context TraceContext
+}
+
+func (tr *tracer) setContext(tc TraceContext) {
+	tr.mtx.Lock()
+	defer tr.mtx.Unlock()
+	tr.context = tc
+}
+
+func (tr *tracer) trace(operation string, storeKey StoreKey, key, value []byte) {
+	tr.mtx.Lock()
+	defer tr.mtx.Unlock()
+	bz, err := json.Marshal(TraceOperation{
+		Operation: operation,
+		Store:     storeKey.Name(),
+		Key:       key,
+		Value:     value,
+		Metadata:  tr.context,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("failed to encode store trace: %v", err))
+	}
+	// tracing is for debugging only, a failing writer must not halt the chain
+	tr.w.Write(append(bz, '\n'))
+}
+
+//----------------------------------------
+
+var _ KVStore = (*traceKVStore)(nil)
+
+// traceKVStore traces every operation on its parent
+type traceKVStore struct {
+	parent   KVStore
+	storeKey StoreKey
+	tracer   *tracer
+}
+
+func newTraceKVStore(parent KVStore, storeKey StoreKey, tr *tracer) *traceKVStore {
+	return &traceKVStore{parent: parent, storeKey: storeKey, tracer: tr}
+}
+
+// Implements Store.
+func (ts *traceKVStore) GetStoreType() StoreType {
+	return ts.parent.GetStoreType()
+}
+
+// Implements KVStore.
+func (ts *traceKVStore) Get(key []byte) []byte {
+	value := ts.parent.Get(key)
+	ts.tracer.trace(TraceRead, ts.storeKey, key, value)
+	return value
+}
+
+// Implements KVStore.
+func (ts *traceKVStore) Has(key []byte) bool {
+	return ts.Get(key) != nil
+}
+
+// Implements KVStore.
+func (ts *traceKVStore) Set(key []byte, value []byte) {
+	ts.tracer.trace(TraceWrite, ts.storeKey, key, value)
+	ts.parent.Set(key, value)
+}
+
+// Implements KVStore.
+func (ts *traceKVStore) Delete(key []byte) {
+	ts.tracer.trace(TraceDelete, ts.storeKey, key, nil)
+	ts.parent.Delete(key)
+}
+
+// Implements KVStore.
+func (ts *traceKVStore) Iterator(start, end []byte) Iterator {
+	return &traceIterator{ts.parent.Iterator(start, end), ts}
+}
+
+// Implements KVStore.
+func (ts *traceKVStore) ReverseIterator(start, end []byte) Iterator {
+	return &traceIterator{ts.parent.ReverseIterator(start, end), ts}
+}
+
+// Implements KVStore.
+func (ts *traceKVStore) CacheWrap() CacheWrap {
+	return NewCacheKVStore(ts)
+}
+
+// traceIterator traces the keys and values read from an iterator
+type traceIterator struct {
+	Iterator
+	store *traceKVStore
+}
+
+func (ti *traceIterator) Key() []byte {
+	key := ti.Iterator.Key()
+	ti.store.tracer.trace(TraceIterKey, ti.store.storeKey, key, nil)
+	return key
+}
+
+func (ti *traceIterator) Value() []byte {
+	value := ti.Iterator.Value()
+	ti.store.tracer.trace(TraceIterValue, ti.store.storeKey, nil, value)
+	return value
+}
+
+//----------------------------------------
+
+// TraceDivergence is the first line at which two store traces differ.
+// A or B is nil if its trace ended before the other one.
+type TraceDivergence struct {
+	Line int
+	A, B *TraceOperation
+}
+
+// FirstTraceDivergence compares two store traces line by line, it returns
+// nil if they are identical
+func FirstTraceDivergence(a, b io.Reader) (*TraceDivergence, error) {
+	scanA, scanB := newTraceScanner(a), newTraceScanner(b)
+	for line := 1; ; line++ {
+		okA, okB := scanA.Scan(), scanB.Scan()
+		if err := scanA.Err(); err != nil {
+			return nil, err
+		}
+		if err := scanB.Err(); err != nil {
+			return nil, err
+		}
+		if !okA && !okB {
+			return nil, nil
+		}
+		if okA && okB && bytes.Equal(scanA.Bytes(), scanB.Bytes()) {
+			continue
+		}
+
+		div := &TraceDivergence{Line: line}
+		var err error
+		if okA {
+			div.A, err = parseTraceOperation(scanA.Bytes(), line)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if okB {
+			div.B, err = parseTraceOperation(scanB.Bytes(), line)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return div, nil
+	}
+}
+
+func newTraceScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	// values can be much larger than the default token size
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	return scanner
+}
+
+func parseTraceOperation(bz []byte, line int) (*TraceOperation, error) {
+	var op TraceOperation
+	err := json.Unmarshal(bz, &op)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trace at line %d: %v", line, err)
+	}
+	return &op, nil
+}
@@ -0,0 +1,109 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tepleton/tmlibs/db"
+
+	sdk "github.com/tepleton/tepleton-sdk/types"
+)
+
+func readTrace(t *testing.T, buf *bytes.Buffer) []TraceOperation {
+	var ops []TraceOperation
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var op TraceOperation
+		require.Nil(t, json.Unmarshal([]byte(line), &op))
+		ops = append(ops, op)
+	}
+	buf.Reset()
+	return ops
+}
+
+func TestTraceKVStore(t *testing.T) {
+	var buf bytes.Buffer
+	tr := &tracer{w: &buf, context: TraceContext{"blockHeight": float64(3)}}
+	key := sdk.NewKVStoreKey("store")
+	ts := newTraceKVStore(dbStoreAdapter{dbm.NewMemDB()}, key, tr)
+
+	ts.Set([]byte("a"), []byte("1"))
+	require.Equal(t, []byte("1"), ts.Get([]byte("a")))
+	ts.Delete([]byte("a"))
+	require.Equal(t, []TraceOperation{
+		{TraceWrite, "store", []byte("a"), []byte("1"), TraceContext{"blockHeight": float64(3)}},
+		{TraceRead, "store", []byte("a"), []byte("1"), TraceContext{"blockHeight": float64(3)}},
+		{TraceDelete, "store", []byte("a"), nil, TraceContext{"blockHeight": float64(3)}},
+	}, readTrace(t, &buf))
+
+	ts.Set([]byte("b"), []byte("2"))
+	buf.Reset()
+	iter := ts.Iterator(nil, nil)
+	require.Equal(t, []byte("b"), iter.Key())
+	require.Equal(t, []byte("2"), iter.Value())
+	iter.Close()
+	ops := readTrace(t, &buf)
+	require.Len(t, ops, 2)
+	require.Equal(t, TraceIterKey, ops[0].Operation)
+	require.Equal(t, TraceIterValue, ops[1].Operation)
+}
+
+func TestMultiStoreTracing(t *testing.T) {
+	var buf bytes.Buffer
+	multi := newMultiStoreWithMounts(dbm.NewMemDB())
+	require.Nil(t, multi.LoadLatestVersion())
+	key := multi.keysByName["store1"]
+
+	multi.SetTracer(&buf)
+	require.True(t, multi.TracingEnabled())
+	multi.SetTracingContext(TraceContext{"blockHeight": 1})
+
+	// only the operations on the deliver state are traced, not the
+	// writes to the underlying stores
+	multi.CacheMultiStore().GetKVStore(key).Set([]byte("a"), []byte("1"))
+	cms := multi.CacheMultiStoreWithListeners()
+	txCache := cms.CacheMultiStore()
+	multi.SetTracingContext(TraceContext{"blockHeight": 1, "txIndex": 0})
+	txCache.GetKVStore(key).Set([]byte("b"), []byte("2"))
+	txCache.Write()
+	cms.Write()
+	require.Equal(t, []TraceOperation{
+		{TraceWrite, "store1", []byte("b"), []byte("2"), TraceContext{"blockHeight": float64(1), "txIndex": float64(0)}},
+	}, readTrace(t, &buf))
+
+	multi.SetTracer(nil)
+	require.False(t, multi.TracingEnabled())
+}
+
+func TestFirstTraceDivergence(t *testing.T) {
+	a := `{"operation":"write","store":"main","key":"YQ==","value":"MQ==","metadata":{"blockHeight":1}}
+{"operation":"write","store":"main","key":"Yg==","value":"Mg==","metadata":{"blockHeight":1}}
+`
+	b := `{"operation":"write","store":"main","key":"YQ==","value":"MQ==","metadata":{"blockHeight":1}}
+{"operation":"write","store":"main","key":"Yg==","value":"Mw==","metadata":{"blockHeight":1}}
+`
+	div, err := FirstTraceDivergence(strings.NewReader(a), strings.NewReader(a))
+	require.Nil(t, err)
+	require.Nil(t, div)
+
+	div, err = FirstTraceDivergence(strings.NewReader(a), strings.NewReader(b))
+	require.Nil(t, err)
+	require.Equal(t, 2, div.Line)
+	require.Equal(t, []byte("2"), div.A.Value)
+	require.Equal(t, []byte("3"), div.B.Value)
+
+	// a trace that ends early diverges too
+	div, err = FirstTraceDivergence(strings.NewReader(a), strings.NewReader(strings.SplitAfter(b, "\n")[0]))
+	require.Nil(t, err)
+	require.Equal(t, 2, div.Line)
+	require.NotNil(t, div.A)
+	require.Nil(t, div.B)
+
+	_, err = FirstTraceDivergence(strings.NewReader(a), strings.NewReader("garbage\n"))
+	require.NotNil(t, err)
+}
@@ -0,0 +1,43 @@
+package store
+
+import (
+	dbm "github.com/tepleton/tmlibs/db"
+
+	sdk "github.com/tepleton/tepleton-sdk/types"
+)
+
+var _ KVStore = (*transientStore)(nil)
+var _ CommitStore = (*transientStore)(nil)
+
+// transientStore is a KVStore in memory that is emptied on every commit.
+// It is used for data that only lives for a block, it is never part of
+// the commit info and so never affects the app hash.
+type transientStore struct {
+	dbStoreAdapter
+}
+
+func newTransientStore() *transientStore {
+	return &transientStore{dbStoreAdapter{dbm.NewMemDB()}}
+}
+
+// Implements Committer.
+// Drops all data, the returned CommitID is always empty.
+func (ts *transientStore) Commit() CommitID {
+	ts.dbStoreAdapter = dbStoreAdapter{dbm.NewMemDB()}
+	return CommitID{}
+}
+
+// Implements Committer.
+func (ts *transientStore) LastCommitID() CommitID {
+	return CommitID{}
+}
+
+// Implements Store.
+func (ts *transientStore) GetStoreType() StoreType {
+	return sdk.StoreTypeTransient
+}
+
+// Implements KVStore.
+func (ts *transientStore) CacheWrap() CacheWrap {
+	return NewCacheKVStore(ts)
+}
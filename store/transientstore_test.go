@@ -0,0 +1,60 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tepleton/tmlibs/db"
+
+	sdk "github.com/tepleton/tepleton-sdk/types"
+)
+
+func TestTransientStore(t *testing.T) {
+	tstore := newTransientStore()
+	k, v := []byte("key"), []byte("value")
+
+	// cache wrapped writes reach the store on write
+	cache := tstore.CacheWrap().(CacheKVStore)
+	cache.Set(k, v)
+	require.Nil(t, tstore.Get(k))
+	cache.Write()
+	require.Equal(t, v, tstore.Get(k))
+
+	// gas is consumed as for any other store
+	meter := sdk.NewGasMeter(1000)
+	require.Equal(t, v, NewGasKVStore(meter, tstore).Get(k))
+	require.NotZero(t, meter.GasConsumed())
+
+	// commit empties the store
+	require.Equal(t, CommitID{}, tstore.Commit())
+	require.Nil(t, tstore.Get(k))
+	require.Equal(t, CommitID{}, tstore.LastCommitID())
+}
+
+func TestMultiStoreTransient(t *testing.T) {
+	db := dbm.NewMemDB()
+	k, v := []byte("key"), []byte("value")
+	key := sdk.NewTransientStoreKey("transient")
+
+	multi := newMultiStoreWithMounts(db)
+	multi.MountStoreWithDB(key, sdk.StoreTypeTransient, nil)
+	require.Nil(t, multi.LoadLatestVersion())
+	multi.GetKVStore(key).Set(k, v)
+
+	// the transient store is reset and not part of the commit info
+	cid := multi.Commit()
+	require.Nil(t, multi.GetKVStore(key).Get(k))
+	cInfo, err := getCommitInfo(db, cid.Version)
+	require.Nil(t, err)
+	for _, storeInfo := range cInfo.StoreInfos {
+		require.NotEqual(t, "transient", storeInfo.Name)
+	}
+	delete(multi.stores, key)
+	require.Equal(t, getExpectedCommitID(multi, 1), cid)
+
+	// and can be mounted on reload without any upgrades
+	multi = newMultiStoreWithMounts(db)
+	multi.MountStoreWithDB(key, sdk.StoreTypeTransient, nil)
+	require.Nil(t, multi.LoadLatestVersion())
+	require.Equal(t, cid, multi.LastCommitID())
+}
@@ -14,6 +14,8 @@ type CacheMultiStore = types.CacheMultiStore
 type CommitMultiStore = types.CommitMultiStore
 type KVStore = types.KVStore
 type KVPair = types.KVPair
+type SubspaceQuery = types.SubspaceQuery
+type SubspaceResult = types.SubspaceResult
 type Iterator = types.Iterator
 type CacheKVStore = types.CacheKVStore
 type CommitKVStore = types.CommitKVStore
@@ -23,3 +25,7 @@ type CommitID = types.CommitID
 type StoreKey = types.StoreKey
 type StoreType = types.StoreType
 type Queryable = types.Queryable
+type StoreUpgrades = types.StoreUpgrades
+type StoreRename = types.StoreRename
+type WriteListener = types.WriteListener
+type TraceContext = types.TraceContext
@@ -13,3 +13,12 @@ type EndBlocker func(ctx Context, req wrsp.RequestEndBlock) wrsp.ResponseEndBloc
 
 // respond to p2p filtering queries from Tendermint
 type PeerFilter func(info string) wrsp.ResponseQuery
+
+// check a property of the state that must always hold, returning an error
+// describing the violation if it doesn't
+type Invariant func(ctx Context) error
+
+// collects the invariants of the modules, such as the BaseApp
+type InvariantRegistry interface {
+	RegisterInvariant(name string, check Invariant)
+}
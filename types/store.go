@@ -2,6 +2,7 @@ package types
 
 import (
 	"fmt"
+	"io"
 
 	wrsp "github.com/tepleton/wrsp/types"
 	cmn "github.com/tepleton/tmlibs/common"
@@ -82,6 +83,109 @@ type CommitMultiStore interface {
 	// the next commit after loading must be idempotent (return the
 	// same commit id).  Otherwise the behavior is undefined.
 	LoadVersion(ver int64) error
+
+	// Load the latest persisted version, applying upgrades to the
+	// mounted stores. See StoreUpgrades.
+	LoadLatestVersionAndUpgrade(upgrades *StoreUpgrades) error
+
+	// Load a specific persisted version, applying upgrades to the
+	// mounted stores. See StoreUpgrades.
+	LoadVersionAndUpgrade(ver int64, upgrades *StoreUpgrades) error
+
+	// Register listeners notified of every write to the store of key
+	// made through a CacheMultiStoreWithListeners.
+	AddListeners(key StoreKey, listeners []WriteListener)
+
+	// ListeningEnabled is true if listeners are registered for key.
+	ListeningEnabled(key StoreKey) bool
+
+	// CacheMultiStoreWithListeners is a CacheMultiStore that reports every
+	// write to it, including the writes of its own caches, to the
+	// registered listeners. It is used for the DeliverTx state.
+	// If a tracer is set, all operations on its stores are traced.
+	CacheMultiStoreWithListeners() CacheMultiStore
+
+	// SetTracer traces the operations on the stores to w as JSON lines,
+	// or stops tracing if w is nil.
+	SetTracer(w io.Writer)
+
+	// SetTracingContext replaces the metadata of the traced operations,
+	// such as the block height.
+	SetTracingContext(tc TraceContext)
+
+	// TracingEnabled is true if a tracer is set.
+	TracingEnabled() bool
+}
+
+// TraceContext is the metadata written along with traced store operations
+type TraceContext map[string]interface{}
+
+// WriteListener is notified of the writes to the stores it is registered
+// for, see CommitMultiStore.AddListeners
+type WriteListener interface {
+	// OnWrite is called for every Set and Delete, value is nil for deletes
+	OnWrite(storeKey StoreKey, key []byte, value []byte, delete bool)
+}
+
+// StoreRename moves the data of the store OldKey to the store NewKey
+type StoreRename struct {
+	OldKey string `json:"old_key"`
+	NewKey string `json:"new_key"`
+}
+
+// StoreUpgrades describes how the mounted stores differ from the stores
+// of the version being loaded, e.g. when a module is added to a running
+// chain. Added stores start empty, renamed stores keep their data under
+// the new name and deleted stores are pruned.
+type StoreUpgrades struct {
+	Added   []string      `json:"added"`
+	Renamed []StoreRename `json:"renamed"`
+	Deleted []string      `json:"deleted"`
+}
+
+// IsAdded is true if the store named key is added by the upgrades
+func (s *StoreUpgrades) IsAdded(key string) bool {
+	if s == nil {
+		return false
+	}
+	for _, added := range s.Added {
+		if key == added {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDeleted is true if the store named key is deleted by the upgrades
+func (s *StoreUpgrades) IsDeleted(key string) bool {
+	if s == nil {
+		return false
+	}
+	for _, deleted := range s.Deleted {
+		if key == deleted {
+			return true
+		}
+	}
+	return false
+}
+
+// RenamedFrom returns the old name of the store named key, or "" if the
+// upgrades don't rename it
+func (s *StoreUpgrades) RenamedFrom(key string) string {
+	if s == nil {
+		return ""
+	}
+	for _, rename := range s.Renamed {
+		if rename.NewKey == key {
+			return rename.OldKey
+		}
+	}
+	return ""
+}
+
+// IsEmpty is true if the upgrades don't change any store
+func (s *StoreUpgrades) IsEmpty() bool {
+	return s == nil || len(s.Added)+len(s.Renamed)+len(s.Deleted) == 0
 }
 
 //---------subsp-------------------------------
@@ -201,6 +305,7 @@ const (
 	StoreTypeMulti StoreType = iota
 	StoreTypeDB
 	StoreTypeIAVL
+	StoreTypeTransient
 )
 
 //----------------------------------------
@@ -234,6 +339,29 @@ func (key *KVStoreKey) String() string {
 	return fmt.Sprintf("KVStoreKey{%p, %s}", key, key.name)
 }
 
+// TransientStoreKey is used for accessing transient substores, which are
+// reset on every commit and never part of the app hash.
+// Only the pointer value should ever be used - it functions as a capabilities key.
+type TransientStoreKey struct {
+	name string
+}
+
+// NewTransientStoreKey returns a new pointer to a TransientStoreKey.
+// Use a pointer so keys don't collide.
+func NewTransientStoreKey(name string) *TransientStoreKey {
+	return &TransientStoreKey{
+		name: name,
+	}
+}
+
+func (key *TransientStoreKey) Name() string {
+	return key.name
+}
+
+func (key *TransientStoreKey) String() string {
+	return fmt.Sprintf("TransientStoreKey{%p, %s}", key, key.name)
+}
+
 // PrefixEndBytes returns the []byte that would end a
 // range query for all []byte with a certain prefix
 // Deals with last byte of prefix being FF without overflowing
@@ -264,3 +392,26 @@ func PrefixEndBytes(prefix []byte) []byte {
 
 // key-value result for iterator queries
 type KVPair cmn.KVPair
+
+// MaxSubspaceQueryLimit bounds the number of pairs a subspace query returns
+const MaxSubspaceQueryLimit = 1000
+
+// SubspaceQuery selects a page of the pairs whose key starts with
+// Subspace, it is the data of "/subspace-page" store queries.
+type SubspaceQuery struct {
+	Subspace []byte `json:"subspace"`
+	// Start is the first key of the page, or the last key if Reverse.
+	// The page starts at the end of the subspace if empty.
+	Start []byte `json:"start"`
+	// Limit is the max number of pairs in the page, at most and by
+	// default MaxSubspaceQueryLimit
+	Limit   int  `json:"limit"`
+	Reverse bool `json:"reverse"`
+}
+
+// SubspaceResult is a page of a subspace query
+type SubspaceResult struct {
+	KVs []KVPair `json:"kvs"`
+	// NextKey is the Start of the next page, nil on the last page
+	NextKey []byte `json:"next_key"`
+}
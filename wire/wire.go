@@ -6,6 +6,8 @@ import (
 
 	"github.com/tepleton/go-amino"
 	"github.com/tepleton/go-crypto"
+
+	ccrypto "github.com/tepleton/tepleton-sdk/crypto"
 )
 
 // amino codec to marshal/unmarshal
@@ -16,9 +18,10 @@ func NewCodec() *Codec {
 	return cdc
 }
 
-// Register the go-crypto to the codec
+// Register the go-crypto to the codec, and the key types of the sdk
 func RegisterCrypto(cdc *Codec) {
 	crypto.RegisterAmino(cdc)
+	ccrypto.RegisterAmino(cdc)
 }
 
 // attempt to make some pretty json
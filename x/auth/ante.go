@@ -17,6 +17,13 @@ const (
 // and increments sequence numbers, checks signatures & account numbers,
 // and deducts fees from the first signer.
 func NewAnteHandler(am AccountMapper, fck FeeCollectionKeeper) sdk.AnteHandler {
+	return NewAnteHandlerWithFeeGrants(am, fck, FeeGrantKeeper{})
+}
+
+// NewAnteHandlerWithFeeGrants returns an AnteHandler like NewAnteHandler,
+// except the fees of a tx with a fee granter are deducted from the granter,
+// if it granted them to the first signer.
+func NewAnteHandlerWithFeeGrants(am AccountMapper, fck FeeCollectionKeeper, fgk FeeGrantKeeper) sdk.AnteHandler {
 
 	return func(
 		ctx sdk.Context, tx sdk.Tx,
@@ -46,6 +53,13 @@ func NewAnteHandler(am AccountMapper, fck FeeCollectionKeeper) sdk.AnteHandler {
 				true
 		}
 
+		// Refuse txs which timed out
+		if stdTx.TimeoutHeight != 0 && ctx.BlockHeight() > stdTx.TimeoutHeight {
+			return ctx,
+				sdk.ErrUnauthorized(fmt.Sprintf("tx timed out at height %d", stdTx.TimeoutHeight)).Result(),
+				true
+		}
+
 		// Get the sign bytes (requires all account & sequence numbers and the fee)
 		sequences := make([]int64, len(signerAddrs))
 		for i := 0; i < len(signerAddrs); i++ {
@@ -62,7 +76,7 @@ func NewAnteHandler(am AccountMapper, fck FeeCollectionKeeper) sdk.AnteHandler {
 		if chainID == "" {
 			chainID = viper.GetString("chain-id")
 		}
-		signBytes := StdSignBytes(ctx.ChainID(), accNums, sequences, fee, msg)
+		signMsg := NewStdSignMsg(ctx.ChainID(), accNums, sequences, stdTx)
 
 		// Check sig and nonce and collect signer accounts.
 		var signerAccs = make([]Account, len(signerAddrs))
@@ -72,18 +86,22 @@ func NewAnteHandler(am AccountMapper, fck FeeCollectionKeeper) sdk.AnteHandler {
 			// check signature, return account with incremented nonce
 			signerAcc, res := processSig(
 				ctx, am,
-				signerAddr, sig, signBytes,
+				signerAddr, sig, signMsg,
 			)
 			if !res.IsOK() {
 				return ctx, res, true
 			}
 
-			// first sig pays the fees
+			// first sig pays the fees, or its fee granter
 			if i == 0 {
 				// TODO: min fee
 				if !fee.Amount.IsZero() {
 					ctx.GasMeter().ConsumeGas(deductFeesCost, "deductFees")
-					signerAcc, res = deductFees(signerAcc, fee)
+					if len(stdTx.FeeGranter) == 0 {
+						signerAcc, res = deductFees(signerAcc, fee)
+					} else {
+						res = deductGrantedFees(ctx, am, fgk, stdTx.FeeGranter, signerAddr, fee)
+					}
 					if !res.IsOK() {
 						return ctx, res, true
 					}
@@ -108,11 +126,12 @@ func NewAnteHandler(am AccountMapper, fck FeeCollectionKeeper) sdk.AnteHandler {
 	}
 }
 
-// verify the signature and increment the sequence.
+// verify the signature, over the sign message encoded in the sign mode of
+// the signature, and increment the sequence.
 // if the account doesn't have a pubkey, set it.
 func processSig(
 	ctx sdk.Context, am AccountMapper,
-	addr sdk.Address, sig StdSignature, signBytes []byte) (
+	addr sdk.Address, sig StdSignature, signMsg StdSignMsg) (
 	acc Account, res sdk.Result) {
 
 	// Get the account.
@@ -155,6 +174,10 @@ func processSig(
 	}
 
 	// Check sig.
+	signBytes, err := signMsg.BytesForMode(sig.SignMode)
+	if err != nil {
+		return nil, sdk.ErrUnauthorized(err.Error()).Result()
+	}
 	ctx.GasMeter().ConsumeGas(verifyCost, "ante verify")
 	if !pubKey.VerifyBytes(signBytes, sig.Signature) {
 		return nil, sdk.ErrUnauthorized("signature verification failed").Result()
@@ -179,5 +202,29 @@ func deductFees(acc Account, fee StdFee) (Account, sdk.Result) {
 	return acc, sdk.Result{}
 }
 
+// Deduct the fee from the granter, using its fee grant to the grantee.
+// The grantee can't be its own granter, since the AnteHandler saves the
+// grantee's account after this and would overwrite the deduction.
+func deductGrantedFees(ctx sdk.Context, am AccountMapper, fgk FeeGrantKeeper, granter, grantee sdk.Address, fee StdFee) sdk.Result {
+	if bytes.Equal(granter, grantee) {
+		return sdk.ErrUnauthorized("fee granter can't be the fee payer").Result()
+	}
+	grant, err := fgk.useFeeGrant(ctx, granter, grantee, fee.Amount)
+	if err != nil {
+		return err.Result()
+	}
+	granterAcc := am.GetAccount(ctx, granter)
+	if granterAcc == nil {
+		return sdk.ErrUnknownAddress(granter.String()).Result()
+	}
+	granterAcc, res := deductFees(granterAcc, fee)
+	if !res.IsOK() {
+		return res
+	}
+	am.SetAccount(ctx, granterAcc)
+	fgk.setUsedFeeGrant(ctx, grant)
+	return sdk.Result{}
+}
+
 // BurnFeeHandler burns all fees (decreasing total supply)
 func BurnFeeHandler(_ sdk.Context, _ sdk.Tx, _ sdk.Coins) {}
@@ -9,6 +9,7 @@ import (
 	crypto "github.com/tepleton/go-crypto"
 	"github.com/tepleton/tmlibs/log"
 
+	"github.com/tepleton/tepleton-sdk/crypto/keys"
 	sdk "github.com/tepleton/tepleton-sdk/types"
 	wire "github.com/tepleton/tepleton-sdk/wire"
 )
@@ -402,3 +403,95 @@ func TestAnteHandlerSetPubKey(t *testing.T) {
 	acc2 = mapper.GetAccount(ctx, addr2)
 	assert.Nil(t, acc2.GetPubKey())
 }
+
+// Test signing in textual sign mode and the timeout height of a tx.
+func TestAnteHandlerSignModeAndTimeout(t *testing.T) {
+	// setup
+	ms, capKey, capKey2 := setupMultiStore()
+	cdc := wire.NewCodec()
+	RegisterBaseAccount(cdc)
+	mapper := NewAccountMapper(cdc, capKey, &BaseAccount{})
+	feeCollector := NewFeeCollectionKeeper(cdc, capKey2)
+	anteHandler := NewAnteHandler(mapper, feeCollector)
+	ctx := sdk.NewContext(ms, wrsp.Header{ChainID: "mychainid", Height: 10}, false, nil, log.NewNopLogger())
+
+	// keys and addresses
+	priv1, addr1 := privAndAddr()
+
+	// set the accounts
+	acc1 := mapper.NewAccountWithAddress(ctx, addr1)
+	acc1.SetCoins(newCoins())
+	mapper.SetAccount(ctx, acc1)
+
+	msg := newTestMsg(addr1)
+	fee := newStdFee()
+	newTx := func(mode SignMode, seq int64, timeoutHeight int64) sdk.Tx {
+		tx := NewStdTx(msg, fee, nil).WithTimeoutHeight(timeoutHeight)
+		signBytes, err := NewStdSignMsg(ctx.ChainID(), []int64{0}, []int64{seq}, tx).BytesForMode(SignModeTextual)
+		require.Nil(t, err)
+		tx.Signatures = []StdSignature{{PubKey: priv1.PubKey(), Signature: priv1.Sign(signBytes), Sequence: seq, SignMode: mode}}
+		return tx
+	}
+
+	// textual signature checked against json sign bytes fails
+	checkInvalidTx(t, anteHandler, ctx, newTx(SignModeJSON, 0, 0), sdk.CodeUnauthorized)
+
+	// unknown sign mode
+	checkInvalidTx(t, anteHandler, ctx, newTx(SignMode(0x02), 0, 0), sdk.CodeUnauthorized)
+
+	// textual signature
+	checkValidTx(t, anteHandler, ctx, newTx(SignModeTextual, 0, 0))
+
+	// timeout height at the current block is fine, below it is refused
+	checkValidTx(t, anteHandler, ctx, newTx(SignModeTextual, 1, 10))
+	checkInvalidTx(t, anteHandler, ctx, newTx(SignModeTextual, 2, 9), sdk.CodeUnauthorized)
+
+	// the timeout height is signed over
+	tx := newTx(SignModeTextual, 2, 11).(StdTx).WithTimeoutHeight(12)
+	checkInvalidTx(t, anteHandler, ctx, tx, sdk.CodeUnauthorized)
+}
+
+// Test txs signed with keys of every signing algo of the keybase.
+func TestAnteHandlerSigningAlgos(t *testing.T) {
+	// setup
+	ms, capKey, capKey2 := setupMultiStore()
+	cdc := wire.NewCodec()
+	RegisterBaseAccount(cdc)
+	mapper := NewAccountMapper(cdc, capKey, &BaseAccount{})
+	feeCollector := NewFeeCollectionKeeper(cdc, capKey2)
+	anteHandler := NewAnteHandler(mapper, feeCollector)
+	ctx := sdk.NewContext(ms, wrsp.Header{ChainID: "mychainid"}, false, nil, log.NewNopLogger())
+
+	kb := keys.NewInMemory()
+	fee := newStdFee()
+	for accnum, algo := range []keys.SigningAlgo{keys.Secp256k1, keys.Ed25519, keys.Secp256r1} {
+		name := string(algo)
+		info, _, err := kb.CreateMnemonic(name, keys.English, "1234", "", algo)
+		require.NoError(t, err, name)
+
+		// set the account
+		addr := sdk.Address(info.GetPubKey().Address())
+		acc := mapper.NewAccountWithAddress(ctx, addr)
+		acc.SetCoins(newCoins())
+		mapper.SetAccount(ctx, acc)
+
+		msg := newTestMsg(addr)
+		newTx := func(seq int64, signBytes []byte) sdk.Tx {
+			sig, pub, err := kb.Sign(name, "1234", signBytes)
+			require.NoError(t, err, name)
+			return NewStdTx(msg, fee, []StdSignature{{PubKey: pub, Signature: sig, AccountNumber: int64(accnum), Sequence: seq}})
+		}
+
+		// a signature over other bytes is refused
+		signBytes := StdSignBytes("otherchainid", []int64{int64(accnum)}, []int64{0}, fee, msg)
+		checkInvalidTx(t, anteHandler, ctx, newTx(0, signBytes), sdk.CodeUnauthorized)
+
+		// the first tx sets the public key, the second is checked against it
+		for seq := int64(0); seq < 2; seq++ {
+			signBytes = StdSignBytes(ctx.ChainID(), []int64{int64(accnum)}, []int64{seq}, fee, msg)
+			checkValidTx(t, anteHandler, ctx, newTx(seq, signBytes))
+		}
+		acc = mapper.GetAccount(ctx, addr)
+		require.Equal(t, info.GetPubKey(), acc.GetPubKey())
+	}
+}
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tepleton/tepleton-sdk/client/context"
+	"github.com/tepleton/tepleton-sdk/client/keys"
+	sdk "github.com/tepleton/tepleton-sdk/types"
+	"github.com/tepleton/tepleton-sdk/wire"
+	"github.com/tepleton/tepleton-sdk/x/auth"
+)
+
+const (
+	flagGrantee    = "grantee"
+	flagSpendLimit = "spend-limit"
+	flagExpiration = "expiration"
+)
+
+// GetCmdQueryFeeGrants returns the command to query the fee grants to a grantee
+func GetCmdQueryFeeGrants(storeName string, cdc *wire.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "fee-grants [grantee]",
+		Short: "Query the fee grants to an address",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			grantee, err := keys.GetAccAddress(args[0])
+			if err != nil {
+				return err
+			}
+
+			ctx := context.NewCoreContextFromViper()
+			resKVs, err := ctx.QuerySubspace(cdc, auth.GetFeeGrantsKey(grantee), storeName)
+			if err != nil {
+				return err
+			}
+			grants := make([]auth.FeeGrant, len(resKVs))
+			for i, kv := range resKVs {
+				cdc.MustUnmarshalBinary(kv.Value, &grants[i])
+			}
+
+			output, err := wire.MarshalJSONIndent(cdc, grants)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(output))
+			return nil
+		},
+	}
+}
+
+// GetCmdGrantFeeAllowance returns the command to let a grantee pay its fees
+// with the coins of the signer
+func GetCmdGrantFeeAllowance(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "grant-fees",
+		Short: "Allow an address to pay its fees with your coins",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(GetAccountDecoder(cdc))
+
+			granter, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			grantee, err := keys.GetAccAddress(viper.GetString(flagGrantee))
+			if err != nil {
+				return err
+			}
+			spendLimit, err := sdk.ParseCoins(viper.GetString(flagSpendLimit))
+			if err != nil {
+				return err
+			}
+
+			msg := auth.NewMsgGrantFeeAllowance(granter, grantee, spendLimit, viper.GetInt64(flagExpiration))
+			res, err := ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, msg, cdc)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Committed at block %d. Hash: %s\n", res.Height, res.Hash.String())
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagGrantee, "", "Address allowed to pay its fees with your coins")
+	cmd.Flags().String(flagSpendLimit, "", "Maximum amount of fees paid for the grantee")
+	cmd.Flags().Int64(flagExpiration, 0, "Block time the grant expires at, 0 if it doesn't")
+	return cmd
+}
+
+// GetCmdRevokeFeeAllowance returns the command to revoke a fee grant of the signer
+func GetCmdRevokeFeeAllowance(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "revoke-fees",
+		Short: "Revoke the fee grant to an address",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(GetAccountDecoder(cdc))
+
+			granter, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			grantee, err := keys.GetAccAddress(viper.GetString(flagGrantee))
+			if err != nil {
+				return err
+			}
+
+			msg := auth.NewMsgRevokeFeeAllowance(granter, grantee)
+			res, err := ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, msg, cdc)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Committed at block %d. Hash: %s\n", res.Height, res.Hash.String())
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagGrantee, "", "Address whose fee grant is revoked")
+	return cmd
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/tepleton/tepleton-sdk/client/context"
+	"github.com/tepleton/tepleton-sdk/client/openapi"
 	sdk "github.com/tepleton/tepleton-sdk/types"
 	"github.com/tepleton/tepleton-sdk/wire"
 	"github.com/tepleton/tepleton-sdk/x/auth"
@@ -14,11 +15,14 @@ import (
 )
 
 // register REST routes
-func RegisterRoutes(ctx context.CoreContext, r *mux.Router, cdc *wire.Codec, storeName string) {
-	r.HandleFunc(
-		"/accounts/{address}",
-		QueryAccountRequestHandlerFn(storeName, cdc, authcmd.GetAccountDecoder(cdc), ctx),
-	).Methods("GET")
+func RegisterRoutes(ctx context.CoreContext, r *openapi.Router, cdc *wire.Codec, storeName string) {
+	r.Handle(openapi.Route{
+		Method:   "GET",
+		Path:     "/accounts/{address}",
+		Summary:  "Get the account at a bech32 address",
+		Response: auth.BaseAccount{},
+		Handler:  QueryAccountRequestHandlerFn(storeName, cdc, authcmd.GetAccountDecoder(cdc), ctx),
+	})
 }
 
 // query accountREST Handler
@@ -0,0 +1,195 @@
+package auth
+
+import (
+	"bytes"
+	"fmt"
+
+	sdk "github.com/tepleton/tepleton-sdk/types"
+	wire "github.com/tepleton/tepleton-sdk/wire"
+)
+
+var (
+	feeGrantKey = []byte{0x00} // prefix for each fee grant, by grantee and granter
+)
+
+// get the key for the fee grant from granter to grantee
+func GetFeeGrantKey(grantee, granter sdk.Address) []byte {
+	return append(GetFeeGrantsKey(grantee), lengthPrefix(granter)...)
+}
+
+// get the prefix of the keys for all the fee grants to grantee.
+// The addresses are length-prefixed so that the keys of different
+// grantees and granters can't collide.
+func GetFeeGrantsKey(grantee sdk.Address) []byte {
+	return append(feeGrantKey, lengthPrefix(grantee)...)
+}
+
+// prefix the address bytes with their length
+func lengthPrefix(addr sdk.Address) []byte {
+	if len(addr) > 255 {
+		panic("address longer than 255 bytes")
+	}
+	return append([]byte{byte(len(addr))}, addr.Bytes()...)
+}
+
+// FeeGrant allows the grantee to pay the fees of its transactions with the
+// coins of the granter, up to the spend limit and until the expiration
+type FeeGrant struct {
+	Granter    sdk.Address `json:"granter"`
+	Grantee    sdk.Address `json:"grantee"`
+	SpendLimit sdk.Coins   `json:"spend_limit"`
+	Expiration int64       `json:"expiration"` // block time the grant expires at, 0 if it doesn't
+}
+
+// NewFeeGrant returns a new FeeGrant
+func NewFeeGrant(granter, grantee sdk.Address, spendLimit sdk.Coins, expiration int64) FeeGrant {
+	return FeeGrant{
+		Granter:    granter,
+		Grantee:    grantee,
+		SpendLimit: spendLimit,
+		Expiration: expiration,
+	}
+}
+
+// IsExpired returns whether the grant has expired at the block time
+func (grant FeeGrant) IsExpired(blockTime int64) bool {
+	return grant.Expiration != 0 && blockTime >= grant.Expiration
+}
+
+//__________________________________________________________
+
+// FeeGrantKeeper stores the fee grants, and uses them to pay the fees in
+// the AnteHandler
+type FeeGrantKeeper struct {
+
+	// The (unexposed) key used to access the fee grant store from the Context.
+	key sdk.StoreKey
+
+	// The wire codec for binary encoding/decoding of fee grants.
+	cdc *wire.Codec
+}
+
+// NewFeeGrantKeeper returns a new FeeGrantKeeper
+func NewFeeGrantKeeper(cdc *wire.Codec, key sdk.StoreKey) FeeGrantKeeper {
+	return FeeGrantKeeper{
+		key: key,
+		cdc: cdc,
+	}
+}
+
+// GetFeeGrant returns the fee grant from granter to grantee
+func (fgk FeeGrantKeeper) GetFeeGrant(ctx sdk.Context, granter, grantee sdk.Address) (grant FeeGrant, found bool) {
+	if fgk.key == nil {
+		return grant, false
+	}
+	store := ctx.KVStore(fgk.key)
+	bz := store.Get(GetFeeGrantKey(grantee, granter))
+	if bz == nil {
+		return grant, false
+	}
+	fgk.cdc.MustUnmarshalBinary(bz, &grant)
+	return grant, true
+}
+
+// GetFeeGrants returns all the fee grants to grantee
+func (fgk FeeGrantKeeper) GetFeeGrants(ctx sdk.Context, grantee sdk.Address) (grants []FeeGrant) {
+	if fgk.key == nil {
+		return nil
+	}
+	store := ctx.KVStore(fgk.key)
+	iterator := sdk.KVStorePrefixIterator(store, GetFeeGrantsKey(grantee))
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var grant FeeGrant
+		fgk.cdc.MustUnmarshalBinary(iterator.Value(), &grant)
+		grants = append(grants, grant)
+	}
+	return grants
+}
+
+// GetAllFeeGrants returns the fee grants to all the grantees
+func (fgk FeeGrantKeeper) GetAllFeeGrants(ctx sdk.Context) (grants []FeeGrant) {
+	if fgk.key == nil {
+		return nil
+	}
+	store := ctx.KVStore(fgk.key)
+	iterator := sdk.KVStorePrefixIterator(store, feeGrantKey)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var grant FeeGrant
+		fgk.cdc.MustUnmarshalBinary(iterator.Value(), &grant)
+		grants = append(grants, grant)
+	}
+	return grants
+}
+
+// SetFeeGrant sets a fee grant, replacing the previous one between the
+// same accounts
+func (fgk FeeGrantKeeper) SetFeeGrant(ctx sdk.Context, grant FeeGrant) {
+	store := ctx.KVStore(fgk.key)
+	store.Set(GetFeeGrantKey(grant.Grantee, grant.Granter), fgk.cdc.MustMarshalBinary(grant))
+}
+
+// RevokeFeeGrant removes the fee grant from granter to grantee
+func (fgk FeeGrantKeeper) RevokeFeeGrant(ctx sdk.Context, granter, grantee sdk.Address) {
+	store := ctx.KVStore(fgk.key)
+	store.Delete(GetFeeGrantKey(grantee, granter))
+}
+
+// useFeeGrant returns the grant from granter to grantee with fee taken from
+// its spend limit. The used grant isn't stored until setUsedFeeGrant, so it
+// is only updated once the granter has paid the fee. Expired grants are
+// removed.
+func (fgk FeeGrantKeeper) useFeeGrant(ctx sdk.Context, granter, grantee sdk.Address, fee sdk.Coins) (FeeGrant, sdk.Error) {
+	grant, found := fgk.GetFeeGrant(ctx, granter, grantee)
+	if !found {
+		return grant, sdk.ErrUnauthorized(fmt.Sprintf("no fee grant from %v to %v", granter, grantee))
+	}
+	if grant.IsExpired(ctx.BlockHeader().Time) {
+		fgk.RevokeFeeGrant(ctx, granter, grantee)
+		return grant, sdk.ErrUnauthorized(fmt.Sprintf("fee grant from %v to %v has expired", granter, grantee))
+	}
+	if !grant.SpendLimit.IsGTE(fee) {
+		return grant, sdk.ErrInsufficientFunds(fmt.Sprintf("fee grant spend limit %v < %v", grant.SpendLimit, fee))
+	}
+	grant.SpendLimit = grant.SpendLimit.Minus(fee)
+	return grant, nil
+}
+
+// setUsedFeeGrant stores a grant returned by useFeeGrant, removing it once
+// its spend limit is exhausted
+func (fgk FeeGrantKeeper) setUsedFeeGrant(ctx sdk.Context, grant FeeGrant) {
+	if grant.SpendLimit.IsZero() {
+		fgk.RevokeFeeGrant(ctx, grant.Granter, grant.Grantee)
+		return
+	}
+	fgk.SetFeeGrant(ctx, grant)
+}
+
+//__________________________________________________________
+
+// InitFeeGrantGenesis - set the fee grants of the genesis file
+func InitFeeGrantGenesis(ctx sdk.Context, fgk FeeGrantKeeper, grants []FeeGrant) error {
+	for _, grant := range grants {
+		if len(grant.Granter) == 0 || len(grant.Grantee) == 0 {
+			return fmt.Errorf("fee grant without granter or grantee in genesis state")
+		}
+		if bytes.Equal(grant.Granter, grant.Grantee) {
+			return fmt.Errorf("fee grant of %v to itself in genesis state", grant.Granter)
+		}
+		if !grant.SpendLimit.IsValid() {
+			return fmt.Errorf("invalid spend limit %v of the fee grant from %v to %v", grant.SpendLimit, grant.Granter, grant.Grantee)
+		}
+		fgk.SetFeeGrant(ctx, grant)
+	}
+	return nil
+}
+
+// WriteFeeGrantGenesis - output the fee grants for a genesis file
+func WriteFeeGrantGenesis(ctx sdk.Context, fgk FeeGrantKeeper) []FeeGrant {
+	grants := fgk.GetAllFeeGrants(ctx)
+	if grants == nil {
+		grants = []FeeGrant{}
+	}
+	return grants
+}
@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	crypto "github.com/tepleton/go-crypto"
+	dbm "github.com/tepleton/tmlibs/db"
+	"github.com/tepleton/tmlibs/log"
+	wrsp "github.com/tepleton/wrsp/types"
+
+	"github.com/tepleton/tepleton-sdk/store"
+	sdk "github.com/tepleton/tepleton-sdk/types"
+	wire "github.com/tepleton/tepleton-sdk/wire"
+)
+
+func setupFeeGrants() (sdk.Context, AccountMapper, FeeCollectionKeeper, FeeGrantKeeper) {
+	db := dbm.NewMemDB()
+	accKey := sdk.NewKVStoreKey("acc")
+	feeKey := sdk.NewKVStoreKey("fee")
+	grantKey := sdk.NewKVStoreKey("feegrant")
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(accKey, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(feeKey, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(grantKey, sdk.StoreTypeIAVL, db)
+	ms.LoadLatestVersion()
+
+	cdc := wire.NewCodec()
+	RegisterBaseAccount(cdc)
+	ctx := sdk.NewContext(ms, wrsp.Header{ChainID: "mychainid", Time: 100}, false, nil, log.NewNopLogger())
+	return ctx, NewAccountMapper(cdc, accKey, &BaseAccount{}),
+		NewFeeCollectionKeeper(cdc, feeKey), NewFeeGrantKeeper(cdc, grantKey)
+}
+
+func newTestTxWithFeeGranter(ctx sdk.Context, msg sdk.Msg, privs []crypto.PrivKey, accNums []int64, seqs []int64, fee StdFee, granter sdk.Address) sdk.Tx {
+	signBytes := StdSignBytesWithFeeGranter(ctx.ChainID(), accNums, seqs, fee, granter, msg)
+	tx := newTestTxWithSignBytes(msg, privs, accNums, seqs, fee, signBytes)
+	return tx.(StdTx).WithFeeGranter(granter)
+}
+
+func TestFeeGrantHandler(t *testing.T) {
+	ctx, mapper, _, fgk := setupFeeGrants()
+	handler := NewFeeGrantHandler(fgk, mapper)
+	_, granter := privAndAddr()
+	_, grantee := privAndAddr()
+
+	// grants can't expire in the past
+	res := handler(ctx, NewMsgGrantFeeAllowance(granter, grantee, sdk.Coins{{"atom", 100}}, 50))
+	assert.False(t, res.IsOK())
+
+	// the grant creates the grantee account
+	res = handler(ctx, NewMsgGrantFeeAllowance(granter, grantee, sdk.Coins{{"atom", 100}}, 200))
+	assert.True(t, res.IsOK())
+	assert.NotNil(t, mapper.GetAccount(ctx, grantee))
+	grant, found := fgk.GetFeeGrant(ctx, granter, grantee)
+	assert.True(t, found)
+	assert.Equal(t, NewFeeGrant(granter, grantee, sdk.Coins{{"atom", 100}}, 200), grant)
+	assert.Equal(t, []FeeGrant{grant}, fgk.GetFeeGrants(ctx, grantee))
+
+	res = handler(ctx, NewMsgRevokeFeeAllowance(granter, grantee))
+	assert.True(t, res.IsOK())
+	_, found = fgk.GetFeeGrant(ctx, granter, grantee)
+	assert.False(t, found)
+	res = handler(ctx, NewMsgRevokeFeeAllowance(granter, grantee))
+	assert.False(t, res.IsOK())
+
+	// grants to itself are invalid
+	assert.NotNil(t, NewMsgGrantFeeAllowance(granter, granter, sdk.Coins{{"atom", 100}}, 0).ValidateBasic())
+	assert.NotNil(t, NewMsgGrantFeeAllowance(granter, grantee, sdk.Coins{}, 0).ValidateBasic())
+}
+
+func TestAnteHandlerFeeGrants(t *testing.T) {
+	ctx, mapper, feeCollector, fgk := setupFeeGrants()
+	anteHandler := NewAnteHandlerWithFeeGrants(mapper, feeCollector, fgk)
+
+	_, granter := privAndAddr()
+	priv, grantee := privAndAddr()
+	granterAcc := mapper.NewAccountWithAddress(ctx, granter)
+	granterAcc.SetCoins(sdk.Coins{{"atom", 1000}})
+	mapper.SetAccount(ctx, granterAcc)
+	mapper.SetAccount(ctx, mapper.NewAccountWithAddress(ctx, grantee))
+
+	msg := newTestMsg(grantee)
+	privs, accnums := []crypto.PrivKey{priv}, []int64{1}
+	fee := NewStdFee(100, sdk.Coin{"atom", 150})
+
+	// no grant yet
+	tx := newTestTxWithFeeGranter(ctx, msg, privs, accnums, []int64{0}, fee, granter)
+	checkInvalidTx(t, anteHandler, ctx, tx, sdk.CodeUnauthorized)
+
+	// the granter pays within the spend limit
+	fgk.SetFeeGrant(ctx, NewFeeGrant(granter, grantee, sdk.Coins{{"atom", 200}}, 0))
+	checkValidTx(t, anteHandler, ctx, tx)
+	assert.True(t, mapper.GetAccount(ctx, granter).GetCoins().IsEqual(sdk.Coins{{"atom", 850}}))
+	assert.True(t, mapper.GetAccount(ctx, grantee).GetCoins().IsEqual(sdk.Coins{}))
+	assert.True(t, feeCollector.GetCollectedFees(ctx).IsEqual(sdk.Coins{{"atom", 150}}))
+	grant, _ := fgk.GetFeeGrant(ctx, granter, grantee)
+	assert.True(t, grant.SpendLimit.IsEqual(sdk.Coins{{"atom", 50}}))
+
+	// the rest of the spend limit doesn't cover the fee
+	tx = newTestTxWithFeeGranter(ctx, msg, privs, accnums, []int64{1}, fee, granter)
+	checkInvalidTx(t, anteHandler, ctx, tx, sdk.CodeInsufficientFunds)
+
+	// the fee granter is signed by the grantee
+	tx = newTestTx(ctx, msg, privs, accnums, []int64{1}, fee)
+	tx = tx.(StdTx).WithFeeGranter(granter)
+	checkInvalidTx(t, anteHandler, ctx, tx, sdk.CodeUnauthorized)
+
+	// the granter can't pay the fee, so its grant stays unused
+	fgk.SetFeeGrant(ctx, NewFeeGrant(granter, grantee, sdk.Coins{{"atom", 2000}}, 0))
+	tx = newTestTxWithFeeGranter(ctx, msg, privs, accnums, []int64{1}, NewStdFee(100, sdk.Coin{"atom", 900}), granter)
+	checkInvalidTx(t, anteHandler, ctx, tx, sdk.CodeInsufficientFunds)
+	grant, _ = fgk.GetFeeGrant(ctx, granter, grantee)
+	assert.True(t, grant.SpendLimit.IsEqual(sdk.Coins{{"atom", 2000}}))
+	assert.True(t, mapper.GetAccount(ctx, granter).GetCoins().IsEqual(sdk.Coins{{"atom", 850}}))
+
+	// the fee payer can't be its own fee granter
+	fgk.SetFeeGrant(ctx, NewFeeGrant(grantee, grantee, sdk.Coins{{"atom", 200}}, 0))
+	tx = newTestTxWithFeeGranter(ctx, msg, privs, accnums, []int64{1}, fee, grantee)
+	checkInvalidTx(t, anteHandler, ctx, tx, sdk.CodeUnauthorized)
+	fgk.RevokeFeeGrant(ctx, grantee, grantee)
+
+	// expired grants can't be used
+	fgk.SetFeeGrant(ctx, NewFeeGrant(granter, grantee, sdk.Coins{{"atom", 200}}, 100))
+	tx = newTestTxWithFeeGranter(ctx, msg, privs, accnums, []int64{1}, fee, granter)
+	checkInvalidTx(t, anteHandler, ctx, tx, sdk.CodeUnauthorized)
+}
+
+func TestFeeGrantGenesis(t *testing.T) {
+	ctx, _, _, fgk := setupFeeGrants()
+	_, granter := privAndAddr()
+	_, grantee := privAndAddr()
+	_, other := privAndAddr()
+
+	// nothing to export yet
+	assert.Equal(t, []FeeGrant{}, WriteFeeGrantGenesis(ctx, fgk))
+
+	grants := []FeeGrant{
+		NewFeeGrant(granter, grantee, sdk.Coins{{"atom", 200}}, 0),
+		NewFeeGrant(granter, other, sdk.Coins{{"atom", 50}}, 1000),
+	}
+	err := InitFeeGrantGenesis(ctx, fgk, grants)
+	assert.Nil(t, err)
+	exported := WriteFeeGrantGenesis(ctx, fgk)
+	assert.Equal(t, 2, len(exported))
+	for _, grant := range grants {
+		got, found := fgk.GetFeeGrant(ctx, grant.Granter, grant.Grantee)
+		assert.True(t, found)
+		assert.Equal(t, grant, got)
+	}
+
+	// grants without a grantee are refused
+	err = InitFeeGrantGenesis(ctx, fgk, []FeeGrant{NewFeeGrant(granter, nil, sdk.Coins{{"atom", 1}}, 0)})
+	assert.NotNil(t, err)
+	// so are grants to the granter itself
+	err = InitFeeGrantGenesis(ctx, fgk, []FeeGrant{NewFeeGrant(granter, granter, sdk.Coins{{"atom", 1}}, 0)})
+	assert.NotNil(t, err)
+}
+
+func TestFeeGrantKeys(t *testing.T) {
+	// the keys of different grantees and granters don't collide
+	key1 := GetFeeGrantKey(sdk.Address([]byte("ab")), sdk.Address([]byte("c")))
+	key2 := GetFeeGrantKey(sdk.Address([]byte("a")), sdk.Address([]byte("bc")))
+	assert.NotEqual(t, key1, key2)
+
+	ctx, _, _, fgk := setupFeeGrants()
+	fgk.SetFeeGrant(ctx, NewFeeGrant(sdk.Address([]byte("c")), sdk.Address([]byte("ab")), sdk.Coins{{"atom", 100}}, 0))
+	assert.Empty(t, fgk.GetFeeGrants(ctx, sdk.Address([]byte("a"))))
+	assert.Equal(t, 1, len(fgk.GetFeeGrants(ctx, sdk.Address([]byte("ab")))))
+}
@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"fmt"
+
 	sdk "github.com/tepleton/tepleton-sdk/types"
 	wire "github.com/tepleton/tepleton-sdk/wire"
 )
@@ -60,3 +62,23 @@ func (fck FeeCollectionKeeper) addCollectedFees(ctx sdk.Context, coins sdk.Coins
 func (fck FeeCollectionKeeper) ClearCollectedFees(ctx sdk.Context) {
 	fck.setCollectedFees(ctx, sdk.Coins{})
 }
+
+//__________________________________________________________
+
+// InitCollectedFeesGenesis - set the collected fees of the genesis file
+func InitCollectedFeesGenesis(ctx sdk.Context, fck FeeCollectionKeeper, fees sdk.Coins) error {
+	if !fees.IsValid() || !fees.IsNotNegative() {
+		return fmt.Errorf("invalid collected fees %v in genesis state", fees)
+	}
+	fck.setCollectedFees(ctx, fees)
+	return nil
+}
+
+// WriteCollectedFeesGenesis - output the collected fees for a genesis file
+func WriteCollectedFeesGenesis(ctx sdk.Context, fck FeeCollectionKeeper) sdk.Coins {
+	fees := fck.GetCollectedFees(ctx)
+	if fees == nil {
+		fees = sdk.Coins{}
+	}
+	return fees
+}
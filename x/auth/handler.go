@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"fmt"
 	"reflect"
 
 	sdk "github.com/tepleton/tepleton-sdk/types"
@@ -32,3 +33,49 @@ func handleMsgChangeKey(ctx sdk.Context, am AccountMapper, msg MsgChangeKey) sdk
 		Tags: sdk.NewTags("action", []byte("changePubkey"), "address", msg.Address.Bytes(), "pubkey", msg.NewPubKey.Bytes()),
 	}
 }
+
+// NewFeeGrantHandler returns a handler for "feegrant" type messages.
+func NewFeeGrantHandler(fgk FeeGrantKeeper, am AccountMapper) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		switch msg := msg.(type) {
+		case MsgGrantFeeAllowance:
+			return handleMsgGrantFeeAllowance(ctx, fgk, am, msg)
+		case MsgRevokeFeeAllowance:
+			return handleMsgRevokeFeeAllowance(ctx, fgk, msg)
+		default:
+			errMsg := "Unrecognized feegrant Msg type: " + reflect.TypeOf(msg).Name()
+			return sdk.ErrUnknownRequest(errMsg).Result()
+		}
+	}
+}
+
+// Handle MsgGrantFeeAllowance
+// The grantee account is created if it doesn't exist, so it can sign its
+// first transactions without holding coins
+func handleMsgGrantFeeAllowance(ctx sdk.Context, fgk FeeGrantKeeper, am AccountMapper, msg MsgGrantFeeAllowance) sdk.Result {
+	if msg.Expiration != 0 && msg.Expiration <= ctx.BlockHeader().Time {
+		return sdk.ErrUnknownRequest("fee grant expires in the past").Result()
+	}
+
+	if am.GetAccount(ctx, msg.Grantee) == nil {
+		am.SetAccount(ctx, am.NewAccountWithAddress(ctx, msg.Grantee))
+	}
+	fgk.SetFeeGrant(ctx, NewFeeGrant(msg.Granter, msg.Grantee, msg.SpendLimit, msg.Expiration))
+
+	return sdk.Result{
+		Tags: sdk.NewTags("action", []byte("grantFeeAllowance"), "granter", msg.Granter.Bytes(), "grantee", msg.Grantee.Bytes()),
+	}
+}
+
+// Handle MsgRevokeFeeAllowance
+func handleMsgRevokeFeeAllowance(ctx sdk.Context, fgk FeeGrantKeeper, msg MsgRevokeFeeAllowance) sdk.Result {
+	_, found := fgk.GetFeeGrant(ctx, msg.Granter, msg.Grantee)
+	if !found {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("no fee grant from %v to %v", msg.Granter, msg.Grantee)).Result()
+	}
+	fgk.RevokeFeeGrant(ctx, msg.Granter, msg.Grantee)
+
+	return sdk.Result{
+		Tags: sdk.NewTags("action", []byte("revokeFeeAllowance"), "granter", msg.Granter.Bytes(), "grantee", msg.Grantee.Bytes()),
+	}
+}
@@ -39,3 +39,100 @@ func (msg MsgChangeKey) GetSignBytes() []byte {
 func (msg MsgChangeKey) GetSigners() []sdk.Address {
 	return []sdk.Address{msg.Address}
 }
+
+//__________________________________________________________
+
+// MsgGrantFeeAllowance - allow the grantee to pay fees with the coins of the
+// granter, up to the spend limit and until the expiration
+type MsgGrantFeeAllowance struct {
+	Granter    sdk.Address `json:"granter"`
+	Grantee    sdk.Address `json:"grantee"`
+	SpendLimit sdk.Coins   `json:"spend_limit"`
+	Expiration int64       `json:"expiration"`
+}
+
+var _ sdk.Msg = MsgGrantFeeAllowance{}
+
+// NewMsgGrantFeeAllowance - msg to grant fees to the grantee
+func NewMsgGrantFeeAllowance(granter, grantee sdk.Address, spendLimit sdk.Coins, expiration int64) MsgGrantFeeAllowance {
+	return MsgGrantFeeAllowance{
+		Granter:    granter,
+		Grantee:    grantee,
+		SpendLimit: spendLimit,
+		Expiration: expiration,
+	}
+}
+
+// Implements Msg.
+func (msg MsgGrantFeeAllowance) Type() string { return "feegrant" }
+
+// Implements Msg.
+func (msg MsgGrantFeeAllowance) ValidateBasic() sdk.Error {
+	if len(msg.Granter) == 0 || len(msg.Grantee) == 0 {
+		return sdk.ErrInvalidAddress("granter and grantee are required")
+	}
+	if msg.Granter.String() == msg.Grantee.String() {
+		return sdk.ErrInvalidAddress("granter can't grant fees to itself")
+	}
+	if !msg.SpendLimit.IsValid() || !msg.SpendLimit.IsPositive() {
+		return sdk.ErrInvalidCoins(msg.SpendLimit.String())
+	}
+	if msg.Expiration < 0 {
+		return sdk.ErrUnknownRequest("expiration can't be negative")
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgGrantFeeAllowance) GetSignBytes() []byte {
+	b, err := msgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgGrantFeeAllowance) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Granter}
+}
+
+//__________________________________________________________
+
+// MsgRevokeFeeAllowance - remove the fee grant from the granter to the grantee
+type MsgRevokeFeeAllowance struct {
+	Granter sdk.Address `json:"granter"`
+	Grantee sdk.Address `json:"grantee"`
+}
+
+var _ sdk.Msg = MsgRevokeFeeAllowance{}
+
+// NewMsgRevokeFeeAllowance - msg to revoke the fee grant to the grantee
+func NewMsgRevokeFeeAllowance(granter, grantee sdk.Address) MsgRevokeFeeAllowance {
+	return MsgRevokeFeeAllowance{Granter: granter, Grantee: grantee}
+}
+
+// Implements Msg.
+func (msg MsgRevokeFeeAllowance) Type() string { return "feegrant" }
+
+// Implements Msg.
+func (msg MsgRevokeFeeAllowance) ValidateBasic() sdk.Error {
+	if len(msg.Granter) == 0 || len(msg.Grantee) == 0 {
+		return sdk.ErrInvalidAddress("granter and grantee are required")
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgRevokeFeeAllowance) GetSignBytes() []byte {
+	b, err := msgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgRevokeFeeAllowance) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Granter}
+}
@@ -1,7 +1,9 @@
 package auth
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 
 	sdk "github.com/tepleton/tepleton-sdk/types"
 	crypto "github.com/tepleton/go-crypto"
@@ -10,11 +12,16 @@ import (
 var _ sdk.Tx = (*StdTx)(nil)
 
 // StdTx is a standard way to wrap a Msg with Fee and Signatures.
-// NOTE: the first signature is the FeePayer (Signatures must not be nil).
+// NOTE: the first signature is the FeePayer (Signatures must not be nil),
+// unless the fee is paid by a FeeGranter through its fee grant to it.
 type StdTx struct {
 	Msg        sdk.Msg        `json:"msg"`
 	Fee        StdFee         `json:"fee"`
 	Signatures []StdSignature `json:"signatures"`
+	FeeGranter sdk.Address    `json:"fee_granter,omitempty"`
+
+	// height after which the tx is refused, 0 if it doesn't time out
+	TimeoutHeight int64 `json:"timeout_height,omitempty"`
 }
 
 func NewStdTx(msg sdk.Msg, fee StdFee, sigs []StdSignature) StdTx {
@@ -25,6 +32,20 @@ func NewStdTx(msg sdk.Msg, fee StdFee, sigs []StdSignature) StdTx {
 	}
 }
 
+// WithFeeGranter returns a copy of the tx whose fee is paid by granter.
+// The signatures must cover the granter, see StdSignBytesWithFeeGranter.
+func (tx StdTx) WithFeeGranter(granter sdk.Address) StdTx {
+	tx.FeeGranter = granter
+	return tx
+}
+
+// WithTimeoutHeight returns a copy of the tx refused after height.
+// The signatures must cover the timeout height, see StdSignMsg.
+func (tx StdTx) WithTimeoutHeight(height int64) StdTx {
+	tx.TimeoutHeight = height
+	return tx
+}
+
 //nolint
 func (tx StdTx) GetMsg() sdk.Msg { return tx.Msg }
 
@@ -91,22 +112,60 @@ type StdSignDoc struct {
 	FeeBytes       []byte  `json:"fee_bytes"`
 	MsgBytes       []byte  `json:"msg_bytes"`
 	AltBytes       []byte  `json:"alt_bytes"`
+	FeeGranter     []byte  `json:"fee_granter,omitempty"`
+	TimeoutHeight  int64   `json:"timeout_height,omitempty"`
 }
 
 // StdSignBytes returns the bytes to sign for a transaction.
 // TODO: change the API to just take a chainID and StdTx ?
 func StdSignBytes(chainID string, accnums []int64, sequences []int64, fee StdFee, msg sdk.Msg) []byte {
-	bz, err := json.Marshal(StdSignDoc{
+	return StdSignBytesWithFeeGranter(chainID, accnums, sequences, fee, nil, msg)
+}
+
+// StdSignBytesWithFeeGranter returns the bytes to sign for a transaction
+// whose fee is paid by feeGranter, or by the first signer if it's empty.
+func StdSignBytesWithFeeGranter(chainID string, accnums []int64, sequences []int64, fee StdFee, feeGranter sdk.Address, msg sdk.Msg) []byte {
+	return StdSignMsg{
 		ChainID:        chainID,
 		AccountNumbers: accnums,
 		Sequences:      sequences,
-		FeeBytes:       fee.Bytes(),
-		MsgBytes:       msg.GetSignBytes(),
-	})
+		Fee:            fee,
+		FeeGranter:     feeGranter,
+		Msg:            msg,
+	}.Bytes()
+}
+
+// ParseStdSignBytes decodes bytes produced by StdSignBytes, refusing
+// anything that is not the canonical sign document for chainID.
+func ParseStdSignBytes(chainID string, bz []byte) (doc StdSignDoc, fee StdFee, err error) {
+	err = json.Unmarshal(bz, &doc)
 	if err != nil {
-		panic(err)
+		return doc, fee, err
 	}
-	return bz
+	if doc.ChainID != chainID {
+		return doc, fee, fmt.Errorf("Sign document is for chain %q, expected %q", doc.ChainID, chainID)
+	}
+	if len(doc.AccountNumbers) == 0 || len(doc.AccountNumbers) != len(doc.Sequences) {
+		return doc, fee, fmt.Errorf("Sign document needs matching account numbers and sequences")
+	}
+	if !json.Valid(doc.MsgBytes) {
+		return doc, fee, fmt.Errorf("Sign document msg is not valid JSON")
+	}
+	err = msgCdc.UnmarshalJSON(doc.FeeBytes, &fee)
+	if err != nil {
+		return doc, fee, err
+	}
+	if !bytes.Equal(fee.Bytes(), doc.FeeBytes) {
+		return doc, fee, fmt.Errorf("Sign document fee is not canonically encoded")
+	}
+	canonical, err := json.Marshal(doc)
+	if err != nil {
+		return doc, fee, err
+	}
+	if !bytes.Equal(canonical, bz) {
+		return doc, fee, fmt.Errorf("Sign document is not canonically encoded")
+	}
+	return doc, fee, nil
 }
 
 // StdSignMsg is a convenience structure for passing along
@@ -117,19 +176,81 @@ type StdSignMsg struct {
 	AccountNumbers []int64
 	Sequences      []int64
 	Fee            StdFee
+	FeeGranter     sdk.Address
+	TimeoutHeight  int64
 	Msg            sdk.Msg
 	// XXX: Alt
 }
 
-// get message bytes
+// get the sign message of a tx, for the given account numbers and sequences
+func NewStdSignMsg(chainID string, accnums []int64, sequences []int64, tx StdTx) StdSignMsg {
+	return StdSignMsg{
+		ChainID:        chainID,
+		AccountNumbers: accnums,
+		Sequences:      sequences,
+		Fee:            tx.Fee,
+		FeeGranter:     tx.FeeGranter,
+		TimeoutHeight:  tx.TimeoutHeight,
+		Msg:            tx.Msg,
+	}
+}
+
+// get message bytes, signed in SignModeJSON
 func (msg StdSignMsg) Bytes() []byte {
-	return StdSignBytes(msg.ChainID, msg.AccountNumbers, msg.Sequences, msg.Fee, msg.Msg)
+	bz, err := json.Marshal(StdSignDoc{
+		ChainID:        msg.ChainID,
+		AccountNumbers: msg.AccountNumbers,
+		Sequences:      msg.Sequences,
+		FeeBytes:       msg.Fee.Bytes(),
+		MsgBytes:       msg.Msg.GetSignBytes(),
+		FeeGranter:     msg.FeeGranter,
+		TimeoutHeight:  msg.TimeoutHeight,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return bz
+}
+
+// get the bytes to sign in mode
+func (msg StdSignMsg) BytesForMode(mode SignMode) ([]byte, error) {
+	switch mode {
+	case SignModeJSON:
+		return msg.Bytes(), nil
+	case SignModeTextual:
+		return msg.TextBytes(), nil
+	default:
+		return nil, fmt.Errorf("Unknown sign mode %d", mode)
+	}
+}
+
+// SignMode is the encoding of the sign message a signature is made over
+type SignMode byte
+
+// nolint
+const (
+	SignModeJSON    SignMode = 0x00 // JSON of the StdSignDoc
+	SignModeTextual SignMode = 0x01 // human-readable lines, see StdSignMsg.Text
+)
+
+// ParseSignMode parses the name of a sign mode, "json" or "textual".
+// The empty name is SignModeJSON.
+func ParseSignMode(name string) (SignMode, error) {
+	switch name {
+	case "", "json":
+		return SignModeJSON, nil
+	case "textual":
+		return SignModeTextual, nil
+	default:
+		return SignModeJSON, fmt.Errorf("Unknown sign mode %q, expected json or textual", name)
+	}
 }
 
 // Standard Signature
 type StdSignature struct {
 	crypto.PubKey    `json:"pub_key"` // optional
 	crypto.Signature `json:"signature"`
-	AccountNumber    int64 `json:"account_number"`
-	Sequence         int64 `json:"sequence"`
+	AccountNumber    int64    `json:"account_number"`
+	Sequence         int64    `json:"sequence"`
+	SignMode         SignMode `json:"sign_mode,omitempty"`
 }
@@ -30,3 +30,29 @@ func TestStdTx(t *testing.T) {
 	feePayer := FeePayer(tx)
 	assert.Equal(t, addr, feePayer)
 }
+
+func TestParseStdSignBytes(t *testing.T) {
+	priv := crypto.GenPrivKeyEd25519()
+	msg := sdk.NewTestMsg(priv.PubKey().Address())
+	fee := newStdFee()
+	bz := StdSignBytes("mychain", []int64{1}, []int64{2}, fee, msg)
+
+	doc, parsedFee, err := ParseStdSignBytes("mychain", bz)
+	assert.Nil(t, err)
+	assert.Equal(t, "mychain", doc.ChainID)
+	assert.Equal(t, []int64{1}, doc.AccountNumbers)
+	assert.Equal(t, []int64{2}, doc.Sequences)
+	assert.Equal(t, fee.Bytes(), parsedFee.Bytes())
+
+	// wrong chain
+	_, _, err = ParseStdSignBytes("otherchain", bz)
+	assert.NotNil(t, err)
+
+	// arbitrary bytes
+	_, _, err = ParseStdSignBytes("mychain", []byte("not a sign doc"))
+	assert.NotNil(t, err)
+
+	// valid JSON, but not the canonical encoding
+	_, _, err = ParseStdSignBytes("mychain", append([]byte(" "), bz...))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	sdk "github.com/tepleton/tepleton-sdk/types"
+)
+
+// TextualMsg is a Msg with its own rendering for SignModeTextual, as
+// labelled lines. Other Msgs are rendered from their sign bytes.
+type TextualMsg interface {
+	sdk.Msg
+	GetSignText() []string
+}
+
+// Text renders the sign message as deterministic labelled lines, which
+// signers using SignModeTextual can read before signing them.
+func (msg StdSignMsg) Text() []string {
+	lines := []string{"Chain id: " + TextValue(msg.ChainID)}
+	for i, accnum := range msg.AccountNumbers {
+		lines = append(lines, fmt.Sprintf("Signer %d account number: %d", i+1, accnum))
+		if i < len(msg.Sequences) {
+			lines = append(lines, fmt.Sprintf("Signer %d sequence: %d", i+1, msg.Sequences[i]))
+		}
+	}
+	lines = append(lines,
+		"Fee: "+msg.Fee.Amount.String(),
+		fmt.Sprintf("Gas: %d", msg.Fee.Gas),
+	)
+	if len(msg.FeeGranter) != 0 {
+		lines = append(lines, "Fee granter: "+sdk.MustBech32ifyAcc(msg.FeeGranter))
+	}
+	if msg.TimeoutHeight != 0 {
+		lines = append(lines, fmt.Sprintf("Timeout height: %d", msg.TimeoutHeight))
+	}
+	lines = append(lines,
+		"Msg type: "+msg.Msg.Type(),
+		"Msg name: "+MsgTypeName(msg.Msg),
+	)
+	return append(lines, MsgText(msg.Msg)...)
+}
+
+// MsgTypeName returns the name of the concrete Go type of the msg, which
+// tells apart the msgs sharing a Type
+func MsgTypeName(msg sdk.Msg) string {
+	t := reflect.TypeOf(msg)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// TextBytes returns the bytes signed in SignModeTextual, the lines of Text
+func (msg StdSignMsg) TextBytes() []byte {
+	return []byte(strings.Join(msg.Text(), "\n"))
+}
+
+// MsgText renders a Msg as labelled lines, with its GetSignText if it's a
+// TextualMsg, else by flattening the JSON of its sign bytes, in key order
+func MsgText(msg sdk.Msg) []string {
+	if textual, ok := msg.(TextualMsg); ok {
+		return textual.GetSignText()
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(msg.GetSignBytes()))
+	dec.UseNumber()
+	var value interface{}
+	err := dec.Decode(&value)
+	if err != nil {
+		panic(err)
+	}
+	return appendText(nil, "Msg", value)
+}
+
+func appendText(lines []string, label string, value interface{}) []string {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		if len(keys) == 0 {
+			return append(lines, label+": {}")
+		}
+		for _, key := range keys {
+			lines = appendText(lines, label+" "+key, value[key])
+		}
+		return lines
+	case []interface{}:
+		if len(value) == 0 {
+			return append(lines, label+": []")
+		}
+		for i, elem := range value {
+			lines = appendText(lines, fmt.Sprintf("%s %d", label, i+1), elem)
+		}
+		return lines
+	case string:
+		return append(lines, label+": "+TextValue(value))
+	case nil:
+		return append(lines, label+": null")
+	default:
+		return append(lines, fmt.Sprintf("%s: %v", label, value))
+	}
+}
+
+// TextValue renders a string for a line of text, quoted if it has
+// characters which could be mistaken for another line, or a quote
+func TextValue(s string) string {
+	if strings.HasPrefix(s, `"`) {
+		return strconv.QuoteToASCII(s)
+	}
+	for _, r := range s {
+		if !unicode.IsPrint(r) {
+			return strconv.QuoteToASCII(s)
+		}
+	}
+	return s
+}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	sdk "github.com/tepleton/tepleton-sdk/types"
+)
+
+func TestTextValue(t *testing.T) {
+	cases := []struct {
+		in, out string
+	}{
+		{"", ""},
+		{"mychainid", "mychainid"},
+		{"with space", "with space"},
+		{`"quoted"`, `"\"quoted\""`},
+		{"two\nlines", `"two\nlines"`},
+		{"tab\there", `"tab\there"`},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.out, TextValue(tc.in), tc.in)
+	}
+}
+
+func TestStdSignMsgText(t *testing.T) {
+	_, addr := privAndAddr()
+	msg := StdSignMsg{
+		ChainID:        "mychainid",
+		AccountNumbers: []int64{3},
+		Sequences:      []int64{4},
+		Fee:            newStdFee(),
+		Msg:            sdk.NewTestMsg(addr),
+		TimeoutHeight:  20,
+	}
+	lines := msg.Text()
+	assert.Equal(t, []string{
+		"Chain id: mychainid",
+		"Signer 1 account number: 3",
+		"Signer 1 sequence: 4",
+		"Fee: 150atom",
+		"Gas: 100",
+		"Timeout height: 20",
+		"Msg type: TestMsg",
+		"Msg name: TestMsg",
+	}, lines[:8])
+	assert.NotEmpty(t, lines[8:])
+
+	// the text changes with anything signed over
+	msg2 := msg
+	msg2.TimeoutHeight = 21
+	assert.NotEqual(t, msg.TextBytes(), msg2.TextBytes())
+
+	// msgs of the same type and sign bytes are told apart by their name
+	msg3 := msg
+	msg3.Msg = otherTestMsg{sdk.NewTestMsg(addr)}
+	assert.Equal(t, "Msg name: otherTestMsg", msg3.Text()[7])
+	assert.NotEqual(t, msg.TextBytes(), msg3.TextBytes())
+}
+
+// a msg with the type and sign bytes of sdk.TestMsg
+type otherTestMsg struct {
+	*sdk.TestMsg
+}
@@ -10,6 +10,8 @@ func RegisterWire(cdc *wire.Codec) {
 	cdc.RegisterConcrete(&BaseAccount{}, "auth/Account", nil)
 	cdc.RegisterConcrete(MsgChangeKey{}, "auth/ChangeKey", nil)
 	cdc.RegisterConcrete(StdTx{}, "auth/StdTx", nil)
+	cdc.RegisterConcrete(MsgGrantFeeAllowance{}, "auth/GrantFeeAllowance", nil)
+	cdc.RegisterConcrete(MsgRevokeFeeAllowance{}, "auth/RevokeFeeAllowance", nil)
 }
 
 var msgCdc = wire.NewCodec()
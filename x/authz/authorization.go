@@ -0,0 +1,91 @@
+package authz
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+
+	sdk "github.com/tepleton/tepleton-sdk/types"
+	"github.com/tepleton/tepleton-sdk/x/bank"
+)
+
+// MsgName returns the name authorizations refer to a msg by: its type and
+// the name of its Go type, like "bank/MsgSend"
+func MsgName(msg sdk.Msg) string {
+	return msg.Type() + "/" + reflect.TypeOf(msg).Name()
+}
+
+// Authorization allows a grantee to execute msgs of a type on behalf of the
+// granter
+type Authorization interface {
+	// name of the msgs allowed, see MsgName
+	MsgName() string
+
+	// Accept checks the msg is allowed on behalf of granter, and returns the
+	// authorization left after executing it, nil if it's exhausted. Errors
+	// are in codespace.
+	Accept(codespace sdk.CodespaceType, granter sdk.Address, msg sdk.Msg) (updated Authorization, err sdk.Error)
+}
+
+//______________________________________________________________________
+
+// GenericAuthorization allows any msg of a type, without limit
+type GenericAuthorization struct {
+	Msg string `json:"msg"`
+}
+
+var _ Authorization = GenericAuthorization{}
+
+// NewGenericAuthorization returns a new GenericAuthorization
+func NewGenericAuthorization(msgName string) GenericAuthorization {
+	return GenericAuthorization{Msg: msgName}
+}
+
+// Implements Authorization
+func (a GenericAuthorization) MsgName() string { return a.Msg }
+
+// Implements Authorization
+func (a GenericAuthorization) Accept(codespace sdk.CodespaceType, granter sdk.Address, msg sdk.Msg) (Authorization, sdk.Error) {
+	return a, nil
+}
+
+//______________________________________________________________________
+
+// SendAuthorization allows bank sends, up to a spend limit
+type SendAuthorization struct {
+	SpendLimit sdk.Coins `json:"spend_limit"`
+}
+
+var _ Authorization = SendAuthorization{}
+
+// NewSendAuthorization returns a new SendAuthorization
+func NewSendAuthorization(spendLimit sdk.Coins) SendAuthorization {
+	return SendAuthorization{SpendLimit: spendLimit}
+}
+
+// Implements Authorization
+func (a SendAuthorization) MsgName() string { return MsgName(bank.MsgSend{}) }
+
+// Implements Authorization. Only the coins sent from the granter count
+// against the spend limit, the other inputs are signed by their owners.
+func (a SendAuthorization) Accept(codespace sdk.CodespaceType, granter sdk.Address, msg sdk.Msg) (Authorization, sdk.Error) {
+	send, ok := msg.(bank.MsgSend)
+	if !ok {
+		return nil, ErrUnauthorized(codespace, "send authorization only allows bank sends")
+	}
+	var total sdk.Coins
+	for _, in := range send.Inputs {
+		if bytes.Equal(in.Address, granter) {
+			total = total.Plus(in.Coins)
+		}
+	}
+	if !a.SpendLimit.IsGTE(total) {
+		return nil, ErrUnauthorized(codespace,
+			fmt.Sprintf("send of %v exceeds the spend limit %v", total, a.SpendLimit))
+	}
+	left := a.SpendLimit.Minus(total)
+	if left.IsZero() {
+		return nil, nil
+	}
+	return NewSendAuthorization(left), nil
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tepleton/tepleton-sdk/client/context"
+	"github.com/tepleton/tepleton-sdk/client/keys"
+	"github.com/tepleton/tepleton-sdk/wire"
+	"github.com/tepleton/tepleton-sdk/x/authz"
+)
+
+// GetCmdQueryGrants returns the command to query the grants from a granter to a grantee
+func GetCmdQueryGrants(storeName string, cdc *wire.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "grants [granter] [grantee]",
+		Short: "Query the authorizations granted to an address",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			granter, err := keys.GetAccAddress(args[0])
+			if err != nil {
+				return err
+			}
+			grantee, err := keys.GetAccAddress(args[1])
+			if err != nil {
+				return err
+			}
+
+			ctx := context.NewCoreContextFromViper()
+			resKVs, err := ctx.QuerySubspace(cdc, authz.GetGrantsKey(granter, grantee), storeName)
+			if err != nil {
+				return err
+			}
+			grants := make([]authz.Grant, len(resKVs))
+			for i, kv := range resKVs {
+				cdc.MustUnmarshalBinary(kv.Value, &grants[i])
+			}
+
+			output, err := wire.MarshalJSONIndent(cdc, grants)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(output))
+			return nil
+		},
+	}
+}
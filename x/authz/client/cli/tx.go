@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tepleton/tepleton-sdk/client/context"
+	"github.com/tepleton/tepleton-sdk/client/keys"
+	sdk "github.com/tepleton/tepleton-sdk/types"
+	"github.com/tepleton/tepleton-sdk/wire"
+	authcmd "github.com/tepleton/tepleton-sdk/x/auth/client/cli"
+	"github.com/tepleton/tepleton-sdk/x/authz"
+)
+
+const (
+	flagGrantee    = "grantee"
+	flagMsgName    = "msg-name"
+	flagSpendLimit = "spend-limit"
+	flagExpiration = "expiration"
+)
+
+// GetCmdGrant returns the command to grant an authorization to an address
+func GetCmdGrant(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "grant",
+		Short: "Allow an address to execute msgs on your behalf",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+
+			granter, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			grantee, err := keys.GetAccAddress(viper.GetString(flagGrantee))
+			if err != nil {
+				return err
+			}
+
+			// a spend limit grants bank sends, else any msg of the type
+			var authorization authz.Authorization
+			if limit := viper.GetString(flagSpendLimit); limit != "" {
+				spendLimit, err := sdk.ParseCoins(limit)
+				if err != nil {
+					return err
+				}
+				authorization = authz.NewSendAuthorization(spendLimit)
+			} else {
+				msgName := viper.GetString(flagMsgName)
+				if msgName == "" {
+					return errors.Errorf("--%s or --%s is required", flagMsgName, flagSpendLimit)
+				}
+				authorization = authz.NewGenericAuthorization(msgName)
+			}
+
+			msg := authz.NewMsgGrant(granter, grantee, authorization, viper.GetInt64(flagExpiration))
+			res, err := ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, msg, cdc)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Committed at block %d. Hash: %s\n", res.Height, res.Hash.String())
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagGrantee, "", "Address allowed to execute the msgs")
+	cmd.Flags().String(flagMsgName, "", "Msg type allowed without limit, like stake/MsgDelegate")
+	cmd.Flags().String(flagSpendLimit, "", "Amount of coins the grantee may send")
+	cmd.Flags().Int64(flagExpiration, 0, "Block time the grant expires at, 0 if it doesn't")
+	return cmd
+}
+
+// GetCmdRevoke returns the command to revoke an authorization
+func GetCmdRevoke(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "revoke",
+		Short: "Revoke the authorization of a msg type to an address",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+
+			granter, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			grantee, err := keys.GetAccAddress(viper.GetString(flagGrantee))
+			if err != nil {
+				return err
+			}
+
+			msg := authz.NewMsgRevoke(granter, grantee, viper.GetString(flagMsgName))
+			res, err := ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, msg, cdc)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Committed at block %d. Hash: %s\n", res.Height, res.Hash.String())
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagGrantee, "", "Address whose authorization is revoked")
+	cmd.Flags().String(flagMsgName, "", "Msg type of the authorization, like bank/MsgSend")
+	return cmd
+}
+
+// GetCmdExec returns the command to execute msgs granted to the signer,
+// read as JSON from a file
+func GetCmdExec(cdc *wire.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "exec [msgs-file]",
+		Short: "Execute msgs on behalf of the accounts which granted them to you",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+
+			grantee, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			bz, err := ioutil.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			var msgs []sdk.Msg
+			err = cdc.UnmarshalJSON(bz, &msgs)
+			if err != nil {
+				return err
+			}
+
+			msg := authz.NewMsgExec(grantee, msgs)
+			res, err := ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, msg, cdc)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Committed at block %d. Hash: %s\n", res.Height, res.Hash.String())
+			return nil
+		},
+	}
+}
@@ -0,0 +1,36 @@
+// nolint
+package authz
+
+import (
+	"fmt"
+
+	sdk "github.com/tepleton/tepleton-sdk/types"
+)
+
+const (
+	DefaultCodespace sdk.CodespaceType = 7
+
+	CodeInvalidGrant       sdk.CodeType = 1
+	CodeUnknownGrant       sdk.CodeType = 2
+	CodeUnauthorized       sdk.CodeType = 3
+	CodeUnrecognizedAction sdk.CodeType = 4
+)
+
+//----------------------------------------
+// Error constructors
+
+func ErrInvalidGrant(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidGrant, msg)
+}
+
+func ErrUnknownGrant(codespace sdk.CodespaceType, granter, grantee sdk.Address, msgName string) sdk.Error {
+	return sdk.NewError(codespace, CodeUnknownGrant, fmt.Sprintf("%v didn't grant %s to %v", granter, msgName, grantee))
+}
+
+func ErrUnauthorized(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeUnauthorized, msg)
+}
+
+func ErrUnrecognizedAction(codespace sdk.CodespaceType, msgType string) sdk.Error {
+	return sdk.NewError(codespace, CodeUnrecognizedAction, fmt.Sprintf("no handler for msg type %s", msgType))
+}
@@ -0,0 +1,57 @@
+package authz
+
+import (
+	"fmt"
+
+	sdk "github.com/tepleton/tepleton-sdk/types"
+)
+
+// GenesisState - the grants of the granters to their grantees
+type GenesisState struct {
+	Grants []GenesisGrant `json:"grants"`
+}
+
+// GenesisGrant - a grant from the granter to the grantee
+type GenesisGrant struct {
+	Granter sdk.Address `json:"granter"`
+	Grantee sdk.Address `json:"grantee"`
+	Grant   Grant       `json:"grant"`
+}
+
+// DefaultGenesisState - no grants
+func DefaultGenesisState() GenesisState {
+	return GenesisState{
+		Grants: []GenesisGrant{},
+	}
+}
+
+// InitGenesis - set the grants from the genesis
+func InitGenesis(ctx sdk.Context, k Keeper, data GenesisState) error {
+	for _, grant := range data.Grants {
+		if len(grant.Granter) == 0 || len(grant.Grantee) == 0 {
+			return fmt.Errorf("grant without granter or grantee in genesis state")
+		}
+		if grant.Granter.String() == grant.Grantee.String() {
+			return fmt.Errorf("grant of %v to itself in genesis state", grant.Granter)
+		}
+		if grant.Grant.Authorization == nil || len(grant.Grant.Authorization.MsgName()) == 0 {
+			return fmt.Errorf("grant from %v to %v without authorization in genesis state", grant.Granter, grant.Grantee)
+		}
+	}
+	for _, grant := range data.Grants {
+		k.SetGrant(ctx, grant.Granter, grant.Grantee, grant.Grant)
+	}
+	return nil
+}
+
+// WriteGenesis - output the grants
+func WriteGenesis(ctx sdk.Context, k Keeper) GenesisState {
+	grants := []GenesisGrant{}
+	k.IterateGrants(ctx, func(granter, grantee sdk.Address, grant Grant) (stop bool) {
+		grants = append(grants, GenesisGrant{granter, grantee, grant})
+		return false
+	})
+	return GenesisState{
+		Grants: grants,
+	}
+}
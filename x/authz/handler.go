@@ -0,0 +1,53 @@
+package authz
+
+import (
+	"reflect"
+
+	sdk "github.com/tepleton/tepleton-sdk/types"
+)
+
+// NewHandler returns a handler for "authz" type messages.
+func NewHandler(k Keeper) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		switch msg := msg.(type) {
+		case MsgGrant:
+			return handleMsgGrant(ctx, k, msg)
+		case MsgRevoke:
+			return handleMsgRevoke(ctx, k, msg)
+		case MsgExec:
+			return handleMsgExec(ctx, k, msg)
+		default:
+			errMsg := "Unrecognized authz Msg type: " + reflect.TypeOf(msg).Name()
+			return sdk.ErrUnknownRequest(errMsg).Result()
+		}
+	}
+}
+
+func handleMsgGrant(ctx sdk.Context, k Keeper, msg MsgGrant) sdk.Result {
+	if msg.Expiration != 0 && msg.Expiration <= ctx.BlockHeader().Time {
+		return ErrInvalidGrant(k.codespace, "grant expires in the past").Result()
+	}
+	k.SetGrant(ctx, msg.Granter, msg.Grantee, NewGrant(msg.Authorization, msg.Expiration))
+
+	return sdk.Result{
+		Tags: sdk.NewTags("action", []byte("grant"), "granter", msg.Granter.Bytes(), "grantee", msg.Grantee.Bytes()),
+	}
+}
+
+func handleMsgRevoke(ctx sdk.Context, k Keeper, msg MsgRevoke) sdk.Result {
+	_, found := k.GetGrant(ctx, msg.Granter, msg.Grantee, msg.MsgName)
+	if !found {
+		return ErrUnknownGrant(k.codespace, msg.Granter, msg.Grantee, msg.MsgName).Result()
+	}
+	k.DeleteGrant(ctx, msg.Granter, msg.Grantee, msg.MsgName)
+
+	return sdk.Result{
+		Tags: sdk.NewTags("action", []byte("revoke"), "granter", msg.Granter.Bytes(), "grantee", msg.Grantee.Bytes()),
+	}
+}
+
+// The signature of the grantee was checked by the AnteHandler, the msgs are
+// checked against the grants of their signers instead of their signatures.
+func handleMsgExec(ctx sdk.Context, k Keeper, msg MsgExec) sdk.Result {
+	return k.DispatchActions(ctx, msg.Grantee, msg.Msgs)
+}
@@ -0,0 +1,163 @@
+package authz
+
+import (
+	sdk "github.com/tepleton/tepleton-sdk/types"
+	wire "github.com/tepleton/tepleton-sdk/wire"
+)
+
+// Router returns the handlers of msgs, by type, like the router of BaseApp
+type Router interface {
+	Route(path string) (h sdk.Handler)
+}
+
+// Grant is an authorization of a granter to a grantee, until the
+// expiration, a block time, or without expiration if it's 0
+type Grant struct {
+	Authorization Authorization `json:"authorization"`
+	Expiration    int64         `json:"expiration"`
+}
+
+// NewGrant returns a new Grant
+func NewGrant(authorization Authorization, expiration int64) Grant {
+	return Grant{Authorization: authorization, Expiration: expiration}
+}
+
+// IsExpired returns whether the grant has expired at the block time
+func (g Grant) IsExpired(blockTime int64) bool {
+	return g.Expiration != 0 && blockTime >= g.Expiration
+}
+
+// Authorization Keeper
+type Keeper struct {
+	// The router dispatching the msgs executed on behalf of granters
+	router Router
+
+	// The (unexposed) key used to access the store from the Context.
+	storeKey sdk.StoreKey
+
+	// The wire codec for binary encoding/decoding.
+	cdc *wire.Codec
+
+	// Reserved codespace
+	codespace sdk.CodespaceType
+}
+
+// NewKeeper returns a new Keeper, executing the msgs with the handlers of router
+func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, router Router, codespace sdk.CodespaceType) Keeper {
+	return Keeper{
+		router:    router,
+		storeKey:  key,
+		cdc:       cdc,
+		codespace: codespace,
+	}
+}
+
+// GetGrant returns the grant of msgName from granter to grantee
+func (k Keeper) GetGrant(ctx sdk.Context, granter, grantee sdk.Address, msgName string) (grant Grant, found bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(GetGrantKey(granter, grantee, msgName))
+	if bz == nil {
+		return grant, false
+	}
+	k.cdc.MustUnmarshalBinary(bz, &grant)
+	return grant, true
+}
+
+// GetGrants returns all the grants from granter to grantee
+func (k Keeper) GetGrants(ctx sdk.Context, granter, grantee sdk.Address) (grants []Grant) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, GetGrantsKey(granter, grantee))
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var grant Grant
+		k.cdc.MustUnmarshalBinary(iterator.Value(), &grant)
+		grants = append(grants, grant)
+	}
+	return grants
+}
+
+// IterateGrants calls handler with every grant, with its granter and
+// grantee, until it returns true
+func (k Keeper) IterateGrants(ctx sdk.Context, handler func(granter, grantee sdk.Address, grant Grant) (stop bool)) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, GrantKey)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		granter, grantee, _ := splitGrantKey(iterator.Key())
+		var grant Grant
+		k.cdc.MustUnmarshalBinary(iterator.Value(), &grant)
+		if handler(granter, grantee, grant) {
+			break
+		}
+	}
+}
+
+// SetGrant sets the grant from granter to grantee, replacing the previous
+// grant of the same msgs
+func (k Keeper) SetGrant(ctx sdk.Context, granter, grantee sdk.Address, grant Grant) {
+	store := ctx.KVStore(k.storeKey)
+	key := GetGrantKey(granter, grantee, grant.Authorization.MsgName())
+	store.Set(key, k.cdc.MustMarshalBinary(grant))
+}
+
+// DeleteGrant removes the grant of msgName from granter to grantee
+func (k Keeper) DeleteGrant(ctx sdk.Context, granter, grantee sdk.Address, msgName string) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(GetGrantKey(granter, grantee, msgName))
+}
+
+// authorize checks granter granted msg to grantee, and updates the grant
+// for its execution
+func (k Keeper) authorize(ctx sdk.Context, granter, grantee sdk.Address, msg sdk.Msg) sdk.Error {
+	msgName := MsgName(msg)
+	grant, found := k.GetGrant(ctx, granter, grantee, msgName)
+	if !found {
+		return ErrUnknownGrant(k.codespace, granter, grantee, msgName)
+	}
+	if grant.IsExpired(ctx.BlockHeader().Time) {
+		k.DeleteGrant(ctx, granter, grantee, msgName)
+		return ErrUnauthorized(k.codespace, "grant has expired")
+	}
+	updated, err := grant.Authorization.Accept(k.codespace, granter, msg)
+	if err != nil {
+		return err
+	}
+	if updated == nil {
+		k.DeleteGrant(ctx, granter, grantee, msgName)
+		return nil
+	}
+	grant.Authorization = updated
+	k.SetGrant(ctx, granter, grantee, grant)
+	return nil
+}
+
+// DispatchActions executes msgs on behalf of their signers, which must all
+// be the grantee or have granted the msgs to it. It stops at the first
+// failing msg and returns its result.
+func (k Keeper) DispatchActions(ctx sdk.Context, grantee sdk.Address, msgs []sdk.Msg) sdk.Result {
+	allTags := sdk.EmptyTags()
+	for _, msg := range msgs {
+		for _, signer := range msg.GetSigners() {
+			if signer.String() == grantee.String() {
+				continue
+			}
+			err := k.authorize(ctx, signer, grantee, msg)
+			if err != nil {
+				return err.Result()
+			}
+		}
+
+		handler := k.router.Route(msg.Type())
+		if handler == nil {
+			return ErrUnrecognizedAction(k.codespace, msg.Type()).Result()
+		}
+		res := handler(ctx, msg)
+		if !res.IsOK() {
+			return res
+		}
+		allTags = allTags.AppendTags(res.Tags)
+	}
+	return sdk.Result{
+		Tags: allTags,
+	}
+}
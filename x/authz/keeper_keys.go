@@ -0,0 +1,45 @@
+package authz
+
+import (
+	sdk "github.com/tepleton/tepleton-sdk/types"
+)
+
+// Key for getting the grants from the store
+var (
+	GrantKey = []byte{0x00} // prefix for each grant, by granter, grantee and msg name
+)
+
+// get the key for the grant of msgName from granter to grantee
+func GetGrantKey(granter, grantee sdk.Address, msgName string) []byte {
+	return append(GetGrantsKey(granter, grantee), []byte(msgName)...)
+}
+
+// get the prefix of the keys for all the grants from granter to grantee.
+// The addresses are length-prefixed so that the keys of different
+// granters and grantees can't collide.
+func GetGrantsKey(granter, grantee sdk.Address) []byte {
+	key := append(GrantKey, lengthPrefix(granter)...)
+	return append(key, lengthPrefix(grantee)...)
+}
+
+// prefix the address bytes with their length
+func lengthPrefix(addr sdk.Address) []byte {
+	if len(addr) > 255 {
+		panic("address longer than 255 bytes")
+	}
+	return append([]byte{byte(len(addr))}, addr.Bytes()...)
+}
+
+// split the key of a grant into its granter, grantee and msg name
+func splitGrantKey(key []byte) (granter, grantee sdk.Address, msgName string) {
+	key = key[len(GrantKey):]
+	granter, key = splitLengthPrefixed(key)
+	grantee, key = splitLengthPrefixed(key)
+	return granter, grantee, string(key)
+}
+
+// split the length-prefixed address at the start of the key from the rest
+func splitLengthPrefixed(key []byte) (addr sdk.Address, rest []byte) {
+	n := int(key[0])
+	return sdk.Address(key[1 : 1+n]), key[1+n:]
+}
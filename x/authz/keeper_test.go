@@ -0,0 +1,174 @@
+package authz
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tepleton/tmlibs/db"
+	"github.com/tepleton/tmlibs/log"
+	wrsp "github.com/tepleton/wrsp/types"
+
+	bam "github.com/tepleton/tepleton-sdk/baseapp"
+	"github.com/tepleton/tepleton-sdk/store"
+	sdk "github.com/tepleton/tepleton-sdk/types"
+	"github.com/tepleton/tepleton-sdk/wire"
+	"github.com/tepleton/tepleton-sdk/x/auth"
+	"github.com/tepleton/tepleton-sdk/x/bank"
+)
+
+var (
+	granter = sdk.Address([]byte("granter"))
+	grantee = sdk.Address([]byte("grantee"))
+	other   = sdk.Address([]byte("other"))
+)
+
+func createTestInput(t *testing.T) (sdk.Context, Keeper, bank.Keeper) {
+	db := dbm.NewMemDB()
+	keyAcc := sdk.NewKVStoreKey("acc")
+	keyAuthz := sdk.NewKVStoreKey("authz")
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(keyAcc, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyAuthz, sdk.StoreTypeIAVL, db)
+	require.Nil(t, ms.LoadLatestVersion())
+
+	cdc := wire.NewCodec()
+	auth.RegisterBaseAccount(cdc)
+	RegisterWire(cdc)
+	ctx := sdk.NewContext(ms, wrsp.Header{Time: 100}, false, nil, log.NewNopLogger())
+	ck := bank.NewKeeper(auth.NewAccountMapper(cdc, keyAcc, &auth.BaseAccount{}))
+
+	router := bam.NewRouter()
+	router.AddRoute("bank", bank.NewHandler(ck))
+	keeper := NewKeeper(cdc, keyAuthz, router, DefaultCodespace)
+	router.AddRoute("authz", NewHandler(keeper))
+	return ctx, keeper, ck
+}
+
+func newSend(from, to sdk.Address, amount int64) bank.MsgSend {
+	coins := sdk.Coins{{"steak", amount}}
+	return bank.NewMsgSend([]bank.Input{bank.NewInput(from, coins)}, []bank.Output{bank.NewOutput(to, coins)})
+}
+
+func TestExecSendAuthorization(t *testing.T) {
+	ctx, keeper, ck := createTestInput(t)
+	handler := NewHandler(keeper)
+	ck.SetCoins(ctx, granter, sdk.Coins{{"steak", 1000}})
+
+	// nothing was granted yet
+	res := handler(ctx, NewMsgExec(grantee, []sdk.Msg{newSend(granter, grantee, 60)}))
+	assert.Equal(t, sdk.ToWRSPCode(DefaultCodespace, CodeUnknownGrant), res.Code)
+
+	msg := NewMsgGrant(granter, grantee, NewSendAuthorization(sdk.Coins{{"steak", 100}}), 0)
+	require.Nil(t, msg.ValidateBasic())
+	res = handler(ctx, msg)
+	require.True(t, res.IsOK())
+
+	// the grantee sends the coins of the granter, within the spend limit
+	res = handler(ctx, NewMsgExec(grantee, []sdk.Msg{newSend(granter, other, 60)}))
+	require.True(t, res.IsOK(), res.Log)
+	assert.True(t, ck.GetCoins(ctx, granter).IsEqual(sdk.Coins{{"steak", 940}}))
+	assert.True(t, ck.GetCoins(ctx, other).IsEqual(sdk.Coins{{"steak", 60}}))
+	grant, found := keeper.GetGrant(ctx, granter, grantee, "bank/MsgSend")
+	require.True(t, found)
+	assert.Equal(t, NewSendAuthorization(sdk.Coins{{"steak", 40}}), grant.Authorization)
+
+	res = handler(ctx, NewMsgExec(grantee, []sdk.Msg{newSend(granter, other, 60)}))
+	assert.Equal(t, sdk.ToWRSPCode(DefaultCodespace, CodeUnauthorized), res.Code)
+
+	// exhausting the spend limit removes the grant
+	res = handler(ctx, NewMsgExec(grantee, []sdk.Msg{newSend(granter, other, 40)}))
+	require.True(t, res.IsOK(), res.Log)
+	_, found = keeper.GetGrant(ctx, granter, grantee, "bank/MsgSend")
+	assert.False(t, found)
+
+	// msgs of accounts which didn't grant anything are refused
+	res = handler(ctx, NewMsgExec(grantee, []sdk.Msg{newSend(other, grantee, 10)}))
+	assert.Equal(t, sdk.ToWRSPCode(DefaultCodespace, CodeUnknownGrant), res.Code)
+}
+
+func TestGenericAuthorization(t *testing.T) {
+	ctx, keeper, ck := createTestInput(t)
+	handler := NewHandler(keeper)
+	ck.SetCoins(ctx, granter, sdk.Coins{{"steak", 1000}})
+
+	// grants can't expire in the past
+	res := handler(ctx, NewMsgGrant(granter, grantee, NewGenericAuthorization("bank/MsgSend"), 50))
+	assert.Equal(t, sdk.ToWRSPCode(DefaultCodespace, CodeInvalidGrant), res.Code)
+
+	res = handler(ctx, NewMsgGrant(granter, grantee, NewGenericAuthorization("bank/MsgSend"), 200))
+	require.True(t, res.IsOK())
+	assert.Equal(t, 1, len(keeper.GetGrants(ctx, granter, grantee)))
+
+	res = handler(ctx, NewMsgExec(grantee, []sdk.Msg{newSend(granter, other, 600)}))
+	require.True(t, res.IsOK(), res.Log)
+	res = handler(ctx, NewMsgExec(grantee, []sdk.Msg{newSend(granter, other, 400)}))
+	require.True(t, res.IsOK(), res.Log)
+	assert.True(t, ck.GetCoins(ctx, granter).IsEqual(sdk.Coins{}))
+
+	// revoked grants can't be used
+	res = handler(ctx, NewMsgRevoke(granter, grantee, "bank/MsgSend"))
+	require.True(t, res.IsOK())
+	res = handler(ctx, NewMsgExec(grantee, []sdk.Msg{newSend(granter, other, 1)}))
+	assert.Equal(t, sdk.ToWRSPCode(DefaultCodespace, CodeUnknownGrant), res.Code)
+
+	// expired grants either
+	res = handler(ctx, NewMsgGrant(granter, grantee, NewGenericAuthorization("bank/MsgSend"), 200))
+	require.True(t, res.IsOK())
+	res = handler(ctx.WithBlockHeader(wrsp.Header{Time: 200}), NewMsgExec(grantee, []sdk.Msg{newSend(granter, other, 1)}))
+	assert.Equal(t, sdk.ToWRSPCode(DefaultCodespace, CodeUnauthorized), res.Code)
+}
+
+func TestGrantKeys(t *testing.T) {
+	// the keys of different granters and grantees don't collide
+	key1 := GetGrantKey(sdk.Address([]byte("ab")), sdk.Address([]byte("c")), "bank/MsgSend")
+	key2 := GetGrantKey(sdk.Address([]byte("a")), sdk.Address([]byte("bc")), "bank/MsgSend")
+	assert.NotEqual(t, key1, key2)
+
+	ctx, keeper, _ := createTestInput(t)
+	grant := NewGrant(NewGenericAuthorization("bank/MsgSend"), 0)
+	keeper.SetGrant(ctx, sdk.Address([]byte("ab")), sdk.Address([]byte("c")), grant)
+	assert.Empty(t, keeper.GetGrants(ctx, sdk.Address([]byte("a")), sdk.Address([]byte("bc"))))
+	assert.Equal(t, 1, len(keeper.GetGrants(ctx, sdk.Address([]byte("ab")), sdk.Address([]byte("c")))))
+}
+
+func TestSendAuthorizationCodespace(t *testing.T) {
+	codespace := sdk.CodespaceType(20)
+	authorization := NewSendAuthorization(sdk.Coins{{"steak", 10}})
+	_, err := authorization.Accept(codespace, granter, newSend(granter, other, 20))
+	require.NotNil(t, err)
+	assert.Equal(t, sdk.ToWRSPCode(codespace, CodeUnauthorized), err.WRSPCode())
+}
+
+func TestSendAuthorizationGranterInputs(t *testing.T) {
+	// only the coins sent by the granter count against the spend limit
+	authorization := NewSendAuthorization(sdk.Coins{{"steak", 10}})
+	send := bank.NewMsgSend(
+		[]bank.Input{bank.NewInput(granter, sdk.Coins{{"steak", 4}}), bank.NewInput(grantee, sdk.Coins{{"steak", 50}})},
+		[]bank.Output{bank.NewOutput(other, sdk.Coins{{"steak", 54}})},
+	)
+	updated, err := authorization.Accept(DefaultCodespace, granter, send)
+	require.Nil(t, err)
+	assert.Equal(t, NewSendAuthorization(sdk.Coins{{"steak", 6}}), updated)
+}
+
+func TestAuthzGenesis(t *testing.T) {
+	ctx, keeper, _ := createTestInput(t)
+	keeper.SetGrant(ctx, granter, grantee, NewGrant(NewSendAuthorization(sdk.Coins{{"steak", 10}}), 0))
+	keeper.SetGrant(ctx, granter, other, NewGrant(NewGenericAuthorization("bank/MsgSend"), 200))
+	genesis := WriteGenesis(ctx, keeper)
+	require.Len(t, genesis.Grants, 2)
+
+	ctx, keeper, _ = createTestInput(t)
+	require.Nil(t, InitGenesis(ctx, keeper, genesis))
+	assert.Equal(t, genesis, WriteGenesis(ctx, keeper))
+	grant, found := keeper.GetGrant(ctx, granter, other, "bank/MsgSend")
+	require.True(t, found)
+	assert.Equal(t, int64(200), grant.Expiration)
+
+	// grants to oneself or without authorization are rejected
+	invalid := GenesisState{[]GenesisGrant{{granter, granter, NewGrant(NewGenericAuthorization("bank/MsgSend"), 0)}}}
+	assert.NotNil(t, InitGenesis(ctx, keeper, invalid))
+	invalid = GenesisState{[]GenesisGrant{{granter, grantee, Grant{}}}}
+	assert.NotNil(t, InitGenesis(ctx, keeper, invalid))
+}
@@ -0,0 +1,173 @@
+package authz
+
+import (
+	"encoding/json"
+
+	sdk "github.com/tepleton/tepleton-sdk/types"
+)
+
+// name to identify transaction types
+const MsgType = "authz"
+
+//-----------------------------------------------------------
+// MsgGrant
+
+// MsgGrant - grant an authorization to the grantee
+type MsgGrant struct {
+	Granter       sdk.Address   `json:"granter"`
+	Grantee       sdk.Address   `json:"grantee"`
+	Authorization Authorization `json:"authorization"`
+	Expiration    int64         `json:"expiration"`
+}
+
+var _ sdk.Msg = MsgGrant{}
+
+// NewMsgGrant - msg to grant an authorization to the grantee
+func NewMsgGrant(granter, grantee sdk.Address, authorization Authorization, expiration int64) MsgGrant {
+	return MsgGrant{
+		Granter:       granter,
+		Grantee:       grantee,
+		Authorization: authorization,
+		Expiration:    expiration,
+	}
+}
+
+// Implements Msg.
+func (msg MsgGrant) Type() string { return MsgType }
+
+// Implements Msg.
+func (msg MsgGrant) ValidateBasic() sdk.Error {
+	if len(msg.Granter) == 0 || len(msg.Grantee) == 0 {
+		return sdk.ErrInvalidAddress("granter and grantee are required")
+	}
+	if msg.Granter.String() == msg.Grantee.String() {
+		return ErrInvalidGrant(DefaultCodespace, "granter can't grant to itself")
+	}
+	if msg.Authorization == nil || len(msg.Authorization.MsgName()) == 0 {
+		return ErrInvalidGrant(DefaultCodespace, "authorization of a msg type is required")
+	}
+	if msg.Expiration < 0 {
+		return ErrInvalidGrant(DefaultCodespace, "expiration can't be negative")
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgGrant) GetSignBytes() []byte {
+	b, err := msgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgGrant) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Granter}
+}
+
+//-----------------------------------------------------------
+// MsgRevoke
+
+// MsgRevoke - revoke the grant of a msg type to the grantee
+type MsgRevoke struct {
+	Granter sdk.Address `json:"granter"`
+	Grantee sdk.Address `json:"grantee"`
+	MsgName string      `json:"msg_name"`
+}
+
+var _ sdk.Msg = MsgRevoke{}
+
+// NewMsgRevoke - msg to revoke the grant of msgName to the grantee
+func NewMsgRevoke(granter, grantee sdk.Address, msgName string) MsgRevoke {
+	return MsgRevoke{Granter: granter, Grantee: grantee, MsgName: msgName}
+}
+
+// Implements Msg.
+func (msg MsgRevoke) Type() string { return MsgType }
+
+// Implements Msg.
+func (msg MsgRevoke) ValidateBasic() sdk.Error {
+	if len(msg.Granter) == 0 || len(msg.Grantee) == 0 {
+		return sdk.ErrInvalidAddress("granter and grantee are required")
+	}
+	if len(msg.MsgName) == 0 {
+		return ErrInvalidGrant(DefaultCodespace, "msg name is required")
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgRevoke) GetSignBytes() []byte {
+	b, err := msgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgRevoke) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Granter}
+}
+
+//-----------------------------------------------------------
+// MsgExec
+
+// MsgExec - execute msgs on behalf of the granters, signed by the grantee
+type MsgExec struct {
+	Grantee sdk.Address `json:"grantee"`
+	Msgs    []sdk.Msg   `json:"msgs"`
+}
+
+var _ sdk.Msg = MsgExec{}
+
+// NewMsgExec - msg to execute msgs granted to the grantee
+func NewMsgExec(grantee sdk.Address, msgs []sdk.Msg) MsgExec {
+	return MsgExec{Grantee: grantee, Msgs: msgs}
+}
+
+// Implements Msg.
+func (msg MsgExec) Type() string { return MsgType }
+
+// Implements Msg.
+func (msg MsgExec) ValidateBasic() sdk.Error {
+	if len(msg.Grantee) == 0 {
+		return sdk.ErrInvalidAddress("grantee is required")
+	}
+	if len(msg.Msgs) == 0 {
+		return sdk.ErrUnknownRequest("no msgs to execute")
+	}
+	for _, m := range msg.Msgs {
+		err := m.ValidateBasic()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Implements Msg.
+// The msgs sign with their own sign bytes.
+func (msg MsgExec) GetSignBytes() []byte {
+	msgs := make([]json.RawMessage, len(msg.Msgs))
+	for i, m := range msg.Msgs {
+		msgs[i] = m.GetSignBytes()
+	}
+	b, err := msgCdc.MarshalJSON(struct {
+		Grantee string            `json:"grantee"`
+		Msgs    []json.RawMessage `json:"msgs"`
+	}{
+		Grantee: sdk.MustBech32ifyAcc(msg.Grantee),
+		Msgs:    msgs,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgExec) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Grantee}
+}
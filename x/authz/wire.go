@@ -0,0 +1,22 @@
+package authz
+
+import (
+	"github.com/tepleton/tepleton-sdk/wire"
+)
+
+// Register concrete types on wire codec
+func RegisterWire(cdc *wire.Codec) {
+	cdc.RegisterConcrete(MsgGrant{}, "tepleton-sdk/MsgGrant", nil)
+	cdc.RegisterConcrete(MsgRevoke{}, "tepleton-sdk/MsgRevoke", nil)
+	cdc.RegisterConcrete(MsgExec{}, "tepleton-sdk/MsgExec", nil)
+
+	cdc.RegisterInterface((*Authorization)(nil), nil)
+	cdc.RegisterConcrete(GenericAuthorization{}, "authz/GenericAuthorization", nil)
+	cdc.RegisterConcrete(SendAuthorization{}, "authz/SendAuthorization", nil)
+}
+
+var msgCdc = wire.NewCodec()
+
+func init() {
+	RegisterWire(msgCdc)
+}
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tepleton/tepleton-sdk/client/context"
+	"github.com/tepleton/tepleton-sdk/client/keys"
+	sdk "github.com/tepleton/tepleton-sdk/types"
+	"github.com/tepleton/tepleton-sdk/wire"
+	authcmd "github.com/tepleton/tepleton-sdk/x/auth/client/cli"
+	"github.com/tepleton/tepleton-sdk/x/bank"
+)
+
+// IssueTxCmd will create an issue tx, minting coins to an address, and sign
+// it with the key of the issuer
+func IssueTxCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "issue",
+		Short: "Create and sign an issue tx",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+
+			banker, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			to, err := keys.GetAccAddress(viper.GetString(flagTo))
+			if err != nil {
+				return err
+			}
+			coins, err := sdk.ParseCoins(viper.GetString(flagAmount))
+			if err != nil {
+				return err
+			}
+
+			msg := bank.NewMsgIssue(banker, []bank.Output{bank.NewOutput(to, coins)})
+			res, err := ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, msg, cdc)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Committed at block %d. Hash: %s\n", res.Height, res.Hash.String())
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagTo, "", "Address to issue coins to")
+	cmd.Flags().String(flagAmount, "", "Amount of coins to issue")
+	return cmd
+}
+
+// BurnTxCmd will create a burn tx, destroying coins of the signer
+func BurnTxCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "burn",
+		Short: "Create and sign a burn tx",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+
+			owner, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			coins, err := sdk.ParseCoins(viper.GetString(flagAmount))
+			if err != nil {
+				return err
+			}
+
+			msg := bank.NewMsgBurn(owner, coins)
+			res, err := ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, msg, cdc)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Committed at block %d. Hash: %s\n", res.Height, res.Hash.String())
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagAmount, "", "Amount of coins to burn")
+	return cmd
+}
+
+// SetIssuerTxCmd will create a tx setting the denominations an issuer may
+// mint, signed with the key of the authority
+func SetIssuerTxCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-issuer [issuer-address] [denoms]",
+		Short: "Create and sign a tx setting the denominations an issuer may mint, empty to remove it",
+		Long: `Create and sign a tx setting the denominations an issuer may mint, empty to remove it.
+It must be signed with the key of the authority set in the genesis, which stands in for governance.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+
+			authority, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			issuer, err := keys.GetAccAddress(args[0])
+			if err != nil {
+				return err
+			}
+			var denoms []string
+			if len(args) == 2 && args[1] != "" {
+				denoms = strings.Split(args[1], ",")
+			}
+
+			msg := bank.NewMsgSetIssuer(authority, bank.NewIssuer(issuer, denoms...))
+			res, err := ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, msg, cdc)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Committed at block %d. Hash: %s\n", res.Height, res.Hash.String())
+			return nil
+		},
+	}
+	return cmd
+}
+
+// SetSendEnabledTxCmd will create a tx enabling or disabling the sends of a
+// denomination, signed with the key of the authority
+func SetSendEnabledTxCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-send-enabled [denom] [true|false]",
+		Short: "Create and sign a tx enabling or disabling the sends of a denomination",
+		Long: `Create and sign a tx enabling or disabling the sends of a denomination.
+It must be signed with the key of the authority set in the genesis, which stands in for governance.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+
+			authority, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			enabled, err := strconv.ParseBool(args[1])
+			if err != nil {
+				return err
+			}
+
+			msg := bank.NewMsgSetSendEnabled(authority, bank.NewSendEnabled(args[0], enabled))
+			res, err := ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, msg, cdc)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Committed at block %d. Hash: %s\n", res.Height, res.Hash.String())
+			return nil
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tepleton/tepleton-sdk/client/context"
+	sdk "github.com/tepleton/tepleton-sdk/types"
+	"github.com/tepleton/tepleton-sdk/wire"
+	"github.com/tepleton/tepleton-sdk/x/bank"
+)
+
+// GetCmdQuerySupply returns the command to query the supply of coins
+func GetCmdQuerySupply(storeName string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "supply [denom]",
+		Short: "Query the total supply of a denomination, or of all of them",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper()
+
+			supply := sdk.Coins{}
+			if len(args) == 1 {
+				res, err := ctx.Query(bank.GetSupplyKey(args[0]), storeName)
+				if err != nil {
+					return err
+				}
+				var amount int64
+				if len(res) != 0 {
+					cdc.MustUnmarshalBinary(res, &amount)
+				}
+				supply = append(supply, sdk.Coin{args[0], amount})
+			} else {
+				resKVs, err := ctx.QuerySubspace(cdc, bank.SupplyKey, storeName)
+				if err != nil {
+					return err
+				}
+				for _, kv := range resKVs {
+					var amount int64
+					cdc.MustUnmarshalBinary(kv.Value, &amount)
+					supply = append(supply, sdk.Coin{string(kv.Key[len(bank.SupplyKey):]), amount})
+				}
+			}
+
+			output, err := wire.MarshalJSONIndent(cdc, supply)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(output))
+			return nil
+		},
+	}
+
+	return cmd
+}
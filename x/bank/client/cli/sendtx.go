@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/tepleton/tepleton-sdk/client/context"
+	"github.com/tepleton/tepleton-sdk/client/keys"
 	sdk "github.com/tepleton/tepleton-sdk/types"
 	"github.com/tepleton/tepleton-sdk/wire"
 	authcmd "github.com/tepleton/tepleton-sdk/x/auth/client/cli"
@@ -34,7 +35,7 @@ func SendTxCmd(cdc *wire.Codec) *cobra.Command {
 
 			toStr := viper.GetString(flagTo)
 
-			to, err := sdk.GetAccAddressBech32(toStr)
+			to, err := keys.GetAccAddress(toStr)
 			if err != nil {
 				return err
 			}
@@ -56,7 +57,7 @@ func SendTxCmd(cdc *wire.Codec) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().String(flagTo, "", "Address to send coins")
+	cmd.Flags().String(flagTo, "", "Address to send coins, or @name of a contact or a local key")
 	cmd.Flags().String(flagAmount, "", "Amount of coins to send")
 	return cmd
 }
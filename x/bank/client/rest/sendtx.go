@@ -6,9 +6,11 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
-	"github.com/tepleton/go-crypto/keys"
+	ctypes "github.com/tepleton/tepleton/rpc/core/types"
 
 	"github.com/tepleton/tepleton-sdk/client/context"
+	"github.com/tepleton/tepleton-sdk/client/openapi"
+	"github.com/tepleton/tepleton-sdk/crypto/keys"
 	sdk "github.com/tepleton/tepleton-sdk/types"
 	"github.com/tepleton/tepleton-sdk/wire"
 	"github.com/tepleton/tepleton-sdk/x/bank"
@@ -16,8 +18,15 @@ import (
 )
 
 // RegisterRoutes - Central function to define routes that get registered by the main application
-func RegisterRoutes(ctx context.CoreContext, r *mux.Router, cdc *wire.Codec, kb keys.Keybase) {
-	r.HandleFunc("/accounts/{address}/send", SendRequestHandlerFn(cdc, kb, ctx)).Methods("POST")
+func RegisterRoutes(ctx context.CoreContext, r *openapi.Router, cdc *wire.Codec, kb keys.Keybase) {
+	r.Handle(openapi.Route{
+		Method:   "POST",
+		Path:     "/accounts/{address}/send",
+		Summary:  "Send coins from a local key to a bech32 address",
+		Request:  sendBody{},
+		Response: ctypes.ResultBroadcastTxCommit{},
+		Handler:  SendRequestHandlerFn(cdc, kb, ctx),
+	})
 }
 
 type sendBody struct {
@@ -30,6 +39,7 @@ type sendBody struct {
 	AccountNumber    int64     `json:"account_number"`
 	Sequence         int64     `json:"sequence"`
 	Gas              int64     `json:"gas"`
+	FeeGranter       string    `json:"fee_granter"`
 }
 
 var msgCdc = wire.NewCodec()
@@ -81,7 +91,7 @@ func SendRequestHandlerFn(cdc *wire.Codec, kb keys.Keybase, ctx context.CoreCont
 		}
 
 		// build message
-		msg := client.BuildMsg(info.PubKey.Address(), to, m.Amount)
+		msg := client.BuildMsg(info.GetPubKey().Address(), to, m.Amount)
 		if err != nil { // XXX rechecking same error ?
 			w.WriteHeader(http.StatusInternalServerError)
 			w.Write([]byte(err.Error()))
@@ -90,6 +100,7 @@ func SendRequestHandlerFn(cdc *wire.Codec, kb keys.Keybase, ctx context.CoreCont
 
 		// add gas to context
 		ctx = ctx.WithGas(m.Gas)
+		ctx = ctx.WithFeeGranter(m.FeeGranter)
 
 		// sign
 		ctx = ctx.WithAccountNumber(m.AccountNumber)
@@ -1,4 +1,4 @@
-//nolint
+// nolint
 package bank
 
 import (
@@ -9,8 +9,14 @@ import (
 const (
 	DefaultCodespace sdk.CodespaceType = 2
 
-	CodeInvalidInput  sdk.CodeType = 101
-	CodeInvalidOutput sdk.CodeType = 102
+	CodeInvalidInput       sdk.CodeType = 101
+	CodeInvalidOutput      sdk.CodeType = 102
+	CodeInvalidIssuer      sdk.CodeType = 103
+	CodeInsufficientSupply sdk.CodeType = 104
+	CodeSendDisabled       sdk.CodeType = 105
+	CodeBlockedRecipient   sdk.CodeType = 106
+	CodeNotAuthority       sdk.CodeType = 107
+	CodeReservedDenom      sdk.CodeType = 108
 )
 
 // NOTE: Don't stringer this, we'll put better messages in later.
@@ -20,6 +26,18 @@ func codeToDefaultMsg(code sdk.CodeType) string {
 		return "Invalid input coins"
 	case CodeInvalidOutput:
 		return "Invalid output coins"
+	case CodeInvalidIssuer:
+		return "Not allowed to issue the coins"
+	case CodeInsufficientSupply:
+		return "Insufficient supply"
+	case CodeSendDisabled:
+		return "Sends of the coins are disabled"
+	case CodeBlockedRecipient:
+		return "Recipient may not receive sends"
+	case CodeNotAuthority:
+		return "Not the authority"
+	case CodeReservedDenom:
+		return "The supply of the coins is managed by another module"
 	default:
 		return sdk.CodeToDefaultMsg(code)
 	}
@@ -44,6 +62,30 @@ func ErrNoOutputs(codespace sdk.CodespaceType) sdk.Error {
 	return newError(codespace, CodeInvalidOutput, "")
 }
 
+func ErrInvalidIssuer(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeInvalidIssuer, msg)
+}
+
+func ErrInsufficientSupply(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeInsufficientSupply, msg)
+}
+
+func ErrSendDisabled(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeSendDisabled, msg)
+}
+
+func ErrBlockedRecipient(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeBlockedRecipient, msg)
+}
+
+func ErrNotAuthority(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeNotAuthority, msg)
+}
+
+func ErrReservedDenom(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeReservedDenom, msg)
+}
+
 //----------------------------------------
 
 func msgOrDefaultMsg(msg string, code sdk.CodeType) string {
@@ -0,0 +1,104 @@
+package bank
+
+import (
+	"fmt"
+
+	sdk "github.com/tepleton/tepleton-sdk/types"
+)
+
+// GenesisState - the supply and the issuers of the coins, whether they may
+// be sent and to whom, and the authority allowed to change the issuers and
+// the send-enabled parameters. No governance module decides these changes:
+// the authority is a key standing in for governance, so whoever holds it
+// decides them. Without an authority they're fixed at genesis.
+type GenesisState struct {
+	Supply       sdk.Coins     `json:"supply"`
+	Issuers      []Issuer      `json:"issuers"`
+	SendEnabled  []SendEnabled `json:"send_enabled"`
+	BlockedAddrs []sdk.Address `json:"blocked_addrs"`
+	Authority    sdk.Address   `json:"authority"`
+}
+
+// DefaultGenesisState - an empty supply without issuers
+func DefaultGenesisState() GenesisState {
+	return GenesisState{
+		Supply: sdk.Coins{},
+	}
+}
+
+// InitGenesis - set the supply, the issuers and the send-enabled parameters
+// from the genesis
+func InitGenesis(ctx sdk.Context, keeper Keeper, data GenesisState) error {
+	err := ValidateGenesis(data)
+	if err != nil {
+		return err
+	}
+	for _, coin := range data.Supply {
+		keeper.SetSupply(ctx, coin)
+	}
+	for _, issuer := range data.Issuers {
+		keeper.SetIssuer(ctx, issuer)
+	}
+	for _, param := range data.SendEnabled {
+		keeper.SetSendEnabled(ctx, param)
+	}
+	for _, addr := range data.BlockedAddrs {
+		keeper.SetBlockedAddr(ctx, addr, true)
+	}
+	keeper.SetAuthority(ctx, data.Authority)
+	return nil
+}
+
+// WriteGenesis - output the supply, the issuers and the send-enabled
+// parameters for a genesis file
+func WriteGenesis(ctx sdk.Context, keeper Keeper) GenesisState {
+	return GenesisState{
+		Supply:       keeper.GetTotalSupply(ctx),
+		Issuers:      keeper.GetIssuers(ctx),
+		SendEnabled:  keeper.GetAllSendEnabled(ctx),
+		BlockedAddrs: keeper.GetBlockedAddrs(ctx),
+		Authority:    keeper.GetAuthority(ctx),
+	}
+}
+
+// ValidateGenesis checks the supply is made of valid coins, and every issuer
+// and send-enabled denomination is listed once
+func ValidateGenesis(data GenesisState) error {
+	if !data.Supply.IsValid() || !data.Supply.IsNotNegative() {
+		return fmt.Errorf("invalid supply %v", data.Supply)
+	}
+	seen := make(map[string]bool, len(data.Issuers))
+	for _, issuer := range data.Issuers {
+		if len(issuer.Address) == 0 {
+			return fmt.Errorf("issuer of %v has no address", issuer.Denoms)
+		}
+		if seen[string(issuer.Address)] {
+			return fmt.Errorf("duplicate issuer %v", issuer.Address)
+		}
+		seen[string(issuer.Address)] = true
+		if len(issuer.Denoms) == 0 {
+			return fmt.Errorf("issuer %v has no denominations", issuer.Address)
+		}
+	}
+	denoms := make(map[string]bool, len(data.SendEnabled))
+	for _, param := range data.SendEnabled {
+		if len(param.Denom) == 0 {
+			return fmt.Errorf("send-enabled parameter without denomination")
+		}
+		if denoms[param.Denom] {
+			return fmt.Errorf("duplicate send-enabled parameter of %s", param.Denom)
+		}
+		denoms[param.Denom] = true
+	}
+	blocked := make(map[string]bool, len(data.BlockedAddrs))
+	for _, addr := range data.BlockedAddrs {
+		if len(addr) == 0 {
+			return fmt.Errorf("empty blocked address")
+		}
+		if blocked[string(addr)] {
+			return fmt.Errorf("duplicate blocked address %v", addr)
+		}
+		blocked[string(addr)] = true
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package bank
 
 import (
+	"fmt"
 	"reflect"
 
 	sdk "github.com/tepleton/tepleton-sdk/types"
@@ -14,6 +15,12 @@ func NewHandler(k Keeper) sdk.Handler {
 			return handleMsgSend(ctx, k, msg)
 		case MsgIssue:
 			return handleMsgIssue(ctx, k, msg)
+		case MsgBurn:
+			return handleMsgBurn(ctx, k, msg)
+		case MsgSetIssuer:
+			return handleMsgSetIssuer(ctx, k, msg)
+		case MsgSetSendEnabled:
+			return handleMsgSetSendEnabled(ctx, k, msg)
 		default:
 			errMsg := "Unrecognized bank Msg type: " + reflect.TypeOf(msg).Name()
 			return sdk.ErrUnknownRequest(errMsg).Result()
@@ -37,5 +44,59 @@ func handleMsgSend(ctx sdk.Context, k Keeper, msg MsgSend) sdk.Result {
 
 // Handle MsgIssue.
 func handleMsgIssue(ctx sdk.Context, k Keeper, msg MsgIssue) sdk.Result {
-	panic("not implemented yet")
+	allTags := sdk.EmptyTags()
+
+	for _, out := range msg.Outputs {
+		if !k.CanIssue(ctx, msg.Banker, out.Coins) {
+			return ErrInvalidIssuer(DefaultCodespace,
+				fmt.Sprintf("%v may not issue %v", msg.Banker, out.Coins)).Result()
+		}
+		_, tags, err := k.MintCoins(ctx, out.Address, out.Coins)
+		if err != nil {
+			return err.Result()
+		}
+		allTags = allTags.AppendTags(tags)
+	}
+
+	return sdk.Result{
+		Tags: allTags.AppendTag("issuer", []byte(msg.Banker.String())),
+	}
+}
+
+// Handle MsgBurn.
+func handleMsgBurn(ctx sdk.Context, k Keeper, msg MsgBurn) sdk.Result {
+	_, tags, err := k.BurnCoins(ctx, msg.Owner, msg.Coins)
+	if err != nil {
+		return err.Result()
+	}
+
+	return sdk.Result{
+		Tags: tags,
+	}
+}
+
+// Handle MsgSetIssuer.
+func handleMsgSetIssuer(ctx sdk.Context, k Keeper, msg MsgSetIssuer) sdk.Result {
+	if !k.IsAuthority(ctx, msg.Authority) {
+		return ErrNotAuthority(DefaultCodespace,
+			fmt.Sprintf("%v may not set issuers", msg.Authority)).Result()
+	}
+	k.SetIssuer(ctx, msg.Issuer)
+
+	return sdk.Result{
+		Tags: sdk.NewTags("issuer", []byte(msg.Issuer.Address.String())),
+	}
+}
+
+// Handle MsgSetSendEnabled.
+func handleMsgSetSendEnabled(ctx sdk.Context, k Keeper, msg MsgSetSendEnabled) sdk.Result {
+	if !k.IsAuthority(ctx, msg.Authority) {
+		return ErrNotAuthority(DefaultCodespace,
+			fmt.Sprintf("%v may not set send-enabled parameters", msg.Authority)).Result()
+	}
+	k.SetSendEnabled(ctx, msg.SendEnabled)
+
+	return sdk.Result{
+		Tags: sdk.NewTags("denom", []byte(msg.SendEnabled.Denom)),
+	}
 }
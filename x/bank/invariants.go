@@ -0,0 +1,50 @@
+package bank
+
+import (
+	"fmt"
+
+	sdk "github.com/tepleton/tepleton-sdk/types"
+	"github.com/tepleton/tepleton-sdk/x/auth"
+)
+
+// RegisterInvariants registers the invariants of the account balances
+func RegisterInvariants(ir sdk.InvariantRegistry, am auth.AccountMapper) {
+	ir.RegisterInvariant("bank/nonnegative-balances", NonnegativeBalanceInvariant(am))
+}
+
+// NonnegativeBalanceInvariant checks that every account holds valid,
+// non-negative coins
+func NonnegativeBalanceInvariant(am auth.AccountMapper) sdk.Invariant {
+	return func(ctx sdk.Context) (err error) {
+		am.IterateAccounts(ctx, func(acc auth.Account) bool {
+			coins := acc.GetCoins()
+			if !coins.IsValid() || !coins.IsNotNegative() {
+				err = fmt.Errorf("account %v has invalid coins %v", acc.GetAddress(), coins)
+				return true
+			}
+			return false
+		})
+		return err
+	}
+}
+
+// TotalCoinsInvariant checks that the coins of all accounts, plus the coins
+// held outside of the accounts as returned by held, add up to the supply
+// tracked by k
+func TotalCoinsInvariant(am auth.AccountMapper, k Keeper,
+	held func(ctx sdk.Context) sdk.Coins) sdk.Invariant {
+
+	return func(ctx sdk.Context) error {
+		accounts := sdk.Coins{}
+		am.IterateAccounts(ctx, func(acc auth.Account) bool {
+			accounts = accounts.Plus(acc.GetCoins())
+			return false
+		})
+		total := accounts.Plus(held(ctx))
+		supply := k.GetTotalSupply(ctx)
+		if !total.IsEqual(supply) {
+			return fmt.Errorf("accounts and modules hold %v, but the supply is %v", total, supply)
+		}
+		return nil
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	sdk "github.com/tepleton/tepleton-sdk/types"
+	"github.com/tepleton/tepleton-sdk/wire"
 	"github.com/tepleton/tepleton-sdk/x/auth"
 )
 
@@ -15,16 +16,33 @@ const (
 	costAddCoins      sdk.Gas = 10
 )
 
-// Keeper manages transfers between accounts
+// Keeper manages transfers between accounts, and the issuance of coins
 type Keeper struct {
 	am auth.AccountMapper
+
+	// store of the supply, the issuers and the send-enabled parameters,
+	// nil if they aren't tracked
+	storeKey sdk.StoreKey
+	cdc      *wire.Codec
+
+	// recipients refused by sends
+	blockedAddrs map[string]bool
+
+	// denominations which may not be minted or burned, nil if none
+	reservedDenoms func(ctx sdk.Context) []string
 }
 
-// NewKeeper returns a new Keeper
+// NewKeeper returns a new Keeper, which doesn't track the supply
 func NewKeeper(am auth.AccountMapper) Keeper {
 	return Keeper{am: am}
 }
 
+// NewKeeperWithSupply returns a new Keeper tracking the supply of each
+// denomination and its issuers in the store of key
+func NewKeeperWithSupply(cdc *wire.Codec, key sdk.StoreKey, am auth.AccountMapper) Keeper {
+	return Keeper{am: am, storeKey: key, cdc: cdc}
+}
+
 // GetCoins returns the coins at the addr.
 func (keeper Keeper) GetCoins(ctx sdk.Context, addr sdk.Address) sdk.Coins {
 	return getCoins(ctx, keeper.am, addr)
@@ -50,13 +68,56 @@ func (keeper Keeper) AddCoins(ctx sdk.Context, addr sdk.Address, amt sdk.Coins)
 	return addCoins(ctx, keeper.am, addr, amt)
 }
 
-// SendCoins moves coins from one account to another
+// MintCoins adds newly created coins to the addr, increasing the supply.
+func (keeper Keeper) MintCoins(ctx sdk.Context, addr sdk.Address, amt sdk.Coins) (sdk.Coins, sdk.Tags, sdk.Error) {
+	err := keeper.checkReservedDenoms(ctx, amt)
+	if err != nil {
+		return amt, nil, err
+	}
+	newCoins, tags, err := addCoins(ctx, keeper.am, addr, amt)
+	if err != nil {
+		return amt, nil, err
+	}
+	keeper.IncreaseSupply(ctx, amt)
+	return newCoins, tags, nil
+}
+
+// BurnCoins destroys coins of the addr, decreasing the supply.
+func (keeper Keeper) BurnCoins(ctx sdk.Context, addr sdk.Address, amt sdk.Coins) (sdk.Coins, sdk.Tags, sdk.Error) {
+	err := keeper.checkReservedDenoms(ctx, amt)
+	if err != nil {
+		return amt, nil, err
+	}
+	newCoins, tags, err := subtractCoins(ctx, keeper.am, addr, amt)
+	if err != nil {
+		return amt, nil, err
+	}
+	err = keeper.DecreaseSupply(ctx, amt)
+	if err != nil {
+		return amt, nil, err
+	}
+	return newCoins, tags, nil
+}
+
+// SendCoins moves coins from one account to another, unless the recipient
+// is blocked or sends of the coins are disabled
 func (keeper Keeper) SendCoins(ctx sdk.Context, fromAddr sdk.Address, toAddr sdk.Address, amt sdk.Coins) (sdk.Tags, sdk.Error) {
+	err := keeper.CheckSend(ctx, toAddr, amt)
+	if err != nil {
+		return nil, err
+	}
 	return sendCoins(ctx, keeper.am, fromAddr, toAddr, amt)
 }
 
-// InputOutputCoins handles a list of inputs and outputs
+// InputOutputCoins handles a list of inputs and outputs, unless a recipient
+// is blocked or sends of the coins are disabled
 func (keeper Keeper) InputOutputCoins(ctx sdk.Context, inputs []Input, outputs []Output) (sdk.Tags, sdk.Error) {
+	for _, out := range outputs {
+		err := keeper.CheckSend(ctx, out.Address, out.Coins)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return inputOutputCoins(ctx, keeper.am, inputs, outputs)
 }
 
@@ -206,3 +206,156 @@ func TestViewKeeper(t *testing.T) {
 	assert.False(t, viewKeeper.HasCoins(ctx, addr, sdk.Coins{{"foocoin", 15}}))
 	assert.False(t, viewKeeper.HasCoins(ctx, addr, sdk.Coins{{"barcoin", 5}}))
 }
+
+func TestSupply(t *testing.T) {
+	db := dbm.NewMemDB()
+	authKey := sdk.NewKVStoreKey("authkey")
+	bankKey := sdk.NewKVStoreKey("bankkey")
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(authKey, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(bankKey, sdk.StoreTypeIAVL, db)
+	ms.LoadLatestVersion()
+
+	cdc := wire.NewCodec()
+	auth.RegisterBaseAccount(cdc)
+
+	ctx := sdk.NewContext(ms, wrsp.Header{}, false, nil, log.NewNopLogger())
+	accountMapper := auth.NewAccountMapper(cdc, authKey, &auth.BaseAccount{})
+	coinKeeper := NewKeeperWithSupply(cdc, bankKey, accountMapper)
+	handler := NewHandler(coinKeeper)
+
+	issuer := sdk.Address([]byte("issuer"))
+	addr := sdk.Address([]byte("addr1"))
+	coinKeeper.SetIssuer(ctx, NewIssuer(issuer, "foocoin"))
+
+	// only the issuer of a denomination may mint it
+	res := handler(ctx, NewMsgIssue(addr, []Output{NewOutput(addr, sdk.Coins{{"foocoin", 10}})}))
+	assert.Equal(t, sdk.ToWRSPCode(DefaultCodespace, CodeInvalidIssuer), res.Code)
+	res = handler(ctx, NewMsgIssue(issuer, []Output{NewOutput(addr, sdk.Coins{{"barcoin", 10}})}))
+	assert.Equal(t, sdk.ToWRSPCode(DefaultCodespace, CodeInvalidIssuer), res.Code)
+	assert.True(t, coinKeeper.GetTotalSupply(ctx).IsEqual(sdk.Coins{}))
+
+	res = handler(ctx, NewMsgIssue(issuer, []Output{NewOutput(addr, sdk.Coins{{"foocoin", 10}})}))
+	assert.True(t, res.IsOK())
+	assert.True(t, coinKeeper.GetCoins(ctx, addr).IsEqual(sdk.Coins{{"foocoin", 10}}))
+	assert.Equal(t, int64(10), coinKeeper.GetSupply(ctx, "foocoin"))
+
+	// transfers don't change the supply
+	coinKeeper.SendCoins(ctx, addr, issuer, sdk.Coins{{"foocoin", 4}})
+	assert.Equal(t, int64(10), coinKeeper.GetSupply(ctx, "foocoin"))
+
+	// burning decreases it
+	res = handler(ctx, NewMsgBurn(addr, sdk.Coins{{"foocoin", 7}}))
+	assert.False(t, res.IsOK())
+	res = handler(ctx, NewMsgBurn(addr, sdk.Coins{{"foocoin", 6}}))
+	assert.True(t, res.IsOK())
+	assert.True(t, coinKeeper.GetCoins(ctx, addr).IsEqual(sdk.Coins{}))
+	assert.True(t, coinKeeper.GetTotalSupply(ctx).IsEqual(sdk.Coins{{"foocoin", 4}}))
+
+	// the supply can't be decreased below zero
+	err := coinKeeper.DecreaseSupply(ctx, sdk.Coins{{"foocoin", 5}})
+	assert.NotNil(t, err)
+	assert.Equal(t, int64(4), coinKeeper.GetSupply(ctx, "foocoin"))
+
+	// only the authority may change the issuers
+	authority := sdk.Address([]byte("authority"))
+	res = handler(ctx, NewMsgSetIssuer(authority, NewIssuer(addr, "barcoin")))
+	assert.Equal(t, sdk.ToWRSPCode(DefaultCodespace, CodeNotAuthority), res.Code)
+	coinKeeper.SetAuthority(ctx, authority)
+	res = handler(ctx, NewMsgSetIssuer(addr, NewIssuer(addr, "barcoin")))
+	assert.Equal(t, sdk.ToWRSPCode(DefaultCodespace, CodeNotAuthority), res.Code)
+	res = handler(ctx, NewMsgSetIssuer(authority, NewIssuer(addr, "barcoin")))
+	assert.True(t, res.IsOK())
+	assert.True(t, coinKeeper.CanIssue(ctx, addr, sdk.Coins{{"barcoin", 1}}))
+	res = handler(ctx, NewMsgSetIssuer(authority, NewIssuer(addr)))
+	assert.True(t, res.IsOK())
+	_, found := coinKeeper.GetIssuer(ctx, addr)
+	assert.False(t, found)
+
+	// the genesis round-trips
+	genesis := WriteGenesis(ctx, coinKeeper)
+	assert.Equal(t, []Issuer{NewIssuer(issuer, "foocoin")}, genesis.Issuers)
+	assert.Equal(t, authority, genesis.Authority)
+	assert.True(t, genesis.Supply.IsEqual(sdk.Coins{{"foocoin", 4}}))
+	assert.NotNil(t, ValidateGenesis(GenesisState{Issuers: []Issuer{NewIssuer(issuer)}}))
+
+	// coins of a reserved denomination can't be issued or burned
+	reserved := NewHandler(coinKeeper.WithReservedDenoms(func(_ sdk.Context) []string { return []string{"foocoin"} }))
+	res = reserved(ctx, NewMsgIssue(issuer, []Output{NewOutput(addr, sdk.Coins{{"foocoin", 10}})}))
+	assert.Equal(t, sdk.ToWRSPCode(DefaultCodespace, CodeReservedDenom), res.Code)
+	res = reserved(ctx, NewMsgBurn(issuer, sdk.Coins{{"foocoin", 1}}))
+	assert.Equal(t, sdk.ToWRSPCode(DefaultCodespace, CodeReservedDenom), res.Code)
+	assert.Equal(t, int64(4), coinKeeper.GetSupply(ctx, "foocoin"))
+
+	// a keeper without a supply store can't issue coins
+	res = NewHandler(NewKeeper(accountMapper))(ctx, NewMsgIssue(issuer, []Output{NewOutput(addr, sdk.Coins{{"foocoin", 10}})}))
+	assert.Equal(t, sdk.ToWRSPCode(DefaultCodespace, CodeInvalidIssuer), res.Code)
+}
+
+func TestSendRestrictions(t *testing.T) {
+	db := dbm.NewMemDB()
+	authKey := sdk.NewKVStoreKey("authkey")
+	bankKey := sdk.NewKVStoreKey("bankkey")
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(authKey, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(bankKey, sdk.StoreTypeIAVL, db)
+	ms.LoadLatestVersion()
+
+	cdc := wire.NewCodec()
+	auth.RegisterBaseAccount(cdc)
+
+	ctx := sdk.NewContext(ms, wrsp.Header{}, false, nil, log.NewNopLogger())
+	accountMapper := auth.NewAccountMapper(cdc, authKey, &auth.BaseAccount{})
+
+	addr := sdk.Address([]byte("addr1"))
+	addr2 := sdk.Address([]byte("addr2"))
+	moduleAddr := sdk.Address([]byte("module"))
+	coinKeeper := NewKeeperWithSupply(cdc, bankKey, accountMapper).WithBlockedAddrs(moduleAddr)
+	coinKeeper.SetCoins(ctx, addr, sdk.Coins{{"barcoin", 10}, {"foocoin", 10}})
+
+	// sends to blocked addresses are refused
+	_, err := coinKeeper.SendCoins(ctx, addr, moduleAddr, sdk.Coins{{"foocoin", 5}})
+	assert.Equal(t, CodeBlockedRecipient, err.Code())
+	_, err = coinKeeper.InputOutputCoins(ctx,
+		[]Input{NewInput(addr, sdk.Coins{{"foocoin", 5}})},
+		[]Output{NewOutput(moduleAddr, sdk.Coins{{"foocoin", 5}})})
+	assert.Equal(t, CodeBlockedRecipient, err.Code())
+	assert.True(t, coinKeeper.GetCoins(ctx, moduleAddr).IsEqual(sdk.Coins{}))
+
+	// disabled denominations can't be sent, but can still be moved by modules
+	coinKeeper.SetSendEnabled(ctx, NewSendEnabled("foocoin", false))
+	assert.False(t, coinKeeper.GetSendEnabled(ctx, "foocoin"))
+	assert.True(t, coinKeeper.GetSendEnabled(ctx, "barcoin"))
+	_, err = coinKeeper.InputOutputCoins(ctx,
+		[]Input{NewInput(addr, sdk.Coins{{"barcoin", 5}, {"foocoin", 5}})},
+		[]Output{NewOutput(addr2, sdk.Coins{{"barcoin", 5}, {"foocoin", 5}})})
+	assert.Equal(t, CodeSendDisabled, err.Code())
+	_, err = coinKeeper.SendCoins(ctx, addr, addr2, sdk.Coins{{"barcoin", 5}})
+	assert.Nil(t, err)
+	_, _, err = coinKeeper.SubtractCoins(ctx, addr, sdk.Coins{{"foocoin", 5}})
+	assert.Nil(t, err)
+
+	// addresses blocked by the genesis are refused too
+	coinKeeper.SetBlockedAddr(ctx, addr2, true)
+	_, err = coinKeeper.SendCoins(ctx, addr, addr2, sdk.Coins{{"barcoin", 1}})
+	assert.Equal(t, CodeBlockedRecipient, err.Code())
+
+	// the authority may enable the sends again
+	authority := sdk.Address([]byte("authority"))
+	handler := NewHandler(coinKeeper)
+	res := handler(ctx, NewMsgSetSendEnabled(authority, NewSendEnabled("foocoin", true)))
+	assert.Equal(t, sdk.ToWRSPCode(DefaultCodespace, CodeNotAuthority), res.Code)
+	coinKeeper.SetAuthority(ctx, authority)
+	res = handler(ctx, NewMsgSetSendEnabled(authority, NewSendEnabled("foocoin", true)))
+	assert.True(t, res.IsOK())
+	assert.Nil(t, coinKeeper.CheckSendEnabled(ctx, sdk.Coins{{"foocoin", 1}}))
+	res = handler(ctx, NewMsgSetSendEnabled(authority, NewSendEnabled("foocoin", false)))
+	assert.True(t, res.IsOK())
+
+	// the parameters are part of the genesis
+	genesis := WriteGenesis(ctx, coinKeeper)
+	assert.Equal(t, []SendEnabled{NewSendEnabled("foocoin", false)}, genesis.SendEnabled)
+	assert.Equal(t, []sdk.Address{addr2}, genesis.BlockedAddrs)
+	genesis.SendEnabled = append(genesis.SendEnabled, NewSendEnabled("foocoin", true))
+	assert.NotNil(t, ValidateGenesis(genesis))
+}
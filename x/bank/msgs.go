@@ -2,6 +2,7 @@ package bank
 
 import (
 	"encoding/json"
+	"fmt"
 
 	sdk "github.com/tepleton/tepleton-sdk/types"
 )
@@ -84,10 +85,26 @@ func (msg MsgSend) GetSigners() []sdk.Address {
 	return addrs
 }
 
+// Implements auth.TextualMsg.
+func (msg MsgSend) GetSignText() []string {
+	var lines []string
+	for i, in := range msg.Inputs {
+		lines = append(lines,
+			fmt.Sprintf("From %d: %s", i+1, sdk.MustBech32ifyAcc(in.Address)),
+			fmt.Sprintf("From %d amount: %s", i+1, in.Coins))
+	}
+	for i, out := range msg.Outputs {
+		lines = append(lines,
+			fmt.Sprintf("To %d: %s", i+1, sdk.MustBech32ifyAcc(out.Address)),
+			fmt.Sprintf("To %d amount: %s", i+1, out.Coins))
+	}
+	return lines
+}
+
 //----------------------------------------
 // MsgIssue
 
-// MsgIssue - high level transaction of the coin module
+// MsgIssue - mint coins into the outputs, by an issuer of their denominations
 type MsgIssue struct {
 	Banker  sdk.Address `json:"banker"`
 	Outputs []Output    `json:"outputs"`
@@ -95,7 +112,7 @@ type MsgIssue struct {
 
 var _ sdk.Msg = MsgIssue{}
 
-// NewMsgIssue - construct arbitrary multi-in, multi-out send msg.
+// NewMsgIssue - construct an issue msg with arbitrary outputs.
 func NewMsgIssue(banker sdk.Address, out []Output) MsgIssue {
 	return MsgIssue{Banker: banker, Outputs: out}
 }
@@ -105,7 +122,9 @@ func (msg MsgIssue) Type() string { return "bank" } // TODO: "bank/issue"
 
 // Implements Msg.
 func (msg MsgIssue) ValidateBasic() sdk.Error {
-	// XXX
+	if len(msg.Banker) == 0 {
+		return sdk.ErrInvalidAddress("banker address is empty")
+	}
 	if len(msg.Outputs) == 0 {
 		return ErrNoOutputs(DefaultCodespace).TraceSDK("")
 	}
@@ -141,6 +160,169 @@ func (msg MsgIssue) GetSigners() []sdk.Address {
 	return []sdk.Address{msg.Banker}
 }
 
+//----------------------------------------
+// MsgBurn
+
+// MsgBurn - destroy coins of the owner, decreasing the supply
+type MsgBurn struct {
+	Owner sdk.Address `json:"owner"`
+	Coins sdk.Coins   `json:"coins"`
+}
+
+var _ sdk.Msg = MsgBurn{}
+
+// NewMsgBurn - construct a burn msg.
+func NewMsgBurn(owner sdk.Address, coins sdk.Coins) MsgBurn {
+	return MsgBurn{Owner: owner, Coins: coins}
+}
+
+// Implements Msg.
+func (msg MsgBurn) Type() string { return "bank" } // TODO: "bank/burn"
+
+// Implements Msg.
+func (msg MsgBurn) ValidateBasic() sdk.Error {
+	if len(msg.Owner) == 0 {
+		return sdk.ErrInvalidAddress("owner address is empty")
+	}
+	if !msg.Coins.IsValid() {
+		return sdk.ErrInvalidCoins(msg.Coins.String())
+	}
+	if !msg.Coins.IsPositive() {
+		return sdk.ErrInvalidCoins(msg.Coins.String())
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgBurn) GetSignBytes() []byte {
+	b, err := msgCdc.MarshalJSON(struct {
+		Owner string    `json:"owner"`
+		Coins sdk.Coins `json:"coins"`
+	}{
+		Owner: sdk.MustBech32ifyAcc(msg.Owner),
+		Coins: msg.Coins,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgBurn) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Owner}
+}
+
+//----------------------------------------
+// MsgSetIssuer
+
+// MsgSetIssuer - set the denominations an issuer may mint, by the
+// authority, the key of the genesis standing in for governance. An issuer
+// without denominations is removed.
+type MsgSetIssuer struct {
+	Authority sdk.Address `json:"authority"`
+	Issuer    Issuer      `json:"issuer"`
+}
+
+var _ sdk.Msg = MsgSetIssuer{}
+
+// NewMsgSetIssuer - construct a msg setting the issuer.
+func NewMsgSetIssuer(authority sdk.Address, issuer Issuer) MsgSetIssuer {
+	return MsgSetIssuer{Authority: authority, Issuer: issuer}
+}
+
+// Implements Msg.
+func (msg MsgSetIssuer) Type() string { return "bank" } // TODO: "bank/setissuer"
+
+// Implements Msg.
+func (msg MsgSetIssuer) ValidateBasic() sdk.Error {
+	if len(msg.Authority) == 0 {
+		return sdk.ErrInvalidAddress("authority address is empty")
+	}
+	if len(msg.Issuer.Address) == 0 {
+		return sdk.ErrInvalidAddress("issuer address is empty")
+	}
+	for _, denom := range msg.Issuer.Denoms {
+		if len(denom) == 0 {
+			return ErrInvalidIssuer(DefaultCodespace, "empty denomination")
+		}
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgSetIssuer) GetSignBytes() []byte {
+	b, err := msgCdc.MarshalJSON(struct {
+		Authority string   `json:"authority"`
+		Issuer    string   `json:"issuer"`
+		Denoms    []string `json:"denoms"`
+	}{
+		Authority: sdk.MustBech32ifyAcc(msg.Authority),
+		Issuer:    sdk.MustBech32ifyAcc(msg.Issuer.Address),
+		Denoms:    msg.Issuer.Denoms,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgSetIssuer) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Authority}
+}
+
+//----------------------------------------
+// MsgSetSendEnabled
+
+// MsgSetSendEnabled - enable or disable the sends of a denomination, by the
+// authority, the key of the genesis standing in for governance
+type MsgSetSendEnabled struct {
+	Authority   sdk.Address `json:"authority"`
+	SendEnabled SendEnabled `json:"send_enabled"`
+}
+
+var _ sdk.Msg = MsgSetSendEnabled{}
+
+// NewMsgSetSendEnabled - construct a msg setting the send-enabled parameter.
+func NewMsgSetSendEnabled(authority sdk.Address, param SendEnabled) MsgSetSendEnabled {
+	return MsgSetSendEnabled{Authority: authority, SendEnabled: param}
+}
+
+// Implements Msg.
+func (msg MsgSetSendEnabled) Type() string { return "bank" } // TODO: "bank/setsendenabled"
+
+// Implements Msg.
+func (msg MsgSetSendEnabled) ValidateBasic() sdk.Error {
+	if len(msg.Authority) == 0 {
+		return sdk.ErrInvalidAddress("authority address is empty")
+	}
+	if len(msg.SendEnabled.Denom) == 0 {
+		return sdk.ErrInvalidCoins("empty denomination")
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgSetSendEnabled) GetSignBytes() []byte {
+	b, err := msgCdc.MarshalJSON(struct {
+		Authority   string      `json:"authority"`
+		SendEnabled SendEnabled `json:"send_enabled"`
+	}{
+		Authority:   sdk.MustBech32ifyAcc(msg.Authority),
+		SendEnabled: msg.SendEnabled,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgSetSendEnabled) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Authority}
+}
+
 //----------------------------------------
 // Input
 
@@ -245,7 +245,12 @@ func TestMsgIssueType(t *testing.T) {
 }
 
 func TestMsgIssueValidation(t *testing.T) {
-	// TODO
+	addr := sdk.Address([]byte("loan-from-bank"))
+	coins := sdk.Coins{{"atom", 10}}
+
+	assert.Nil(t, NewMsgIssue(sdk.Address([]byte("input")), []Output{NewOutput(addr, coins)}).ValidateBasic())
+	assert.NotNil(t, NewMsgIssue(nil, []Output{NewOutput(addr, coins)}).ValidateBasic())
+	assert.NotNil(t, NewMsgIssue(sdk.Address([]byte("input")), nil).ValidateBasic())
 }
 
 func TestMsgIssueGetSignBytes(t *testing.T) {
@@ -268,3 +273,21 @@ func TestMsgIssueGetSigners(t *testing.T) {
 	res := msg.GetSigners()
 	assert.Equal(t, fmt.Sprintf("%v", res), "[6F6E6C796F6E65]")
 }
+
+// ----------------------------------------
+// MsgBurn Tests
+
+func TestMsgBurnValidation(t *testing.T) {
+	addr := sdk.Address([]byte("owner"))
+
+	assert.Nil(t, NewMsgBurn(addr, sdk.Coins{{"atom", 10}}).ValidateBasic())
+	assert.NotNil(t, NewMsgBurn(nil, sdk.Coins{{"atom", 10}}).ValidateBasic())
+	assert.NotNil(t, NewMsgBurn(addr, sdk.Coins{}).ValidateBasic())
+	assert.NotNil(t, NewMsgBurn(addr, sdk.Coins{{"atom", -10}}).ValidateBasic())
+}
+
+func TestMsgBurnGetSigners(t *testing.T) {
+	msg := NewMsgBurn(sdk.Address([]byte("onlyone")), sdk.Coins{{"atom", 10}})
+	res := msg.GetSigners()
+	assert.Equal(t, fmt.Sprintf("%v", res), "[6F6E6C796F6E65]")
+}
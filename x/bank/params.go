@@ -0,0 +1,136 @@
+package bank
+
+import (
+	"fmt"
+
+	sdk "github.com/tepleton/tepleton-sdk/types"
+)
+
+// SendEnabled - whether coins of a denomination may be sent between accounts.
+// Denominations without a parameter are enabled.
+type SendEnabled struct {
+	Denom   string `json:"denom"`
+	Enabled bool   `json:"enabled"`
+}
+
+// NewSendEnabled returns a new SendEnabled
+func NewSendEnabled(denom string, enabled bool) SendEnabled {
+	return SendEnabled{Denom: denom, Enabled: enabled}
+}
+
+// GetSendEnabled returns whether coins of denom may be sent
+func (keeper Keeper) GetSendEnabled(ctx sdk.Context, denom string) bool {
+	if !keeper.TracksSupply() {
+		return true
+	}
+	store := ctx.KVStore(keeper.storeKey)
+	bz := store.Get(GetSendEnabledKey(denom))
+	if bz == nil {
+		return true
+	}
+	var enabled bool
+	keeper.cdc.MustUnmarshalBinary(bz, &enabled)
+	return enabled
+}
+
+// GetAllSendEnabled returns the send-enabled parameters of all denominations
+// which have one
+func (keeper Keeper) GetAllSendEnabled(ctx sdk.Context) (params []SendEnabled) {
+	if !keeper.TracksSupply() {
+		return nil
+	}
+	store := ctx.KVStore(keeper.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, SendEnabledKey)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var enabled bool
+		keeper.cdc.MustUnmarshalBinary(iterator.Value(), &enabled)
+		denom := string(iterator.Key()[len(SendEnabledKey):])
+		params = append(params, NewSendEnabled(denom, enabled))
+	}
+	return params
+}
+
+// SetSendEnabled enables or disables the sends of a denomination, as done by
+// the genesis or the authority
+func (keeper Keeper) SetSendEnabled(ctx sdk.Context, param SendEnabled) {
+	if !keeper.TracksSupply() {
+		return
+	}
+	store := ctx.KVStore(keeper.storeKey)
+	store.Set(GetSendEnabledKey(param.Denom), keeper.cdc.MustMarshalBinary(param.Enabled))
+}
+
+// WithBlockedAddrs returns a copy of the keeper which refuses to send coins
+// to addrs, like addresses controlled by modules which only receive coins
+// through their own handlers
+func (keeper Keeper) WithBlockedAddrs(addrs ...sdk.Address) Keeper {
+	blocked := make(map[string]bool, len(keeper.blockedAddrs)+len(addrs))
+	for addr := range keeper.blockedAddrs {
+		blocked[addr] = true
+	}
+	for _, addr := range addrs {
+		blocked[string(addr)] = true
+	}
+	keeper.blockedAddrs = blocked
+	return keeper
+}
+
+// SetBlockedAddr refuses or allows sends to addr, as done by the genesis
+func (keeper Keeper) SetBlockedAddr(ctx sdk.Context, addr sdk.Address, blocked bool) {
+	if !keeper.TracksSupply() {
+		return
+	}
+	store := ctx.KVStore(keeper.storeKey)
+	if !blocked {
+		store.Delete(GetBlockedAddrKey(addr))
+		return
+	}
+	store.Set(GetBlockedAddrKey(addr), []byte{0x01})
+}
+
+// GetBlockedAddrs returns the recipients refused by sends set in the store,
+// without those the keeper was created with
+func (keeper Keeper) GetBlockedAddrs(ctx sdk.Context) (addrs []sdk.Address) {
+	if !keeper.TracksSupply() {
+		return nil
+	}
+	store := ctx.KVStore(keeper.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, BlockedAddrKey)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		addrs = append(addrs, sdk.Address(iterator.Key()[len(BlockedAddrKey):]))
+	}
+	return addrs
+}
+
+// IsBlockedAddr returns whether sends to addr are refused
+func (keeper Keeper) IsBlockedAddr(ctx sdk.Context, addr sdk.Address) bool {
+	if keeper.blockedAddrs[string(addr)] {
+		return true
+	}
+	if !keeper.TracksSupply() {
+		return false
+	}
+	return ctx.KVStore(keeper.storeKey).Has(GetBlockedAddrKey(addr))
+}
+
+// CheckSendEnabled returns an error unless all denominations of amt may be
+// sent, like coins leaving the chain through IBC
+func (keeper Keeper) CheckSendEnabled(ctx sdk.Context, amt sdk.Coins) sdk.Error {
+	for _, coin := range amt {
+		if !keeper.GetSendEnabled(ctx, coin.Denom) {
+			return ErrSendDisabled(DefaultCodespace, fmt.Sprintf("sends of %s are disabled", coin.Denom))
+		}
+	}
+	return nil
+}
+
+// CheckSend returns an error unless the recipient may receive amt through a
+// send, and the denominations of amt may be sent
+func (keeper Keeper) CheckSend(ctx sdk.Context, toAddr sdk.Address, amt sdk.Coins) sdk.Error {
+	if keeper.IsBlockedAddr(ctx, toAddr) {
+		return ErrBlockedRecipient(DefaultCodespace, fmt.Sprintf("%v may not receive sends", toAddr))
+	}
+	return keeper.CheckSendEnabled(ctx, amt)
+}
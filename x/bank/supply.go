@@ -0,0 +1,252 @@
+package bank
+
+import (
+	"bytes"
+	"fmt"
+
+	sdk "github.com/tepleton/tepleton-sdk/types"
+)
+
+// nolint
+var (
+	// Keys for store prefixes
+	SupplyKey      = []byte{0x00} // prefix for the supply of each denomination
+	IssuerKey      = []byte{0x01} // prefix for each issuer
+	SendEnabledKey = []byte{0x02} // prefix for whether sends of a denomination are enabled
+	AuthorityKey   = []byte{0x03} // key for the authority
+	BlockedAddrKey = []byte{0x04} // prefix for each recipient refused by sends
+)
+
+// get the key for the supply of a denomination
+func GetSupplyKey(denom string) []byte {
+	return append(SupplyKey, []byte(denom)...)
+}
+
+// get the key for the issuer with address
+func GetIssuerKey(addr sdk.Address) []byte {
+	return append(IssuerKey, addr.Bytes()...)
+}
+
+// get the key for whether sends of a denomination are enabled
+func GetSendEnabledKey(denom string) []byte {
+	return append(SendEnabledKey, []byte(denom)...)
+}
+
+// get the key for whether sends to addr are refused
+func GetBlockedAddrKey(addr sdk.Address) []byte {
+	return append(BlockedAddrKey, addr.Bytes()...)
+}
+
+// Issuer is an account allowed to mint coins of some denominations
+type Issuer struct {
+	Address sdk.Address `json:"address"`
+	Denoms  []string    `json:"denoms"`
+}
+
+// NewIssuer returns a new Issuer
+func NewIssuer(addr sdk.Address, denoms ...string) Issuer {
+	return Issuer{Address: addr, Denoms: denoms}
+}
+
+// CanIssue returns whether the issuer may mint coins of denom
+func (i Issuer) CanIssue(denom string) bool {
+	for _, d := range i.Denoms {
+		if d == denom {
+			return true
+		}
+	}
+	return false
+}
+
+//______________________________________________________________________________________________
+
+// TracksSupply returns whether the keeper tracks the supply and the issuers
+func (keeper Keeper) TracksSupply() bool {
+	return keeper.storeKey != nil
+}
+
+// GetSupply returns the total supply of denom
+func (keeper Keeper) GetSupply(ctx sdk.Context, denom string) (amount int64) {
+	if !keeper.TracksSupply() {
+		return 0
+	}
+	store := ctx.KVStore(keeper.storeKey)
+	bz := store.Get(GetSupplyKey(denom))
+	if bz == nil {
+		return 0
+	}
+	keeper.cdc.MustUnmarshalBinary(bz, &amount)
+	return amount
+}
+
+// GetTotalSupply returns the supply of every denomination
+func (keeper Keeper) GetTotalSupply(ctx sdk.Context) (supply sdk.Coins) {
+	supply = sdk.Coins{}
+	if !keeper.TracksSupply() {
+		return supply
+	}
+	store := ctx.KVStore(keeper.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, SupplyKey)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var amount int64
+		keeper.cdc.MustUnmarshalBinary(iterator.Value(), &amount)
+		denom := string(iterator.Key()[len(SupplyKey):])
+		supply = append(supply, sdk.Coin{denom, amount})
+	}
+	return supply
+}
+
+// SetSupply sets the total supply of a denomination
+func (keeper Keeper) SetSupply(ctx sdk.Context, coin sdk.Coin) {
+	if !keeper.TracksSupply() {
+		return
+	}
+	store := ctx.KVStore(keeper.storeKey)
+	if coin.Amount == 0 {
+		store.Delete(GetSupplyKey(coin.Denom))
+		return
+	}
+	store.Set(GetSupplyKey(coin.Denom), keeper.cdc.MustMarshalBinary(coin.Amount))
+}
+
+// IncreaseSupply adds amt to the supply, for coins created outside of the
+// accounts, like staking provisions or incoming IBC transfers
+func (keeper Keeper) IncreaseSupply(ctx sdk.Context, amt sdk.Coins) {
+	for _, coin := range amt {
+		supply := keeper.GetSupply(ctx, coin.Denom)
+		keeper.SetSupply(ctx, sdk.Coin{coin.Denom, supply + coin.Amount})
+	}
+}
+
+// DecreaseSupply removes amt from the supply, for coins which left the chain
+func (keeper Keeper) DecreaseSupply(ctx sdk.Context, amt sdk.Coins) sdk.Error {
+	if !keeper.TracksSupply() {
+		return nil
+	}
+	for _, coin := range amt {
+		supply := keeper.GetSupply(ctx, coin.Denom)
+		if supply < coin.Amount {
+			return ErrInsufficientSupply(DefaultCodespace,
+				fmt.Sprintf("supply of %s is %d, can't remove %d", coin.Denom, supply, coin.Amount))
+		}
+	}
+	for _, coin := range amt {
+		supply := keeper.GetSupply(ctx, coin.Denom)
+		keeper.SetSupply(ctx, sdk.Coin{coin.Denom, supply - coin.Amount})
+	}
+	return nil
+}
+
+//______________________________________________________________________________________________
+
+// GetIssuer returns the issuer with address addr
+func (keeper Keeper) GetIssuer(ctx sdk.Context, addr sdk.Address) (issuer Issuer, found bool) {
+	if !keeper.TracksSupply() {
+		return issuer, false
+	}
+	store := ctx.KVStore(keeper.storeKey)
+	bz := store.Get(GetIssuerKey(addr))
+	if bz == nil {
+		return issuer, false
+	}
+	keeper.cdc.MustUnmarshalBinary(bz, &issuer)
+	return issuer, true
+}
+
+// GetIssuers returns all the issuers
+func (keeper Keeper) GetIssuers(ctx sdk.Context) (issuers []Issuer) {
+	if !keeper.TracksSupply() {
+		return nil
+	}
+	store := ctx.KVStore(keeper.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, IssuerKey)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var issuer Issuer
+		keeper.cdc.MustUnmarshalBinary(iterator.Value(), &issuer)
+		issuers = append(issuers, issuer)
+	}
+	return issuers
+}
+
+// SetIssuer sets the denominations an issuer may mint, as done by the
+// genesis or the authority. An issuer without denominations is removed.
+func (keeper Keeper) SetIssuer(ctx sdk.Context, issuer Issuer) {
+	if !keeper.TracksSupply() {
+		return
+	}
+	store := ctx.KVStore(keeper.storeKey)
+	if len(issuer.Denoms) == 0 {
+		store.Delete(GetIssuerKey(issuer.Address))
+		return
+	}
+	store.Set(GetIssuerKey(issuer.Address), keeper.cdc.MustMarshalBinary(issuer))
+}
+
+// GetAuthority returns the address of the authority, allowed to set the
+// issuers and the send-enabled parameters in place of governance, nil if
+// there is none
+func (keeper Keeper) GetAuthority(ctx sdk.Context) sdk.Address {
+	if !keeper.TracksSupply() {
+		return nil
+	}
+	store := ctx.KVStore(keeper.storeKey)
+	return store.Get(AuthorityKey)
+}
+
+// SetAuthority sets the address of the authority, as done by the genesis.
+// An empty address removes it.
+func (keeper Keeper) SetAuthority(ctx sdk.Context, addr sdk.Address) {
+	if !keeper.TracksSupply() {
+		return
+	}
+	store := ctx.KVStore(keeper.storeKey)
+	if len(addr) == 0 {
+		store.Delete(AuthorityKey)
+		return
+	}
+	store.Set(AuthorityKey, addr)
+}
+
+// IsAuthority returns whether addr is the authority
+func (keeper Keeper) IsAuthority(ctx sdk.Context, addr sdk.Address) bool {
+	authority := keeper.GetAuthority(ctx)
+	return len(authority) != 0 && bytes.Equal(authority, addr)
+}
+
+// CanIssue returns whether addr is allowed to mint the coins amt
+func (keeper Keeper) CanIssue(ctx sdk.Context, addr sdk.Address, amt sdk.Coins) bool {
+	issuer, found := keeper.GetIssuer(ctx, addr)
+	if !found {
+		return false
+	}
+	for _, coin := range amt {
+		if !issuer.CanIssue(coin.Denom) {
+			return false
+		}
+	}
+	return true
+}
+
+// WithReservedDenoms returns a copy of the keeper which refuses to mint or
+// burn the coins of the denominations returned by denoms. Their supply is
+// managed by another module, like the bond denomination by stake, which
+// changes it through its own copy of the keeper.
+func (keeper Keeper) WithReservedDenoms(denoms func(ctx sdk.Context) []string) Keeper {
+	keeper.reservedDenoms = denoms
+	return keeper
+}
+
+// check that none of the coins amt are of a reserved denomination
+func (keeper Keeper) checkReservedDenoms(ctx sdk.Context, amt sdk.Coins) sdk.Error {
+	if keeper.reservedDenoms == nil {
+		return nil
+	}
+	for _, denom := range keeper.reservedDenoms(ctx) {
+		if amt.AmountOf(denom) != 0 {
+			return ErrReservedDenom(DefaultCodespace, fmt.Sprintf("%s can't be minted or burned", denom))
+		}
+	}
+	return nil
+}
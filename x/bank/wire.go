@@ -8,6 +8,9 @@ import (
 func RegisterWire(cdc *wire.Codec) {
 	cdc.RegisterConcrete(MsgSend{}, "tepleton-sdk/Send", nil)
 	cdc.RegisterConcrete(MsgIssue{}, "tepleton-sdk/Issue", nil)
+	cdc.RegisterConcrete(MsgBurn{}, "tepleton-sdk/Burn", nil)
+	cdc.RegisterConcrete(MsgSetIssuer{}, "tepleton-sdk/SetIssuer", nil)
+	cdc.RegisterConcrete(MsgSetSendEnabled{}, "tepleton-sdk/SetSendEnabled", nil)
 }
 
 var msgCdc = wire.NewCodec()
@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/tepleton/tepleton-sdk/client/context"
+	"github.com/tepleton/tepleton-sdk/client/keys"
 	sdk "github.com/tepleton/tepleton-sdk/types"
 	"github.com/tepleton/tepleton-sdk/wire"
 	authcmd "github.com/tepleton/tepleton-sdk/x/auth/client/cli"
@@ -38,7 +39,7 @@ func GetCmdSubmitProposal(cdc *wire.Codec) *cobra.Command {
 			initialDeposit := viper.GetString(flagDeposit)
 
 			// get the from address from the name flag
-			from, err := sdk.GetAccAddressBech32(viper.GetString(flagProposer))
+			from, err := keys.GetAccAddress(viper.GetString(flagProposer))
 			if err != nil {
 				return err
 			}
@@ -90,7 +91,7 @@ func GetCmdDeposit(cdc *wire.Codec) *cobra.Command {
 		Short: "deposit tokens for activing proposal",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// get the from address from the name flag
-			depositer, err := sdk.GetAccAddressBech32(viper.GetString(flagDepositer))
+			depositer, err := keys.GetAccAddress(viper.GetString(flagDepositer))
 			if err != nil {
 				return err
 			}
@@ -137,7 +138,7 @@ func GetCmdVote(cdc *wire.Codec) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 
 			bechVoter := viper.GetString(flagVoter)
-			voter, err := sdk.GetAccAddressBech32(bechVoter)
+			voter, err := keys.GetAccAddress(bechVoter)
 			if err != nil {
 				return err
 			}
@@ -220,7 +221,7 @@ func GetCmdQueryVote(storeName string, cdc *wire.Codec) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			proposalID := viper.GetInt64(flagProposalID)
 
-			voterAddr, err := sdk.GetAccAddressBech32(viper.GetString(flagVoter))
+			voterAddr, err := keys.GetAccAddress(viper.GetString(flagVoter))
 			if err != nil {
 				return err
 			}
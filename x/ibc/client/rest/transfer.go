@@ -6,17 +6,26 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
-	"github.com/tepleton/go-crypto/keys"
+	ctypes "github.com/tepleton/tepleton/rpc/core/types"
 
 	"github.com/tepleton/tepleton-sdk/client/context"
+	"github.com/tepleton/tepleton-sdk/client/openapi"
+	"github.com/tepleton/tepleton-sdk/crypto/keys"
 	sdk "github.com/tepleton/tepleton-sdk/types"
 	"github.com/tepleton/tepleton-sdk/wire"
 	"github.com/tepleton/tepleton-sdk/x/ibc"
 )
 
 // RegisterRoutes - Central function to define routes that get registered by the main application
-func RegisterRoutes(ctx context.CoreContext, r *mux.Router, cdc *wire.Codec, kb keys.Keybase) {
-	r.HandleFunc("/ibc/{destchain}/{address}/send", TransferRequestHandlerFn(cdc, kb, ctx)).Methods("POST")
+func RegisterRoutes(ctx context.CoreContext, r *openapi.Router, cdc *wire.Codec, kb keys.Keybase) {
+	r.Handle(openapi.Route{
+		Method:   "POST",
+		Path:     "/ibc/{destchain}/{address}/send",
+		Summary:  "Transfer coins from a local key to an address on another chain",
+		Request:  transferBody{},
+		Response: ctypes.ResultBroadcastTxCommit{},
+		Handler:  TransferRequestHandlerFn(cdc, kb, ctx),
+	})
 }
 
 type transferBody struct {
@@ -28,6 +37,7 @@ type transferBody struct {
 	AccountNumber    int64     `json:"account_number"`
 	Sequence         int64     `json:"sequence"`
 	Gas              int64     `json:"gas"`
+	FeeGranter       string    `json:"fee_granter"`
 }
 
 // TransferRequestHandler - http request handler to transfer coins to a address
@@ -76,11 +86,12 @@ func TransferRequestHandlerFn(cdc *wire.Codec, kb keys.Keybase, ctx context.Core
 		to := sdk.Address(bz)
 
 		// build message
-		packet := ibc.NewIBCPacket(info.PubKey.Address(), to, m.Amount, m.SrcChainID, destChainID)
+		packet := ibc.NewIBCPacket(info.GetPubKey().Address(), to, m.Amount, m.SrcChainID, destChainID)
 		msg := ibc.IBCTransferMsg{packet}
 
 		// add gas to context
 		ctx = ctx.WithGas(m.Gas)
+		ctx = ctx.WithFeeGranter(m.FeeGranter)
 
 		// sign
 		ctx = ctx.WithAccountNumber(m.AccountNumber)
@@ -132,3 +132,36 @@ func TestIBC(t *testing.T) {
 	igs = ibcm.GetIngressSequence(ctx, chainid)
 	assert.Equal(t, igs, int64(1))
 }
+
+func TestIBCReceiveBlocked(t *testing.T) {
+	cdc := makeCodec()
+
+	key := sdk.NewKVStoreKey("ibc")
+	ctx := defaultContext(key)
+
+	am := auth.NewAccountMapper(cdc, key, &auth.BaseAccount{})
+	dest := newAddress()
+	ck := bank.NewKeeper(am).WithBlockedAddrs(dest)
+
+	ibcm := NewMapper(cdc, key, DefaultCodespace)
+	h := NewHandler(ibcm, ck)
+	msg := IBCReceiveMsg{
+		IBCPacket: IBCPacket{
+			SrcAddr:   newAddress(),
+			DestAddr:  dest,
+			Coins:     sdk.Coins{sdk.Coin{"mycoin", 10}},
+			SrcChain:  "ibcchain",
+			DestChain: "ibcchain",
+		},
+		Relayer:  newAddress(),
+		Sequence: 0,
+	}
+
+	// blocked recipients can't receive coins through IBC either
+	res := h(ctx, msg)
+	assert.Equal(t, bank.CodeBlockedRecipient, res.Code)
+	coins, err := getCoins(ck, ctx, dest)
+	assert.Nil(t, err)
+	assert.Equal(t, sdk.Coins(nil), coins)
+	assert.Equal(t, int64(0), ibcm.GetIngressSequence(ctx, "ibcchain"))
+}
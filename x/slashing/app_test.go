@@ -55,7 +55,10 @@ func getEndBlocker(keeper stake.Keeper) sdk.EndBlocker {
 func getInitChainer(mapp *mock.App, keeper stake.Keeper) sdk.InitChainer {
 	return func(ctx sdk.Context, req wrsp.RequestInitChain) wrsp.ResponseInitChain {
 		mapp.InitChainer(ctx, req)
-		stake.InitGenesis(ctx, keeper, stake.DefaultGenesisState())
+		err := stake.InitGenesis(ctx, keeper, stake.DefaultGenesisState())
+		if err != nil {
+			panic(err)
+		}
 		return wrsp.ResponseInitChain{}
 	}
 }
@@ -0,0 +1,108 @@
+package slashing
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	sdk "github.com/tepleton/tepleton-sdk/types"
+)
+
+// GenesisState - the signing infos of the validators
+type GenesisState struct {
+	SigningInfos []SigningInfo `json:"signing_infos"`
+}
+
+// SigningInfo - the signing info of a validator, with the indexes of the
+// blocks it signed in its signed blocks window
+type SigningInfo struct {
+	Address      sdk.Address          `json:"address"`
+	Info         ValidatorSigningInfo `json:"info"`
+	SignedBlocks []int64              `json:"signed_blocks"`
+}
+
+// DefaultGenesisState - no signing infos
+func DefaultGenesisState() GenesisState {
+	return GenesisState{
+		SigningInfos: []SigningInfo{},
+	}
+}
+
+// InitGenesis - set the signing infos of the validators from the genesis
+func InitGenesis(ctx sdk.Context, k Keeper, data GenesisState) error {
+	for _, signingInfo := range data.SigningInfos {
+		if len(signingInfo.Address) == 0 {
+			return fmt.Errorf("signing info without address in genesis state")
+		}
+		if int64(len(signingInfo.SignedBlocks)) != signingInfo.Info.SignedBlocksCounter {
+			return fmt.Errorf("signed blocks counter %d of validator %v doesn't match its %d signed blocks",
+				signingInfo.Info.SignedBlocksCounter, signingInfo.Address, len(signingInfo.SignedBlocks))
+		}
+		for _, index := range signingInfo.SignedBlocks {
+			if index < 0 || index >= SignedBlocksWindow {
+				return fmt.Errorf("signed block index %d of validator %v out of the signed blocks window", index, signingInfo.Address)
+			}
+		}
+	}
+	for _, signingInfo := range data.SigningInfos {
+		k.setValidatorSigningInfo(ctx, signingInfo.Address, signingInfo.Info)
+		for _, index := range signingInfo.SignedBlocks {
+			k.setValidatorSigningBitArray(ctx, signingInfo.Address, index, true)
+		}
+	}
+	return nil
+}
+
+// WriteGenesis - output the signing infos of the validators
+func WriteGenesis(ctx sdk.Context, k Keeper) GenesisState {
+	signingInfos := []SigningInfo{}
+	k.iterateValidatorSigningInfos(ctx, func(address sdk.Address, info ValidatorSigningInfo) (stop bool) {
+		signingInfos = append(signingInfos, SigningInfo{
+			Address:      address,
+			Info:         info,
+			SignedBlocks: k.getValidatorSignedBlocks(ctx, address),
+		})
+		return false
+	})
+	return GenesisState{
+		SigningInfos: signingInfos,
+	}
+}
+
+// indexes of the blocks the validator signed in its signed blocks window
+func (k Keeper) getValidatorSignedBlocks(ctx sdk.Context, address sdk.Address) []int64 {
+	store := ctx.KVStore(k.storeKey)
+	prefix := append(append([]byte{}, validatorSigningBitArrayKey...), address.Bytes()...)
+	iter := sdk.KVStorePrefixIterator(store, prefix)
+	defer iter.Close()
+	signedBlocks := []int64{}
+	for ; iter.Valid(); iter.Next() {
+		var signed bool
+		k.cdc.MustUnmarshalBinary(iter.Value(), &signed)
+		if signed {
+			index := int64(binary.LittleEndian.Uint64(iter.Key()[len(prefix):]))
+			signedBlocks = append(signedBlocks, index)
+		}
+	}
+	return signedBlocks
+}
+
+// PrepForZeroHeightGenesis - reset the signing infos of all validators for
+// a new chain starting at height zero. Their start heights and signed block
+// windows refer to heights of the old chain, so they start over.
+func PrepForZeroHeightGenesis(ctx sdk.Context, k Keeper) {
+	k.clearValidatorSigningBitArrays(ctx)
+	var addresses []sdk.Address
+	var infos []ValidatorSigningInfo
+	k.iterateValidatorSigningInfos(ctx, func(address sdk.Address, info ValidatorSigningInfo) (stop bool) {
+		addresses = append(addresses, address)
+		infos = append(infos, info)
+		return false
+	})
+	for i, address := range addresses {
+		info := infos[i]
+		info.StartHeight = 0
+		info.IndexOffset = 0
+		info.SignedBlocksCounter = 0
+		k.setValidatorSigningInfo(ctx, address, info)
+	}
+}
@@ -47,6 +47,35 @@ func (k Keeper) setValidatorSigningBitArray(ctx sdk.Context, address sdk.Address
 	store.Set(GetValidatorSigningBitArrayKey(address, index), bz)
 }
 
+// iterate over the signing infos of all validators
+func (k Keeper) iterateValidatorSigningInfos(ctx sdk.Context, handler func(address sdk.Address, info ValidatorSigningInfo) (stop bool)) {
+	store := ctx.KVStore(k.storeKey)
+	iter := sdk.KVStorePrefixIterator(store, validatorSigningInfoKey)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		address := sdk.Address(iter.Key()[len(validatorSigningInfoKey):])
+		var info ValidatorSigningInfo
+		k.cdc.MustUnmarshalBinary(iter.Value(), &info)
+		if handler(address, info) {
+			break
+		}
+	}
+}
+
+// delete the signed block bit arrays of all validators
+func (k Keeper) clearValidatorSigningBitArrays(ctx sdk.Context) {
+	store := ctx.KVStore(k.storeKey)
+	iter := sdk.KVStorePrefixIterator(store, validatorSigningBitArrayKey)
+	var keys [][]byte
+	for ; iter.Valid(); iter.Next() {
+		keys = append(keys, iter.Key())
+	}
+	iter.Close()
+	for _, key := range keys {
+		store.Delete(key)
+	}
+}
+
 // Construct a new `ValidatorSigningInfo` struct
 func NewValidatorSigningInfo(startHeight int64, indexOffset int64, jailedUntil int64, signedBlocksCounter int64) ValidatorSigningInfo {
 	return ValidatorSigningInfo{
@@ -71,14 +100,20 @@ func (i ValidatorSigningInfo) HumanReadableString() string {
 		i.StartHeight, i.IndexOffset, i.JailedUntil, i.SignedBlocksCounter)
 }
 
+// key prefixes of the slashing store
+var (
+	validatorSigningInfoKey     = []byte{0x01}
+	validatorSigningBitArrayKey = []byte{0x02}
+)
+
 // Stored by *validator* address (not owner address)
 func GetValidatorSigningInfoKey(v sdk.Address) []byte {
-	return append([]byte{0x01}, v.Bytes()...)
+	return append(validatorSigningInfoKey, v.Bytes()...)
 }
 
 // Stored by *validator* address (not owner address)
 func GetValidatorSigningBitArrayKey(v sdk.Address, i int64) []byte {
 	b := make([]byte, 8)
 	binary.LittleEndian.PutUint64(b, uint64(i))
-	return append([]byte{0x02}, append(v.Bytes(), b...)...)
+	return append(validatorSigningBitArrayKey, append(v.Bytes(), b...)...)
 }
@@ -33,3 +33,44 @@ func TestGetSetValidatorSigningBitArray(t *testing.T) {
 	signed = keeper.getValidatorSigningBitArray(ctx, addrs[0], 0)
 	require.True(t, signed) // now should be signed
 }
+
+func TestPrepForZeroHeightGenesis(t *testing.T) {
+	ctx, _, _, keeper := createTestInput(t)
+	for _, addr := range addrs[:2] {
+		keeper.setValidatorSigningInfo(ctx, addr, NewValidatorSigningInfo(4, 3, 2, 10))
+		keeper.setValidatorSigningBitArray(ctx, addr, 0, true)
+	}
+	PrepForZeroHeightGenesis(ctx, keeper)
+	for _, addr := range addrs[:2] {
+		info, found := keeper.getValidatorSigningInfo(ctx, addr)
+		require.True(t, found)
+		require.Equal(t, NewValidatorSigningInfo(0, 0, 2, 0), info)
+		require.False(t, keeper.getValidatorSigningBitArray(ctx, addr, 0))
+	}
+}
+
+func TestSlashingGenesis(t *testing.T) {
+	ctx, _, _, keeper := createTestInput(t)
+	keeper.setValidatorSigningInfo(ctx, addrs[0], NewValidatorSigningInfo(4, 3, 2, 2))
+	keeper.setValidatorSigningBitArray(ctx, addrs[0], 0, true)
+	keeper.setValidatorSigningBitArray(ctx, addrs[0], 1, false)
+	keeper.setValidatorSigningBitArray(ctx, addrs[0], 2, true)
+	keeper.setValidatorSigningInfo(ctx, addrs[1], NewValidatorSigningInfo(5, 0, 0, 0))
+	genesis := WriteGenesis(ctx, keeper)
+	require.Equal(t, []SigningInfo{
+		{addrs[0], NewValidatorSigningInfo(4, 3, 2, 2), []int64{0, 2}},
+		{addrs[1], NewValidatorSigningInfo(5, 0, 0, 0), []int64{}},
+	}, genesis.SigningInfos)
+
+	ctx, _, _, keeper = createTestInput(t)
+	require.Nil(t, InitGenesis(ctx, keeper, genesis))
+	require.Equal(t, genesis, WriteGenesis(ctx, keeper))
+	require.True(t, keeper.getValidatorSigningBitArray(ctx, addrs[0], 2))
+	require.False(t, keeper.getValidatorSigningBitArray(ctx, addrs[0], 1))
+
+	// the counter must match the signed blocks, which must be in the window
+	invalid := GenesisState{[]SigningInfo{{addrs[0], NewValidatorSigningInfo(4, 3, 2, 2), []int64{0}}}}
+	require.NotNil(t, InitGenesis(ctx, keeper, invalid))
+	invalid = GenesisState{[]SigningInfo{{addrs[0], NewValidatorSigningInfo(4, 3, 2, 1), []int64{SignedBlocksWindow}}}}
+	require.NotNil(t, InitGenesis(ctx, keeper, invalid))
+}
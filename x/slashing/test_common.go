@@ -62,7 +62,8 @@ func createTestInput(t *testing.T) (sdk.Context, bank.Keeper, stake.Keeper, Keep
 	sk := stake.NewKeeper(cdc, keyStake, ck, stake.DefaultCodespace)
 	genesis := stake.DefaultGenesisState()
 	genesis.Pool.LooseUnbondedTokens = initCoins * int64(len(addrs))
-	stake.InitGenesis(ctx, sk, genesis)
+	err = stake.InitGenesis(ctx, sk, genesis)
+	require.Nil(t, err)
 	for _, addr := range addrs {
 		ck.AddCoins(ctx, addr, sdk.Coins{
 			{sk.GetParams(ctx).BondDenom, initCoins},
@@ -8,6 +8,7 @@ import (
 	"github.com/tepleton/tmlibs/cli"
 
 	"github.com/tepleton/tepleton-sdk/client/context"
+	"github.com/tepleton/tepleton-sdk/client/keys"
 	sdk "github.com/tepleton/tepleton-sdk/types"
 	"github.com/tepleton/tepleton-sdk/wire" // XXX fix
 	"github.com/tepleton/tepleton-sdk/x/stake"
@@ -21,7 +22,7 @@ func GetCmdQueryValidator(storeName string, cdc *wire.Codec) *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 
-			addr, err := sdk.GetAccAddressBech32(args[0])
+			addr, err := keys.GetAccAddress(args[0])
 			if err != nil {
 				return err
 			}
@@ -112,7 +113,7 @@ func GetCmdQueryDelegation(storeName string, cdc *wire.Codec) *cobra.Command {
 		Short: "Query a delegations bond based on address and validator address",
 		RunE: func(cmd *cobra.Command, args []string) error {
 
-			addr, err := sdk.GetAccAddressBech32(viper.GetString(FlagAddressValidator))
+			addr, err := keys.GetAccAddress(viper.GetString(FlagAddressValidator))
 			if err != nil {
 				return err
 			}
@@ -165,7 +166,7 @@ func GetCmdQueryDelegations(storeName string, cdc *wire.Codec) *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 
-			delegatorAddr, err := sdk.GetAccAddressBech32(args[0])
+			delegatorAddr, err := keys.GetAccAddress(args[0])
 			if err != nil {
 				return err
 			}
@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/tepleton/tepleton-sdk/client/context"
+	"github.com/tepleton/tepleton-sdk/client/keys"
 	sdk "github.com/tepleton/tepleton-sdk/types"
 	"github.com/tepleton/tepleton-sdk/wire"
 	authcmd "github.com/tepleton/tepleton-sdk/x/auth/client/cli"
@@ -25,7 +26,7 @@ func GetCmdCreateValidator(cdc *wire.Codec) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			validatorAddr, err := sdk.GetAccAddressBech32(viper.GetString(FlagAddressValidator))
+			validatorAddr, err := keys.GetAccAddress(viper.GetString(FlagAddressValidator))
 			if err != nil {
 				return err
 			}
@@ -74,7 +75,7 @@ func GetCmdEditValidator(cdc *wire.Codec) *cobra.Command {
 		Short: "edit and existing validator account",
 		RunE: func(cmd *cobra.Command, args []string) error {
 
-			validatorAddr, err := sdk.GetAccAddressBech32(viper.GetString(FlagAddressValidator))
+			validatorAddr, err := keys.GetAccAddress(viper.GetString(FlagAddressValidator))
 			if err != nil {
 				return err
 			}
@@ -115,8 +116,8 @@ func GetCmdDelegate(cdc *wire.Codec) *cobra.Command {
 				return err
 			}
 
-			delegatorAddr, err := sdk.GetAccAddressBech32(viper.GetString(FlagAddressDelegator))
-			validatorAddr, err := sdk.GetAccAddressBech32(viper.GetString(FlagAddressValidator))
+			delegatorAddr, err := keys.GetAccAddress(viper.GetString(FlagAddressDelegator))
+			validatorAddr, err := keys.GetAccAddress(viper.GetString(FlagAddressValidator))
 			if err != nil {
 				return err
 			}
@@ -163,8 +164,8 @@ func GetCmdUnbond(cdc *wire.Codec) *cobra.Command {
 				}
 			}
 
-			delegatorAddr, err := sdk.GetAccAddressBech32(viper.GetString(FlagAddressDelegator))
-			validatorAddr, err := sdk.GetAccAddressBech32(viper.GetString(FlagAddressValidator))
+			delegatorAddr, err := keys.GetAccAddress(viper.GetString(FlagAddressDelegator))
+			validatorAddr, err := keys.GetAccAddress(viper.GetString(FlagAddressValidator))
 			if err != nil {
 				return err
 			}
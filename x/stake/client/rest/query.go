@@ -7,20 +7,27 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/tepleton/tepleton-sdk/client/context"
+	"github.com/tepleton/tepleton-sdk/client/openapi"
 	sdk "github.com/tepleton/tepleton-sdk/types"
 	"github.com/tepleton/tepleton-sdk/wire"
 	"github.com/tepleton/tepleton-sdk/x/stake"
 )
 
-func registerQueryRoutes(ctx context.CoreContext, r *mux.Router, cdc *wire.Codec) {
-	r.HandleFunc(
-		"/stake/{delegator}/bonding_status/{validator}",
-		bondingStatusHandlerFn(ctx, "stake", cdc),
-	).Methods("GET")
-	r.HandleFunc(
-		"/stake/validators",
-		validatorsHandlerFn(ctx, "stake", cdc),
-	).Methods("GET")
+func registerQueryRoutes(ctx context.CoreContext, r *openapi.Router, cdc *wire.Codec) {
+	r.Handle(openapi.Route{
+		Method:   "GET",
+		Path:     "/stake/{delegator}/bonding_status/{validator}",
+		Summary:  "Get the delegation of a delegator to a validator",
+		Response: stake.Delegation{},
+		Handler:  bondingStatusHandlerFn(ctx, "stake", cdc),
+	})
+	r.Handle(openapi.Route{
+		Method:   "GET",
+		Path:     "/stake/validators",
+		Summary:  "List all validators",
+		Response: []StakeValidatorOutput{},
+		Handler:  validatorsHandlerFn(ctx, "stake", cdc),
+	})
 }
 
 // http request handler to query delegator bonding status
@@ -7,21 +7,25 @@ import (
 	"io/ioutil"
 	"net/http"
 
-	"github.com/gorilla/mux"
-	"github.com/tepleton/go-crypto/keys"
 	ctypes "github.com/tepleton/tepleton/rpc/core/types"
 
 	"github.com/tepleton/tepleton-sdk/client/context"
+	"github.com/tepleton/tepleton-sdk/client/openapi"
+	"github.com/tepleton/tepleton-sdk/crypto/keys"
 	sdk "github.com/tepleton/tepleton-sdk/types"
 	"github.com/tepleton/tepleton-sdk/wire"
 	"github.com/tepleton/tepleton-sdk/x/stake"
 )
 
-func registerTxRoutes(ctx context.CoreContext, r *mux.Router, cdc *wire.Codec, kb keys.Keybase) {
-	r.HandleFunc(
-		"/stake/delegations",
-		editDelegationsRequestHandlerFn(cdc, kb, ctx),
-	).Methods("POST")
+func registerTxRoutes(ctx context.CoreContext, r *openapi.Router, cdc *wire.Codec, kb keys.Keybase) {
+	r.Handle(openapi.Route{
+		Method:   "POST",
+		Path:     "/stake/delegations",
+		Summary:  "Delegate to and unbond from validators with a local key",
+		Request:  editDelegationsBody{},
+		Response: []ctypes.ResultBroadcastTxCommit{},
+		Handler:  editDelegationsRequestHandlerFn(cdc, kb, ctx),
+	})
 }
 
 type msgDelegateInput struct {
@@ -42,6 +46,7 @@ type editDelegationsBody struct {
 	AccountNumber    int64              `json:"account_number"`
 	Sequence         int64              `json:"sequence"`
 	Gas              int64              `json:"gas"`
+	FeeGranter       string             `json:"fee_granter"`
 	Delegate         []msgDelegateInput `json:"delegate"`
 	Unbond           []msgUnbondInput   `json:"unbond"`
 }
@@ -125,6 +130,7 @@ func editDelegationsRequestHandlerFn(cdc *wire.Codec, kb keys.Keybase, ctx conte
 
 		// add gas to context
 		ctx = ctx.WithGas(m.Gas)
+		ctx = ctx.WithFeeGranter(m.FeeGranter)
 
 		// sign messages
 		signedTxs := make([][]byte, len(messages[:]))
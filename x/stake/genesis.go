@@ -1,70 +1,89 @@
 package stake
 
 import (
+	"encoding/json"
+	"fmt"
+
 	tmtypes "github.com/tepleton/tepleton/types"
 
 	sdk "github.com/tepleton/tepleton-sdk/types"
+	"github.com/tepleton/tepleton-sdk/wire"
+	"github.com/tepleton/tepleton-sdk/x/stake/types"
 )
 
-// GenesisState - all staking state that must be provided at genesis
-type GenesisState struct {
-	Pool       Pool         `json:"pool"`
-	Params     Params       `json:"params"`
-	Validators []Validator  `json:"validators"`
-	Bonds      []Delegation `json:"bonds"`
-}
-
-func NewGenesisState(pool Pool, params Params, validators []Validator, bonds []Delegation) GenesisState {
-	return GenesisState{
-		Pool:       pool,
-		Params:     params,
-		Validators: validators,
-		Bonds:      bonds,
+// InitGenesis - store genesis parameters, along with all secondary indexes
+// of the validators, unbonding delegations and redelegations
+func InitGenesis(ctx sdk.Context, k Keeper, data types.GenesisState) error {
+	err := ValidateGenesis(data)
+	if err != nil {
+		return err
 	}
-}
 
-// get raw genesis raw message for testing
-func DefaultGenesisState() GenesisState {
-	return GenesisState{
-		Pool:   InitialPool(),
-		Params: DefaultParams(),
-	}
-}
-
-// InitGenesis - store genesis parameters
-func InitGenesis(ctx sdk.Context, k Keeper, data GenesisState) {
-	store := ctx.KVStore(k.storeKey)
-	k.setPool(ctx, data.Pool)
-	k.setNewParams(ctx, data.Params)
+	k.SetPool(ctx, data.Pool)
+	k.SetNewParams(ctx, data.Params)
+	k.InitIntraTxCounter(ctx)
 	for _, validator := range data.Validators {
-
-		// set validator
-		k.setValidator(ctx, validator)
+		k.SetValidator(ctx, validator)
 
 		// manually set indexes for the first time
-		k.setValidatorByPubKeyIndex(ctx, validator)
-		k.setValidatorByPowerIndex(ctx, validator, data.Pool)
+		k.SetValidatorByPubKeyIndex(ctx, validator)
+		k.SetValidatorByPowerIndex(ctx, validator, data.Pool)
 		if validator.Status() == sdk.Bonded {
-			store.Set(GetValidatorsBondedKey(validator.PubKey), validator.Owner)
+			k.SetValidatorBondedIndex(ctx, validator)
 		}
 	}
 	for _, bond := range data.Bonds {
-		k.setDelegation(ctx, bond)
+		k.SetDelegation(ctx, bond)
+	}
+	for _, ubd := range data.UnbondingDelegations {
+		k.SetUnbondingDelegation(ctx, ubd)
 	}
-	k.updateBondedValidatorsFull(ctx, store)
+	for _, red := range data.Redelegations {
+		k.SetRedelegation(ctx, red)
+	}
+	k.UpdateBondedValidatorsFull(ctx)
+	return nil
 }
 
 // WriteGenesis - output genesis parameters
-func WriteGenesis(ctx sdk.Context, k Keeper) GenesisState {
-	pool := k.GetPool(ctx)
-	params := k.GetParams(ctx)
-	validators := k.getAllValidators(ctx)
-	bonds := k.getAllDelegations(ctx)
-	return GenesisState{
-		pool,
-		params,
-		validators,
-		bonds,
+func WriteGenesis(ctx sdk.Context, k Keeper) types.GenesisState {
+	return types.GenesisState{
+		Pool:                 k.GetPool(ctx),
+		Params:               k.GetParams(ctx),
+		Validators:           k.GetAllValidators(ctx),
+		Bonds:                k.GetAllDelegations(ctx),
+		UnbondingDelegations: k.GetAllUnbondingDelegations(ctx),
+		Redelegations:        k.GetAllRedelegations(ctx),
+	}
+}
+
+// PrepForZeroHeightGenesis - normalise the staking state to export it
+// for a new chain starting at height zero. All pending unbondings and
+// redelegations are completed and the bond heights are reset.
+func PrepForZeroHeightGenesis(ctx sdk.Context, k Keeper) {
+
+	// complete the queues as if the unbonding time had passed
+	for _, ubd := range k.GetAllUnbondingDelegations(ctx) {
+		header := ctx.BlockHeader()
+		header.Time = ubd.MinTime
+		err := k.CompleteUnbonding(ctx.WithBlockHeader(header), ubd.DelegatorAddr, ubd.ValidatorAddr)
+		if err != nil {
+			panic(err)
+		}
+	}
+	for _, red := range k.GetAllRedelegations(ctx) {
+		k.RemoveRedelegation(ctx, red)
+	}
+
+	// the power index is rebuilt by InitGenesis from the reset heights
+	for _, validator := range k.GetAllValidators(ctx) {
+		validator.BondHeight = 0
+		validator.BondIntraTxCounter = 0
+		k.SetValidator(ctx, validator)
+	}
+	for _, bond := range k.GetAllDelegations(ctx) {
+		bond.Height = 0
+		k.SetDelegation(ctx, bond)
 	}
 }
 
@@ -80,3 +99,170 @@ func WriteValidators(ctx sdk.Context, k Keeper) (vals []tmtypes.GenesisValidator
 	})
 	return
 }
+
+// ValidateGenesis checks that the staking state is consistent: the pool
+// shares equal the sum of the shares of the validators, the delegator
+// shares of every validator equal the sum of its delegations, and all
+// delegations refer to existing validators.
+func ValidateGenesis(data types.GenesisState) error {
+	err := validateParams(data.Params)
+	if err != nil {
+		return err
+	}
+
+	validators := make(map[string]types.Validator, len(data.Validators))
+	pubKeys := make(map[string]bool, len(data.Validators))
+	for _, validator := range data.Validators {
+		if len(validator.Owner) == 0 {
+			return fmt.Errorf("validator without owner")
+		}
+		if validator.PubKey == nil {
+			return fmt.Errorf("validator %v without pubkey", validator.Owner)
+		}
+		owner := string(validator.Owner)
+		if _, ok := validators[owner]; ok {
+			return fmt.Errorf("duplicate validator %v", validator.Owner)
+		}
+		if pubKeys[string(validator.PubKey.Bytes())] {
+			return fmt.Errorf("duplicate pubkey of validator %v", validator.Owner)
+		}
+		if validator.Revoked && validator.Status() == sdk.Bonded {
+			return fmt.Errorf("revoked validator %v is bonded", validator.Owner)
+		}
+		if validator.PoolShares.Amount.LT(sdk.ZeroRat()) || validator.DelegatorShares.LT(sdk.ZeroRat()) {
+			return fmt.Errorf("validator %v has negative shares", validator.Owner)
+		}
+		validators[owner] = validator
+		pubKeys[string(validator.PubKey.Bytes())] = true
+	}
+	err = types.CheckPoolShares(data.Pool, data.Validators)
+	if err != nil {
+		return err
+	}
+	err = types.CheckPoolTokens(data.Pool, data.Validators)
+	if err != nil {
+		return err
+	}
+
+	delegations := make(map[string]bool, len(data.Bonds))
+	for _, bond := range data.Bonds {
+		key := string(bond.DelegatorAddr) + "/" + string(bond.ValidatorAddr)
+		if delegations[key] {
+			return fmt.Errorf("duplicate delegation of %v to %v", bond.DelegatorAddr, bond.ValidatorAddr)
+		}
+		delegations[key] = true
+	}
+	err = types.CheckDelegatorShares(data.Validators, data.Bonds)
+	if err != nil {
+		return err
+	}
+
+	for _, ubd := range data.UnbondingDelegations {
+		if ubd.DelegatorAddr == nil || ubd.ValidatorAddr == nil {
+			return fmt.Errorf("unbonding delegation without address")
+		}
+		err = validateBalance(data.Params, ubd.InitialBalance, ubd.Balance)
+		if err != nil {
+			return fmt.Errorf("unbonding delegation of %v from %v: %v", ubd.DelegatorAddr, ubd.ValidatorAddr, err)
+		}
+	}
+	for _, red := range data.Redelegations {
+		if _, ok := validators[string(red.ValidatorDstAddr)]; !ok {
+			return fmt.Errorf("redelegation of %v to unknown validator %v", red.DelegatorAddr, red.ValidatorDstAddr)
+		}
+		if red.DelegatorAddr == nil || red.ValidatorSrcAddr == nil {
+			return fmt.Errorf("redelegation without address")
+		}
+		err = validateBalance(data.Params, red.InitialBalance, red.Balance)
+		if err != nil {
+			return fmt.Errorf("redelegation of %v from %v: %v", red.DelegatorAddr, red.ValidatorSrcAddr, err)
+		}
+	}
+	return nil
+}
+
+func validateParams(params types.Params) error {
+	if params.BondDenom == "" {
+		return fmt.Errorf("staking parameter BondDenom can't be empty")
+	}
+	if params.InflationMin.GT(params.InflationMax) {
+		return fmt.Errorf("staking parameter InflationMin %v is greater than InflationMax %v",
+			params.InflationMin, params.InflationMax)
+	}
+	if params.UnbondingTime < 0 {
+		return fmt.Errorf("staking parameter UnbondingTime can't be negative")
+	}
+	return nil
+}
+
+// validateBalance checks the balances of pending unbondings and redelegations
+func validateBalance(params types.Params, initial, balance sdk.Coin) error {
+	if initial.Denom != params.BondDenom || balance.Denom != params.BondDenom {
+		return fmt.Errorf("balance must be in %v", params.BondDenom)
+	}
+	if !balance.IsNotNegative() || !initial.IsGTE(balance) {
+		return fmt.Errorf("balance %v must be between 0 and the initial balance %v", balance, initial)
+	}
+	return nil
+}
+
+// genesisStateV019 - the stake genesis state of v0.19, frozen so the
+// migration keeps decoding it as the types of the module change
+type genesisStateV019 struct {
+	Pool       poolV019           `json:"pool"`
+	Params     types.Params       `json:"params"`
+	Validators []types.Validator  `json:"validators"`
+	Bonds      []types.Delegation `json:"bonds"`
+}
+
+// poolV019 - the pool of v0.19, before loose_unbonded_tokens was renamed
+type poolV019 struct {
+	LooseUnbondedTokens     int64   `json:"loose_unbonded_tokens"`
+	UnbondedTokens          int64   `json:"unbonded_tokens"`
+	UnbondingTokens         int64   `json:"unbonding_tokens"`
+	BondedTokens            int64   `json:"bonded_tokens"`
+	UnbondedShares          sdk.Rat `json:"unbonded_shares"`
+	UnbondingShares         sdk.Rat `json:"unbonding_shares"`
+	BondedShares            sdk.Rat `json:"bonded_shares"`
+	InflationLastTime       int64   `json:"inflation_last_time"`
+	Inflation               sdk.Rat `json:"inflation"`
+	DateLastCommissionReset int64   `json:"date_last_commission_reset"`
+	PrevBondedShares        sdk.Rat `json:"prev_bonded_shares"`
+}
+
+// MigrateGenesisV020 - convert the genesis state of v0.19, which held no
+// unbonding delegations and redelegations, to the format of v0.20
+func MigrateGenesisV020(cdc *wire.Codec, state json.RawMessage) (json.RawMessage, error) {
+	var old genesisStateV019
+	err := cdc.UnmarshalJSON(state, &old)
+	if err != nil {
+		return nil, err
+	}
+	data := types.GenesisState{
+		Pool: types.Pool{
+			LooseTokens:             old.Pool.LooseUnbondedTokens,
+			UnbondedTokens:          old.Pool.UnbondedTokens,
+			UnbondingTokens:         old.Pool.UnbondingTokens,
+			BondedTokens:            old.Pool.BondedTokens,
+			UnbondedShares:          old.Pool.UnbondedShares,
+			UnbondingShares:         old.Pool.UnbondingShares,
+			BondedShares:            old.Pool.BondedShares,
+			InflationLastTime:       old.Pool.InflationLastTime,
+			Inflation:               old.Pool.Inflation,
+			DateLastCommissionReset: old.Pool.DateLastCommissionReset,
+			PrevBondedShares:        old.Pool.PrevBondedShares,
+		},
+		Params:               old.Params,
+		Validators:           old.Validators,
+		Bonds:                old.Bonds,
+		UnbondingDelegations: []types.UnbondingDelegation{},
+		Redelegations:        []types.Redelegation{},
+	}
+	if data.Validators == nil {
+		data.Validators = []types.Validator{}
+	}
+	if data.Bonds == nil {
+		data.Bonds = []types.Delegation{}
+	}
+	return wire.MarshalJSONIndent(cdc, data)
+}
@@ -0,0 +1,151 @@
+package stake
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/tepleton/tepleton-sdk/types"
+	"github.com/tepleton/tepleton-sdk/wire"
+	"github.com/tepleton/tepleton-sdk/x/stake/keeper"
+	"github.com/tepleton/tepleton-sdk/x/stake/types"
+)
+
+func genesisWithValidators(t *testing.T) types.GenesisState {
+	genesis := types.DefaultGenesisState()
+	pool := genesis.Pool
+	amts := []int64{9, 8}
+	for i, amt := range amts {
+		validator := types.NewValidator(keeper.Addrs[10+i], keeper.PKs[i], types.Description{})
+		validator, pool, _ = validator.AddTokensFromDel(pool, amt)
+		genesis.Validators = append(genesis.Validators, validator)
+		genesis.Bonds = append(genesis.Bonds, types.Delegation{
+			DelegatorAddr: keeper.Addrs[i],
+			ValidatorAddr: validator.Owner,
+			Shares:        sdk.NewRat(amt),
+		})
+	}
+	genesis.Pool = pool
+	require.Nil(t, ValidateGenesis(genesis))
+	return genesis
+}
+
+func TestInitGenesisExportsPendingUnbonding(t *testing.T) {
+	ctx, _, k := keeper.CreateTestInput(t, false, 0)
+	genesis := genesisWithValidators(t)
+	denom := genesis.Params.BondDenom
+	genesis.UnbondingDelegations = []types.UnbondingDelegation{{
+		DelegatorAddr:  keeper.Addrs[0],
+		ValidatorAddr:  keeper.Addrs[10],
+		CreationHeight: 3,
+		MinTime:        100,
+		InitialBalance: sdk.Coin{denom, 5},
+		Balance:        sdk.Coin{denom, 4},
+	}}
+	genesis.Redelegations = []types.Redelegation{{
+		DelegatorAddr:    keeper.Addrs[1],
+		ValidatorSrcAddr: keeper.Addrs[11],
+		ValidatorDstAddr: keeper.Addrs[10],
+		CreationHeight:   3,
+		MinTime:          100,
+		InitialBalance:   sdk.Coin{denom, 2},
+		Balance:          sdk.Coin{denom, 2},
+		SharesSrc:        sdk.NewRat(2),
+		SharesDst:        sdk.NewRat(2),
+	}}
+
+	err := InitGenesis(ctx, k, genesis)
+	require.Nil(t, err)
+
+	exported := WriteGenesis(ctx, k)
+	require.Equal(t, len(genesis.Validators), len(exported.Validators))
+	require.Equal(t, len(genesis.Bonds), len(exported.Bonds))
+	require.Equal(t, 1, len(exported.UnbondingDelegations))
+	require.True(t, genesis.UnbondingDelegations[0].Equal(exported.UnbondingDelegations[0]))
+	require.Equal(t, 1, len(exported.Redelegations))
+	require.True(t, genesis.Redelegations[0].Equal(exported.Redelegations[0]))
+
+	// the secondary indexes are restored too
+	ubds := k.GetUnbondingDelegationsFromValidator(ctx, keeper.Addrs[10])
+	require.Equal(t, 1, len(ubds))
+	reds := k.GetRedelegationsFromValidator(ctx, keeper.Addrs[11])
+	require.Equal(t, 1, len(reds))
+
+	// the exported state is itself a valid genesis
+	require.Nil(t, ValidateGenesis(exported))
+}
+
+func TestValidateGenesis(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*types.GenesisState)
+	}{
+		{"duplicate validator", func(g *types.GenesisState) {
+			g.Validators = append(g.Validators, g.Validators[0])
+		}},
+		{"validator without owner", func(g *types.GenesisState) {
+			g.Validators[0].Owner = nil
+		}},
+		{"validator without pubkey", func(g *types.GenesisState) {
+			g.Validators[0].PubKey = nil
+		}},
+		{"pool shares mismatch", func(g *types.GenesisState) {
+			g.Pool.UnbondedShares = g.Pool.UnbondedShares.Add(sdk.OneRat())
+		}},
+		{"pool tokens without validator", func(g *types.GenesisState) {
+			g.Pool.UnbondingTokens += 10
+		}},
+		{"delegator shares mismatch", func(g *types.GenesisState) {
+			g.Bonds[0].Shares = sdk.NewRat(1)
+		}},
+		{"delegation to unknown validator", func(g *types.GenesisState) {
+			g.Bonds[0].ValidatorAddr = keeper.Addrs[50]
+		}},
+		{"negative unbonding balance", func(g *types.GenesisState) {
+			g.UnbondingDelegations = []types.UnbondingDelegation{{
+				DelegatorAddr:  keeper.Addrs[0],
+				ValidatorAddr:  keeper.Addrs[10],
+				InitialBalance: sdk.Coin{g.Params.BondDenom, 5},
+				Balance:        sdk.Coin{g.Params.BondDenom, -1},
+			}}
+		}},
+		{"redelegation in wrong denom", func(g *types.GenesisState) {
+			g.Redelegations = []types.Redelegation{{
+				DelegatorAddr:    keeper.Addrs[0],
+				ValidatorSrcAddr: keeper.Addrs[11],
+				ValidatorDstAddr: keeper.Addrs[10],
+				InitialBalance:   sdk.Coin{"fake", 5},
+				Balance:          sdk.Coin{"fake", 5},
+			}}
+		}},
+		{"inflation bounds", func(g *types.GenesisState) {
+			g.Params.InflationMin = g.Params.InflationMax.Add(sdk.OneRat())
+		}},
+	}
+
+	for _, tc := range tests {
+		genesis := genesisWithValidators(t)
+		tc.mutate(&genesis)
+		require.NotNil(t, ValidateGenesis(genesis), tc.name)
+	}
+}
+
+func TestMigrateGenesisV020(t *testing.T) {
+	cdc := wire.NewCodec()
+	wire.RegisterCrypto(cdc)
+	state := json.RawMessage(`{
+		"pool": {"loose_unbonded_tokens": 100, "bonded_tokens": 0, "inflation_last_time": 0},
+		"params": {"max_validators": 100, "bond_denom": "steak"}
+	}`)
+
+	migrated, err := MigrateGenesisV020(cdc, state)
+	require.Nil(t, err)
+	var data types.GenesisState
+	err = cdc.UnmarshalJSON(migrated, &data)
+	require.Nil(t, err)
+	require.Equal(t, int64(100), data.Pool.LooseTokens)
+	require.Equal(t, "steak", data.Params.BondDenom)
+	require.NotNil(t, data.UnbondingDelegations)
+	require.NotNil(t, data.Redelegations)
+}
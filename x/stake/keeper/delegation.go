@@ -95,6 +95,19 @@ func (k Keeper) GetUnbondingDelegation(ctx sdk.Context,
 	return ubd, true
 }
 
+// load all unbonding delegations used during genesis dump
+func (k Keeper) GetAllUnbondingDelegations(ctx sdk.Context) (ubds []types.UnbondingDelegation) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, UnbondingDelegationKey)
+	for ; iterator.Valid(); iterator.Next() {
+		var ubd types.UnbondingDelegation
+		k.cdc.MustUnmarshalBinary(iterator.Value(), &ubd)
+		ubds = append(ubds, ubd)
+	}
+	iterator.Close()
+	return ubds
+}
+
 // load all unbonding delegations from a particular validator
 func (k Keeper) GetUnbondingDelegationsFromValidator(ctx sdk.Context, valAddr sdk.Address) (unbondingDelegations []types.UnbondingDelegation) {
 	store := ctx.KVStore(k.storeKey)
@@ -149,6 +162,19 @@ func (k Keeper) GetRedelegation(ctx sdk.Context,
 	return red, true
 }
 
+// load all redelegations used during genesis dump
+func (k Keeper) GetAllRedelegations(ctx sdk.Context) (reds []types.Redelegation) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, RedelegationKey)
+	for ; iterator.Valid(); iterator.Next() {
+		var red types.Redelegation
+		k.cdc.MustUnmarshalBinary(iterator.Value(), &red)
+		reds = append(reds, red)
+	}
+	iterator.Close()
+	return reds
+}
+
 // load all redelegations from a particular validator
 func (k Keeper) GetRedelegationsFromValidator(ctx sdk.Context, valAddr sdk.Address) (redelegations []types.Redelegation) {
 	store := ctx.KVStore(k.storeKey)
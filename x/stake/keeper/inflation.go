@@ -12,7 +12,8 @@ const (
 
 var hrsPerYrRat = sdk.NewRat(hrsPerYr)
 
-// process provisions for an hour period
+// process provisions for an hour period, the provisions add to the supply
+// of the bond denomination tracked by the coin keeper
 func (k Keeper) ProcessProvisions(ctx sdk.Context) types.Pool {
 
 	pool := k.GetPool(ctx)
@@ -22,6 +23,8 @@ func (k Keeper) ProcessProvisions(ctx sdk.Context) types.Pool {
 
 	// TODO add to the fees provisions
 	pool.LooseTokens += provisions
+	pool.Provisions += provisions
+	k.coinKeeper.IncreaseSupply(ctx, sdk.Coins{{k.GetParams(ctx).BondDenom, provisions}})
 	return pool
 }
 
@@ -0,0 +1,34 @@
+package keeper
+
+import (
+	sdk "github.com/tepleton/tepleton-sdk/types"
+	"github.com/tepleton/tepleton-sdk/x/stake/types"
+)
+
+// register the invariants of the staking state
+func RegisterInvariants(ir sdk.InvariantRegistry, k Keeper) {
+	ir.RegisterInvariant("stake/pool-shares", PoolSharesInvariant(k))
+	ir.RegisterInvariant("stake/pool-tokens", PoolTokensInvariant(k))
+	ir.RegisterInvariant("stake/delegator-shares", DelegatorSharesInvariant(k))
+}
+
+// the shares of the pool equal the pool shares of all validators
+func PoolSharesInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) error {
+		return types.CheckPoolShares(k.GetPool(ctx), k.GetAllValidators(ctx))
+	}
+}
+
+// the tokens of the pool equal the tokens of all validators
+func PoolTokensInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) error {
+		return types.CheckPoolTokens(k.GetPool(ctx), k.GetAllValidators(ctx))
+	}
+}
+
+// the delegator shares of every validator equal the shares of its delegations
+func DelegatorSharesInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) error {
+		return types.CheckDelegatorShares(k.GetAllValidators(ctx), k.GetAllDelegations(ctx))
+	}
+}
@@ -0,0 +1,41 @@
+package keeper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/tepleton/tepleton-sdk/types"
+	"github.com/tepleton/tepleton-sdk/x/stake/types"
+)
+
+func TestInvariants(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 10)
+	pool := keeper.GetPool(ctx)
+
+	validator := types.NewValidator(addrVals[0], PKs[0], types.Description{})
+	validator, pool, shares := validator.AddTokensFromDel(pool, 10)
+	keeper.SetPool(ctx, pool)
+	validator = keeper.UpdateValidator(ctx, validator)
+	keeper.SetDelegation(ctx, types.Delegation{
+		DelegatorAddr: addrDels[0],
+		ValidatorAddr: addrVals[0],
+		Shares:        shares,
+	})
+	require.Nil(t, PoolSharesInvariant(keeper)(ctx))
+	require.Nil(t, DelegatorSharesInvariant(keeper)(ctx))
+
+	// delegations not backed by the validator
+	keeper.SetDelegation(ctx, types.Delegation{
+		DelegatorAddr: addrDels[1],
+		ValidatorAddr: addrVals[0],
+		Shares:        sdk.NewRat(5),
+	})
+	require.NotNil(t, DelegatorSharesInvariant(keeper)(ctx))
+
+	// tokens of the pool not held by any validator
+	pool = keeper.GetPool(ctx)
+	pool.BondedShares = pool.BondedShares.Add(sdk.OneRat())
+	keeper.SetPool(ctx, pool)
+	require.NotNil(t, PoolSharesInvariant(keeper)(ctx))
+}
@@ -88,7 +88,7 @@ func (k Keeper) Slash(ctx sdk.Context, pubkey crypto.PubKey, infractionHeight in
 	// remove shares from the validator
 	validator, pool, burned := validator.RemovePoolShares(pool, sdk.NewRatFromInt(sharesToRemove))
 	// burn tokens
-	pool.LooseTokens -= burned
+	pool = k.burnTokens(ctx, pool, burned)
 	// update the pool
 	k.SetPool(ctx, pool)
 	// update the validator, possibly kicking it out
@@ -168,7 +168,7 @@ func (k Keeper) slashUnbondingDelegation(ctx sdk.Context, unbondingDelegation ty
 		pool := k.GetPool(ctx)
 		// Burn loose tokens
 		// Ref https://github.com/tepleton/tepleton-sdk/pull/1278#discussion_r198657760
-		pool.LooseTokens -= slashAmount.Int64()
+		pool = k.burnTokens(ctx, pool, slashAmount.Int64())
 		k.SetPool(ctx, pool)
 	}
 
@@ -229,9 +229,20 @@ func (k Keeper) slashRedelegation(ctx sdk.Context, validator types.Validator, re
 		}
 		// Burn loose tokens
 		pool := k.GetPool(ctx)
-		pool.LooseTokens -= tokensToBurn
+		pool = k.burnTokens(ctx, pool, tokensToBurn)
 		k.SetPool(ctx, pool)
 	}
 
 	return slashAmount
 }
+
+// burn loose tokens of the pool, removing them from the supply of the bond
+// denomination tracked by the coin keeper
+func (k Keeper) burnTokens(ctx sdk.Context, pool types.Pool, amount int64) types.Pool {
+	pool.LooseTokens -= amount
+	err := k.coinKeeper.DecreaseSupply(ctx, sdk.Coins{{k.GetParams(ctx).BondDenom, amount}})
+	if err != nil {
+		panic(fmt.Sprintf("sanity check: burned tokens exceed the supply: %v", err))
+	}
+	return pool
+}
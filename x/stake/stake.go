@@ -61,6 +61,10 @@ var (
 	GetREDsToValDstIndexKey      = keeper.GetREDsToValDstIndexKey
 	GetREDsByDelToValDstIndexKey = keeper.GetREDsByDelToValDstIndexKey
 
+	RegisterInvariants       = keeper.RegisterInvariants
+	PoolSharesInvariant      = keeper.PoolSharesInvariant
+	DelegatorSharesInvariant = keeper.DelegatorSharesInvariant
+
 	DefaultParams       = types.DefaultParams
 	InitialPool         = types.InitialPool
 	NewUnbondedShares   = types.NewUnbondedShares
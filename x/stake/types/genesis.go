@@ -2,10 +2,12 @@ package types
 
 // GenesisState - all staking state that must be provided at genesis
 type GenesisState struct {
-	Pool       Pool         `json:"pool"`
-	Params     Params       `json:"params"`
-	Validators []Validator  `json:"validators"`
-	Bonds      []Delegation `json:"bonds"`
+	Pool                 Pool                  `json:"pool"`
+	Params               Params                `json:"params"`
+	Validators           []Validator           `json:"validators"`
+	Bonds                []Delegation          `json:"bonds"`
+	UnbondingDelegations []UnbondingDelegation `json:"unbonding_delegations"`
+	Redelegations        []Redelegation        `json:"redelegations"`
 }
 
 func NewGenesisState(pool Pool, params Params, validators []Validator, bonds []Delegation) GenesisState {
@@ -0,0 +1,85 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/tepleton/tepleton-sdk/types"
+)
+
+// CheckPoolShares checks that the shares of the pool equal the sum of the
+// pool shares of the validators, and that the pool holds no negative tokens
+func CheckPoolShares(pool Pool, validators []Validator) error {
+	bonded, unbonding, unbonded := sdk.ZeroRat(), sdk.ZeroRat(), sdk.ZeroRat()
+	for _, validator := range validators {
+		bonded = bonded.Add(validator.PoolShares.Bonded())
+		unbonding = unbonding.Add(validator.PoolShares.Unbonding())
+		unbonded = unbonded.Add(validator.PoolShares.Unbonded())
+	}
+
+	// the tokens of the validators are their shares of the pools
+	if !bonded.Equal(pool.BondedShares) {
+		return fmt.Errorf("bonded shares of the pool %v don't equal the sum of the validators %v", pool.BondedShares, bonded)
+	}
+	if !unbonding.Equal(pool.UnbondingShares) {
+		return fmt.Errorf("unbonding shares of the pool %v don't equal the sum of the validators %v", pool.UnbondingShares, unbonding)
+	}
+	if !unbonded.Equal(pool.UnbondedShares) {
+		return fmt.Errorf("unbonded shares of the pool %v don't equal the sum of the validators %v", pool.UnbondedShares, unbonded)
+	}
+	if pool.LooseTokens < 0 || pool.BondedTokens < 0 || pool.UnbondingTokens < 0 || pool.UnbondedTokens < 0 {
+		return fmt.Errorf("pool has negative tokens")
+	}
+	return nil
+}
+
+// CheckPoolTokens checks that the bonded, unbonding and unbonded tokens of
+// the pool equal the sum of the tokens of the validators in each state
+func CheckPoolTokens(pool Pool, validators []Validator) error {
+	bonded, unbonding, unbonded := sdk.ZeroRat(), sdk.ZeroRat(), sdk.ZeroRat()
+	for _, validator := range validators {
+		tokens := validator.PoolShares.Tokens(pool)
+		switch validator.PoolShares.Status {
+		case sdk.Bonded:
+			bonded = bonded.Add(tokens)
+		case sdk.Unbonding:
+			unbonding = unbonding.Add(tokens)
+		case sdk.Unbonded:
+			unbonded = unbonded.Add(tokens)
+		}
+	}
+
+	if !bonded.Equal(sdk.NewRat(pool.BondedTokens)) {
+		return fmt.Errorf("bonded tokens of the pool %v don't equal the sum of the validators %v", pool.BondedTokens, bonded)
+	}
+	if !unbonding.Equal(sdk.NewRat(pool.UnbondingTokens)) {
+		return fmt.Errorf("unbonding tokens of the pool %v don't equal the sum of the validators %v", pool.UnbondingTokens, unbonding)
+	}
+	if !unbonded.Equal(sdk.NewRat(pool.UnbondedTokens)) {
+		return fmt.Errorf("unbonded tokens of the pool %v don't equal the sum of the validators %v", pool.UnbondedTokens, unbonded)
+	}
+	return nil
+}
+
+// CheckDelegatorShares checks that the delegator shares of every validator
+// equal the sum of the shares of its delegations
+func CheckDelegatorShares(validators []Validator, delegations []Delegation) error {
+	sums := make(map[string]sdk.Rat, len(validators))
+	for _, validator := range validators {
+		sums[string(validator.Owner)] = sdk.ZeroRat()
+	}
+	for _, delegation := range delegations {
+		sum, ok := sums[string(delegation.ValidatorAddr)]
+		if !ok {
+			return fmt.Errorf("delegation of %v to unknown validator %v", delegation.DelegatorAddr, delegation.ValidatorAddr)
+		}
+		sums[string(delegation.ValidatorAddr)] = sum.Add(delegation.Shares)
+	}
+	for _, validator := range validators {
+		sum := sums[string(validator.Owner)]
+		if !sum.Equal(validator.DelegatorShares) {
+			return fmt.Errorf("delegator shares of validator %v %v don't equal the sum of its delegations %v",
+				validator.Owner, validator.DelegatorShares, sum)
+		}
+	}
+	return nil
+}
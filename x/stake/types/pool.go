@@ -13,6 +13,7 @@ type Pool struct {
 	UnbondedTokens    int64   `json:"unbonded_tokens"`     // reserve of unbonded tokens held with validators
 	UnbondingTokens   int64   `json:"unbonding_tokens"`    // tokens moving from bonded to unbonded pool
 	BondedTokens      int64   `json:"bonded_tokens"`       // reserve of bonded tokens
+	Provisions        int64   `json:"provisions"`          // provisions in the loose tokens, which no account holds yet
 	UnbondedShares    sdk.Rat `json:"unbonded_shares"`     // sum of all shares distributed for the Unbonded Pool
 	UnbondingShares   sdk.Rat `json:"unbonding_shares"`    // shares moving from Bonded to Unbonded Pool
 	BondedShares      sdk.Rat `json:"bonded_shares"`       // sum of all shares distributed for the Bonded Pool
@@ -37,6 +38,7 @@ func InitialPool() Pool {
 	return Pool{
 		LooseTokens:             0,
 		BondedTokens:            0,
+		Provisions:              0,
 		UnbondingTokens:         0,
 		UnbondedTokens:          0,
 		BondedShares:            sdk.ZeroRat(),